@@ -0,0 +1,9 @@
+CREATE TABLE "password_history" (
+  "id" bigserial PRIMARY KEY,
+  "username" varchar NOT NULL,
+  "hashed_password" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  FOREIGN KEY ("username") REFERENCES "users" ("username") ON DELETE CASCADE
+);
+
+CREATE INDEX ON "password_history" ("username", "created_at");
@@ -0,0 +1,10 @@
+CREATE TABLE "user_identities" (
+  "issuer" varchar NOT NULL,
+  "subject" varchar NOT NULL,
+  "username" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("issuer", "subject"),
+  FOREIGN KEY ("username") REFERENCES "users" ("username") ON DELETE CASCADE
+);
+
+CREATE INDEX ON "user_identities" ("username");
@@ -0,0 +1,13 @@
+CREATE TABLE "invitations" (
+  "code" varchar PRIMARY KEY,
+  "email" varchar NOT NULL,
+  "role" varchar NOT NULL DEFAULT 'user',
+  "created_by" varchar NOT NULL,
+  "used_by" varchar NOT NULL DEFAULT '',
+  "expires_at" timestamptz NOT NULL,
+  "used_at" timestamptz NOT NULL DEFAULT '0001-01-01',
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  FOREIGN KEY ("created_by") REFERENCES "users" ("username") ON DELETE CASCADE
+);
+
+CREATE INDEX ON "invitations" ("email");
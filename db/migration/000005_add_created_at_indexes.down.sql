@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS "project_created_at_id_idx";
+DROP INDEX IF EXISTS "income_created_at_id_idx";
+DROP INDEX IF EXISTS "loan_created_at_id_idx";
+DROP INDEX IF EXISTS "pay_out_created_at_id_idx";
@@ -0,0 +1,5 @@
+-- List and search queries are ordered by created_at, id and paginated with keyset cursors
+CREATE INDEX "project_created_at_id_idx" ON "project" ("created_at", "id");
+CREATE INDEX "income_created_at_id_idx" ON "income" ("created_at", "id");
+CREATE INDEX "loan_created_at_id_idx" ON "loan" ("created_at", "id");
+CREATE INDEX "pay_out_created_at_id_idx" ON "pay_out" ("created_at", "id");
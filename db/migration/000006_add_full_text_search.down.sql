@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS "project_search_idx";
+DROP INDEX IF EXISTS "income_search_idx";
+DROP INDEX IF EXISTS "loan_search_idx";
+DROP INDEX IF EXISTS "pay_out_search_idx";
+
+DROP INDEX IF EXISTS "project_name_trgm_idx";
+DROP INDEX IF EXISTS "income_payee_trgm_idx";
+DROP INDEX IF EXISTS "loan_borrower_trgm_idx";
+DROP INDEX IF EXISTS "pay_out_owner_trgm_idx";
+
+DROP EXTENSION IF EXISTS pg_trgm;
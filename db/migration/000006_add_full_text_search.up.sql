@@ -0,0 +1,14 @@
+-- Search matches names, descriptions and subjects with full-text search and names with trigram
+-- similarity. The tsvectors are indexed as expressions instead of stored columns so that SELECT *
+-- keeps returning the model columns, queries must repeat the indexed expressions to use the indexes.
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX "project_search_idx" ON "project" USING GIN (to_tsvector('simple', "name" || ' ' || "description"));
+CREATE INDEX "income_search_idx" ON "income" USING GIN (to_tsvector('simple', "payee"));
+CREATE INDEX "loan_search_idx" ON "loan" USING GIN (to_tsvector('simple', "borrower" || ' ' || "subject"));
+CREATE INDEX "pay_out_search_idx" ON "pay_out" USING GIN (to_tsvector('simple', "owner" || ' ' || "subject"));
+
+CREATE INDEX "project_name_trgm_idx" ON "project" USING GIN ("name" gin_trgm_ops);
+CREATE INDEX "income_payee_trgm_idx" ON "income" USING GIN ("payee" gin_trgm_ops);
+CREATE INDEX "loan_borrower_trgm_idx" ON "loan" USING GIN ("borrower" gin_trgm_ops);
+CREATE INDEX "pay_out_owner_trgm_idx" ON "pay_out" USING GIN ("owner" gin_trgm_ops);
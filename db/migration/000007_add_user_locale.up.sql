@@ -0,0 +1,2 @@
+-- An empty locale follows the Accept-Language header of each request
+ALTER TABLE "users" ADD COLUMN "locale" varchar NOT NULL DEFAULT '';
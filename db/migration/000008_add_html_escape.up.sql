@@ -0,0 +1,9 @@
+-- Search snippets wrap the matches in <mark></mark>, the record text they quote is escaped
+-- first so that the snippets are safe to render as HTML. The parser reads the entities as
+-- single tokens, which the simple configuration does not index, so they are never highlighted.
+CREATE FUNCTION html_escape(t text) RETURNS text
+    LANGUAGE sql IMMUTABLE STRICT PARALLEL SAFE
+    AS $$
+        SELECT replace(replace(replace(replace(replace(t,
+            '&', '&amp;'), '<', '&lt;'), '>', '&gt;'), '"', '&quot;'), '''', '&#39;')
+    $$;
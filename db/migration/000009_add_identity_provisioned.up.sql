@@ -0,0 +1,8 @@
+-- Only the users provisioned by the OIDC login have their role synced from the identity
+-- provider groups. The user and the identity of a provisioned user are created in the
+-- same transaction, so they share the same created_at.
+ALTER TABLE "user_identities" ADD COLUMN "provisioned" boolean NOT NULL DEFAULT false;
+
+UPDATE "user_identities" AS i SET "provisioned" = true
+FROM "users" AS u
+WHERE u."username" = i."username" AND u."created_at" = i."created_at";
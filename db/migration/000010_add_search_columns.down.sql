@@ -0,0 +1,9 @@
+ALTER TABLE "project" DROP COLUMN IF EXISTS "search";
+ALTER TABLE "income" DROP COLUMN IF EXISTS "search";
+ALTER TABLE "loan" DROP COLUMN IF EXISTS "search";
+ALTER TABLE "pay_out" DROP COLUMN IF EXISTS "search";
+
+CREATE INDEX "project_search_idx" ON "project" USING GIN (to_tsvector('simple', "name" || ' ' || "description"));
+CREATE INDEX "income_search_idx" ON "income" USING GIN (to_tsvector('simple', "payee"));
+CREATE INDEX "loan_search_idx" ON "loan" USING GIN (to_tsvector('simple', "borrower" || ' ' || "subject"));
+CREATE INDEX "pay_out_search_idx" ON "pay_out" USING GIN (to_tsvector('simple', "owner" || ' ' || "subject"));
@@ -0,0 +1,18 @@
+-- The tsvectors searched by the search queries are stored in generated columns, so the queries
+-- and the indexes read the same values. Search results are ordered by rank, and the cursor
+-- queries compute the rank of the cursor row again: a cursor whose row no longer matches the
+-- query selects no rows.
+ALTER TABLE "project" ADD COLUMN "search" tsvector GENERATED ALWAYS AS (to_tsvector('simple', "name" || ' ' || "description")) STORED;
+ALTER TABLE "income" ADD COLUMN "search" tsvector GENERATED ALWAYS AS (to_tsvector('simple', "payee")) STORED;
+ALTER TABLE "loan" ADD COLUMN "search" tsvector GENERATED ALWAYS AS (to_tsvector('simple', "borrower" || ' ' || "subject")) STORED;
+ALTER TABLE "pay_out" ADD COLUMN "search" tsvector GENERATED ALWAYS AS (to_tsvector('simple', "owner" || ' ' || "subject")) STORED;
+
+DROP INDEX IF EXISTS "project_search_idx";
+DROP INDEX IF EXISTS "income_search_idx";
+DROP INDEX IF EXISTS "loan_search_idx";
+DROP INDEX IF EXISTS "pay_out_search_idx";
+
+CREATE INDEX "project_search_idx" ON "project" USING GIN ("search");
+CREATE INDEX "income_search_idx" ON "income" USING GIN ("search");
+CREATE INDEX "loan_search_idx" ON "loan" USING GIN ("search");
+CREATE INDEX "pay_out_search_idx" ON "pay_out" USING GIN ("search");
@@ -0,0 +1,9 @@
+// Package migration embeds the SQL schema migrations into the binary
+package migration
+
+import "embed"
+
+// FS holds the *.up.sql and *.down.sql migration files
+//
+//go:embed *.sql
+var FS embed.FS
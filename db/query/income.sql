@@ -1,14 +1,71 @@
+-- name: CountIncomes :one
+SELECT count(*) FROM income;
+
+-- name: CountSearchIncomes :one
+SELECT count(*) FROM income
+WHERE search @@ websearch_to_tsquery('simple', sqlc.arg(query)) OR sqlc.arg(query) <% payee;
+
 -- name: CreateIncome :one
 INSERT INTO income (payee, amount, project_id) VALUES ($1, $2, $3) RETURNING *;
 
 -- name: ListIncomes :many
-SELECT * FROM income ORDER BY id OFFSET $1 LIMIT $2;
+SELECT * FROM income ORDER BY created_at, id OFFSET $1 LIMIT $2;
+
+-- name: ListIncomesAfter :many
+SELECT * FROM income
+WHERE (created_at, id) > (sqlc.arg(created_at)::timestamptz, sqlc.arg(id)::uuid)
+ORDER BY created_at, id
+LIMIT sqlc.arg('limit');
+
+-- name: ListIncomesBefore :many
+SELECT * FROM income
+WHERE (created_at, id) < (sqlc.arg(created_at)::timestamptz, sqlc.arg(id)::uuid)
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg('limit');
 
 -- name: GetIncome :one
 SELECT * FROM income WHERE id = $1;
 
 -- name: SearchIncomes :many
-SELECT * FROM income WHERE payee ILIKE $1 ORDER BY id OFFSET $2 LIMIT $3;
+WITH q AS (
+    SELECT websearch_to_tsquery('simple', sqlc.arg(query)::text) AS tsq
+), hits AS (
+    SELECT id, payee, amount, project_id, created_at, updated_at,
+        (ts_rank(search, q.tsq) + word_similarity(sqlc.arg(query), payee))::real AS rank
+    FROM income, q
+    WHERE search @@ q.tsq OR sqlc.arg(query) <% payee
+)
+SELECT id, payee, amount, project_id, created_at, updated_at FROM hits
+ORDER BY rank DESC, created_at, id
+OFFSET sqlc.arg('offset') LIMIT sqlc.arg('limit');
+
+-- name: SearchIncomesAfter :many
+WITH q AS (
+    SELECT websearch_to_tsquery('simple', sqlc.arg(query)::text) AS tsq
+), hits AS (
+    SELECT id, payee, amount, project_id, created_at, updated_at,
+        (ts_rank(search, q.tsq) + word_similarity(sqlc.arg(query), payee))::real AS rank
+    FROM income, q
+    WHERE search @@ q.tsq OR sqlc.arg(query) <% payee
+)
+SELECT id, payee, amount, project_id, created_at, updated_at FROM hits
+WHERE (-rank, created_at, id) > (SELECT -rank, sqlc.arg(created_at)::timestamptz, sqlc.arg(id)::uuid FROM hits WHERE id = sqlc.arg(id)::uuid)
+ORDER BY rank DESC, created_at, id
+LIMIT sqlc.arg('limit');
+
+-- name: SearchIncomesBefore :many
+WITH q AS (
+    SELECT websearch_to_tsquery('simple', sqlc.arg(query)::text) AS tsq
+), hits AS (
+    SELECT id, payee, amount, project_id, created_at, updated_at,
+        (ts_rank(search, q.tsq) + word_similarity(sqlc.arg(query), payee))::real AS rank
+    FROM income, q
+    WHERE search @@ q.tsq OR sqlc.arg(query) <% payee
+)
+SELECT id, payee, amount, project_id, created_at, updated_at FROM hits
+WHERE (-rank, created_at, id) < (SELECT -rank, sqlc.arg(created_at)::timestamptz, sqlc.arg(id)::uuid FROM hits WHERE id = sqlc.arg(id)::uuid)
+ORDER BY rank, created_at DESC, id DESC
+LIMIT sqlc.arg('limit');
 
 -- name: DeleteIncome :one
 DELETE FROM income WHERE id = $1 RETURNING *;
\ No newline at end of file
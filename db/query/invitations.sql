@@ -0,0 +1,7 @@
+-- name: CreateInvitation :one
+INSERT INTO invitations (code, email, role, created_by, expires_at) VALUES ($1, $2, $3, $4, $5) RETURNING *;
+
+-- name: ConsumeInvitation :one
+UPDATE invitations SET used_by = $3, used_at = now()
+WHERE code = $1 AND email = $2 AND used_at = '0001-01-01' AND expires_at > now()
+RETURNING *;
@@ -1,14 +1,71 @@
+-- name: CountLoans :one
+SELECT count(*) FROM loan;
+
+-- name: CountSearchLoans :one
+SELECT count(*) FROM loan
+WHERE search @@ websearch_to_tsquery('simple', sqlc.arg(query)) OR sqlc.arg(query) <% borrower;
+
 -- name: CreateLoan :one
 INSERT INTO loan (borrower, amount, subject) VALUES ($1, $2, $3) RETURNING *;
 
 -- name: ListLoans :many
-SELECT * FROM loan ORDER BY id OFFSET $1 LIMIT $2;
+SELECT * FROM loan ORDER BY created_at, id OFFSET $1 LIMIT $2;
+
+-- name: ListLoansAfter :many
+SELECT * FROM loan
+WHERE (created_at, id) > (sqlc.arg(created_at)::timestamptz, sqlc.arg(id)::uuid)
+ORDER BY created_at, id
+LIMIT sqlc.arg('limit');
+
+-- name: ListLoansBefore :many
+SELECT * FROM loan
+WHERE (created_at, id) < (sqlc.arg(created_at)::timestamptz, sqlc.arg(id)::uuid)
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg('limit');
 
 -- name: GetLoan :one
 SELECT * FROM loan WHERE id = $1;
 
 -- name: SearchLoans :many
-SELECT * FROM loan WHERE borrower ILIKE $1 ORDER BY id OFFSET $2 LIMIT $3;
+WITH q AS (
+    SELECT websearch_to_tsquery('simple', sqlc.arg(query)::text) AS tsq
+), hits AS (
+    SELECT id, borrower, amount, subject, created_at, updated_at,
+        (ts_rank(search, q.tsq) + word_similarity(sqlc.arg(query), borrower))::real AS rank
+    FROM loan, q
+    WHERE search @@ q.tsq OR sqlc.arg(query) <% borrower
+)
+SELECT id, borrower, amount, subject, created_at, updated_at FROM hits
+ORDER BY rank DESC, created_at, id
+OFFSET sqlc.arg('offset') LIMIT sqlc.arg('limit');
+
+-- name: SearchLoansAfter :many
+WITH q AS (
+    SELECT websearch_to_tsquery('simple', sqlc.arg(query)::text) AS tsq
+), hits AS (
+    SELECT id, borrower, amount, subject, created_at, updated_at,
+        (ts_rank(search, q.tsq) + word_similarity(sqlc.arg(query), borrower))::real AS rank
+    FROM loan, q
+    WHERE search @@ q.tsq OR sqlc.arg(query) <% borrower
+)
+SELECT id, borrower, amount, subject, created_at, updated_at FROM hits
+WHERE (-rank, created_at, id) > (SELECT -rank, sqlc.arg(created_at)::timestamptz, sqlc.arg(id)::uuid FROM hits WHERE id = sqlc.arg(id)::uuid)
+ORDER BY rank DESC, created_at, id
+LIMIT sqlc.arg('limit');
+
+-- name: SearchLoansBefore :many
+WITH q AS (
+    SELECT websearch_to_tsquery('simple', sqlc.arg(query)::text) AS tsq
+), hits AS (
+    SELECT id, borrower, amount, subject, created_at, updated_at,
+        (ts_rank(search, q.tsq) + word_similarity(sqlc.arg(query), borrower))::real AS rank
+    FROM loan, q
+    WHERE search @@ q.tsq OR sqlc.arg(query) <% borrower
+)
+SELECT id, borrower, amount, subject, created_at, updated_at FROM hits
+WHERE (-rank, created_at, id) < (SELECT -rank, sqlc.arg(created_at)::timestamptz, sqlc.arg(id)::uuid FROM hits WHERE id = sqlc.arg(id)::uuid)
+ORDER BY rank, created_at DESC, id DESC
+LIMIT sqlc.arg('limit');
 
 -- name: DeleteLoan :one
 DELETE FROM loan WHERE id = $1 RETURNING *;
\ No newline at end of file
@@ -0,0 +1,5 @@
+-- name: CreatePasswordHistory :one
+INSERT INTO password_history (username, hashed_password) VALUES ($1, $2) RETURNING *;
+
+-- name: ListPasswordHistory :many
+SELECT * FROM password_history WHERE username = $1 ORDER BY created_at DESC LIMIT $2;
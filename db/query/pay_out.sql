@@ -1,14 +1,71 @@
+-- name: CountPayOuts :one
+SELECT count(*) FROM pay_out;
+
+-- name: CountSearchPayOuts :one
+SELECT count(*) FROM pay_out
+WHERE search @@ websearch_to_tsquery('simple', sqlc.arg(query)) OR sqlc.arg(query) <% owner;
+
 -- name: CreatePayOut :one
 INSERT INTO pay_out (owner, amount, subject) VALUES ($1, $2, $3) RETURNING *;
 
 -- name: ListPayOuts :many
-SELECT * FROM pay_out ORDER BY id OFFSET $1 LIMIT $2;
+SELECT * FROM pay_out ORDER BY created_at, id OFFSET $1 LIMIT $2;
+
+-- name: ListPayOutsAfter :many
+SELECT * FROM pay_out
+WHERE (created_at, id) > (sqlc.arg(created_at)::timestamptz, sqlc.arg(id)::uuid)
+ORDER BY created_at, id
+LIMIT sqlc.arg('limit');
+
+-- name: ListPayOutsBefore :many
+SELECT * FROM pay_out
+WHERE (created_at, id) < (sqlc.arg(created_at)::timestamptz, sqlc.arg(id)::uuid)
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg('limit');
 
 -- name: GetPayOut :one
 SELECT * FROM pay_out WHERE id = $1;
 
 -- name: SearchPayOuts :many
-SELECT * FROM pay_out WHERE owner ILIKE $1 ORDER BY id OFFSET $2 LIMIT $3;
+WITH q AS (
+    SELECT websearch_to_tsquery('simple', sqlc.arg(query)::text) AS tsq
+), hits AS (
+    SELECT id, owner, amount, subject, created_at, updated_at,
+        (ts_rank(search, q.tsq) + word_similarity(sqlc.arg(query), owner))::real AS rank
+    FROM pay_out, q
+    WHERE search @@ q.tsq OR sqlc.arg(query) <% owner
+)
+SELECT id, owner, amount, subject, created_at, updated_at FROM hits
+ORDER BY rank DESC, created_at, id
+OFFSET sqlc.arg('offset') LIMIT sqlc.arg('limit');
+
+-- name: SearchPayOutsAfter :many
+WITH q AS (
+    SELECT websearch_to_tsquery('simple', sqlc.arg(query)::text) AS tsq
+), hits AS (
+    SELECT id, owner, amount, subject, created_at, updated_at,
+        (ts_rank(search, q.tsq) + word_similarity(sqlc.arg(query), owner))::real AS rank
+    FROM pay_out, q
+    WHERE search @@ q.tsq OR sqlc.arg(query) <% owner
+)
+SELECT id, owner, amount, subject, created_at, updated_at FROM hits
+WHERE (-rank, created_at, id) > (SELECT -rank, sqlc.arg(created_at)::timestamptz, sqlc.arg(id)::uuid FROM hits WHERE id = sqlc.arg(id)::uuid)
+ORDER BY rank DESC, created_at, id
+LIMIT sqlc.arg('limit');
+
+-- name: SearchPayOutsBefore :many
+WITH q AS (
+    SELECT websearch_to_tsquery('simple', sqlc.arg(query)::text) AS tsq
+), hits AS (
+    SELECT id, owner, amount, subject, created_at, updated_at,
+        (ts_rank(search, q.tsq) + word_similarity(sqlc.arg(query), owner))::real AS rank
+    FROM pay_out, q
+    WHERE search @@ q.tsq OR sqlc.arg(query) <% owner
+)
+SELECT id, owner, amount, subject, created_at, updated_at FROM hits
+WHERE (-rank, created_at, id) < (SELECT -rank, sqlc.arg(created_at)::timestamptz, sqlc.arg(id)::uuid FROM hits WHERE id = sqlc.arg(id)::uuid)
+ORDER BY rank, created_at DESC, id DESC
+LIMIT sqlc.arg('limit');
 
 -- name: DeletePayOut :one
 DELETE FROM pay_out WHERE id = $1 RETURNING *;
@@ -1,14 +1,71 @@
+-- name: CountProjects :one
+SELECT count(*) FROM project;
+
+-- name: CountSearchProjects :one
+SELECT count(*) FROM project
+WHERE search @@ websearch_to_tsquery('simple', sqlc.arg(query)) OR sqlc.arg(query) <% name;
+
 -- name: CreateProject :one
 INSERT INTO project (name, description, amount) VALUES ($1, $2, $3) RETURNING *;
 
 -- name: ListProjects :many
-SELECT * FROM project ORDER BY id OFFSET $1 LIMIT $2;
+SELECT * FROM project ORDER BY created_at, id OFFSET $1 LIMIT $2;
+
+-- name: ListProjectsAfter :many
+SELECT * FROM project
+WHERE (created_at, id) > (sqlc.arg(created_at)::timestamptz, sqlc.arg(id)::uuid)
+ORDER BY created_at, id
+LIMIT sqlc.arg('limit');
+
+-- name: ListProjectsBefore :many
+SELECT * FROM project
+WHERE (created_at, id) < (sqlc.arg(created_at)::timestamptz, sqlc.arg(id)::uuid)
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg('limit');
 
 -- name: GetProject :one
 SELECT * FROM project WHERE id = $1;
 
 -- name: SearchProjects :many
-SELECT * FROM project WHERE name ILIKE $1 ORDER BY id OFFSET $2 LIMIT $3;
+WITH q AS (
+    SELECT websearch_to_tsquery('simple', sqlc.arg(query)::text) AS tsq
+), hits AS (
+    SELECT id, name, amount, description, created_at, updated_at,
+        (ts_rank(search, q.tsq) + word_similarity(sqlc.arg(query), name))::real AS rank
+    FROM project, q
+    WHERE search @@ q.tsq OR sqlc.arg(query) <% name
+)
+SELECT id, name, amount, description, created_at, updated_at FROM hits
+ORDER BY rank DESC, created_at, id
+OFFSET sqlc.arg('offset') LIMIT sqlc.arg('limit');
+
+-- name: SearchProjectsAfter :many
+WITH q AS (
+    SELECT websearch_to_tsquery('simple', sqlc.arg(query)::text) AS tsq
+), hits AS (
+    SELECT id, name, amount, description, created_at, updated_at,
+        (ts_rank(search, q.tsq) + word_similarity(sqlc.arg(query), name))::real AS rank
+    FROM project, q
+    WHERE search @@ q.tsq OR sqlc.arg(query) <% name
+)
+SELECT id, name, amount, description, created_at, updated_at FROM hits
+WHERE (-rank, created_at, id) > (SELECT -rank, sqlc.arg(created_at)::timestamptz, sqlc.arg(id)::uuid FROM hits WHERE id = sqlc.arg(id)::uuid)
+ORDER BY rank DESC, created_at, id
+LIMIT sqlc.arg('limit');
+
+-- name: SearchProjectsBefore :many
+WITH q AS (
+    SELECT websearch_to_tsquery('simple', sqlc.arg(query)::text) AS tsq
+), hits AS (
+    SELECT id, name, amount, description, created_at, updated_at,
+        (ts_rank(search, q.tsq) + word_similarity(sqlc.arg(query), name))::real AS rank
+    FROM project, q
+    WHERE search @@ q.tsq OR sqlc.arg(query) <% name
+)
+SELECT id, name, amount, description, created_at, updated_at FROM hits
+WHERE (-rank, created_at, id) < (SELECT -rank, sqlc.arg(created_at)::timestamptz, sqlc.arg(id)::uuid FROM hits WHERE id = sqlc.arg(id)::uuid)
+ORDER BY rank, created_at DESC, id DESC
+LIMIT sqlc.arg('limit');
 
 -- name: DeleteProject :one
 DELETE FROM project WHERE id = $1 RETURNING *;
\ No newline at end of file
@@ -0,0 +1,36 @@
+-- name: SearchAll :many
+WITH q AS (
+    SELECT websearch_to_tsquery('simple', sqlc.arg(query)::text) AS tsq
+)
+SELECT hits.resource, hits.id, hits.title, hits.snippet, hits.amount, hits.created_at, hits.rank
+FROM (
+    SELECT 'project'::text AS resource, id, name AS title,
+        ts_headline('simple', html_escape(name || ' ' || description), q.tsq, 'StartSel=<mark>, StopSel=</mark>, MaxWords=20, MinWords=5') AS snippet,
+        amount, created_at,
+        (ts_rank(search, q.tsq) + word_similarity(sqlc.arg(query), name))::real AS rank
+    FROM project, q
+    WHERE search @@ q.tsq OR sqlc.arg(query) <% name
+    UNION ALL
+    SELECT 'income'::text, id, payee,
+        ts_headline('simple', html_escape(payee), q.tsq, 'StartSel=<mark>, StopSel=</mark>, MaxWords=20, MinWords=5'),
+        amount, created_at,
+        (ts_rank(search, q.tsq) + word_similarity(sqlc.arg(query), payee))::real
+    FROM income, q
+    WHERE search @@ q.tsq OR sqlc.arg(query) <% payee
+    UNION ALL
+    SELECT 'loan'::text, id, borrower,
+        ts_headline('simple', html_escape(borrower || ' ' || subject), q.tsq, 'StartSel=<mark>, StopSel=</mark>, MaxWords=20, MinWords=5'),
+        amount, created_at,
+        (ts_rank(search, q.tsq) + word_similarity(sqlc.arg(query), borrower))::real
+    FROM loan, q
+    WHERE search @@ q.tsq OR sqlc.arg(query) <% borrower
+    UNION ALL
+    SELECT 'pay_out'::text, id, owner,
+        ts_headline('simple', html_escape(owner || ' ' || subject), q.tsq, 'StartSel=<mark>, StopSel=</mark>, MaxWords=20, MinWords=5'),
+        amount, created_at,
+        (ts_rank(search, q.tsq) + word_similarity(sqlc.arg(query), owner))::real
+    FROM pay_out, q
+    WHERE search @@ q.tsq OR sqlc.arg(query) <% owner
+) AS hits
+ORDER BY hits.rank DESC, hits.created_at DESC, hits.id
+LIMIT sqlc.arg('limit');
@@ -1,13 +1,25 @@
 -- name: CreateUser :one
 INSERT INTO users (
    username,
+   role,
    hashed_password,
    full_name,
    email
 ) VALUES (
-    $1, $2, $3, $4
+    $1, $2, $3, $4, $5
 ) RETURNING *;
 
 -- name: GetUser :one
 SELECT * FROM users WHERE username = $1 LIMIT 1;
 
+-- name: UpdateUserPassword :one
+UPDATE users SET hashed_password = $2, password_changed_at = now() WHERE username = $1 RETURNING *;
+
+-- name: GetUserByEmail :one
+SELECT * FROM users WHERE email = $1 LIMIT 1;
+
+-- name: UpdateUserRole :one
+UPDATE users SET role = $2 WHERE username = $1 RETURNING *;
+
+-- name: UpdateUserLocale :one
+UPDATE users SET locale = $2, updated_at = now() WHERE username = $1 RETURNING *;
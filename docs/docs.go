@@ -27,7 +27,220 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/auth/oidc/callback": {
+            "get": {
+                "description": "Exchanges the authorization code, provisions or links the user and returns an access token.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Completes the OpenID Connect login.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization code",
+                        "name": "code",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "State",
+                        "name": "state",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User login response",
+                        "schema": {
+                            "$ref": "#/definitions/api.loginUserResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/oidc/login": {
+            "get": {
+                "description": "Redirects to the identity provider using the authorization code flow with PKCE.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Starts the OpenID Connect login.",
+                "responses": {
+                    "302": {
+                        "description": "Found"
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/incomes": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "List incomes, optionally filtered by amount, creation time and payee or project, and sorted by sort, or search them with q.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "incomes"
+                ],
+                "summary": "List incomes",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "CreatedFrom keeps the items created at or after CreatedFrom.\nexample: 2024-01-01T00:00:00Z\nin: body",
+                        "name": "created_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "CreatedTo keeps the items created before CreatedTo.\nexample: 2024-02-01T00:00:00Z\nin: body",
+                        "name": "created_to",
+                        "in": "query"
+                    },
+                    {
+                        "maxLength": 256,
+                        "type": "string",
+                        "description": "Cursor is the next_cursor or prev_cursor of a previous response.\nin: body",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 0,
+                        "type": "number",
+                        "description": "MaxAmount keeps the items whose amount is at most MaxAmount.\nexample: 1000\nin: body",
+                        "name": "max_amount",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 0,
+                        "type": "number",
+                        "description": "MinAmount keeps the items whose amount is at least MinAmount.\nexample: 100\nin: body",
+                        "name": "min_amount",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "PageID is the page number, it defaults to 1 and is ignored when Cursor is set.\nexample: 1\nin: body\nminimum: 1",
+                        "name": "page_id",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 5,
+                        "type": "integer",
+                        "description": "PageSize is the number of projects per page.\nRequired: true\nexample: 5\nin: body\nminimum: 5\nmaximum: 100",
+                        "name": "page_size",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "maxLength": 64,
+                        "type": "string",
+                        "description": "Payee keeps the incomes whose payee contains Payee, ignoring case.\nexample: john\nin: body",
+                        "name": "payee",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ProjectID keeps the incomes of a project.\nexample: 2b7a7b3e-6c1a-4b8e-9f4e-1c2d3e4f5a6b\nin: body",
+                        "name": "project_id",
+                        "in": "query"
+                    },
+                    {
+                        "maxLength": 128,
+                        "type": "string",
+                        "description": "Query searches the incomes like the search endpoint, it cannot be combined with filters or sort.\nexample: john\nin: query",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "maxLength": 32,
+                        "type": "string",
+                        "description": "Sort is a sortable column, prefixed with - for a descending order. It defaults to created_at.\nexample: -amount\nin: body",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Set to false to omit the total count",
+                        "name": "count",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of incomes",
+                        "schema": {
+                            "$ref": "#/definitions/api.listResponse-db_Income"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    }
+                }
+            },
             "post": {
                 "security": [
                     {
@@ -91,7 +304,7 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "List all incomes.",
+                "description": "List incomes like GET /incomes. Deprecated, use GET /incomes.",
                 "consumes": [
                     "application/json"
                 ],
@@ -101,26 +314,30 @@ const docTemplate = `{
                 "tags": [
                     "incomes"
                 ],
-                "summary": "List all incomes",
+                "summary": "List incomes",
+                "deprecated": true,
                 "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Set to false to omit the total count",
+                        "name": "count",
+                        "in": "query"
+                    },
                     {
                         "description": "List Request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/api.listRequest"
+                            "$ref": "#/definitions/api.listIncomesRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "List of incomes",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/db.Income"
-                            }
+                            "$ref": "#/definitions/api.listResponse-db_Income"
                         }
                     },
                     "400": {
@@ -157,7 +374,7 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Search incomes by payee.",
+                "description": "Search incomes like GET /incomes?q=. Deprecated, use GET /incomes?q=.",
                 "consumes": [
                     "application/json"
                 ],
@@ -168,7 +385,14 @@ const docTemplate = `{
                     "incomes"
                 ],
                 "summary": "Search incomes",
+                "deprecated": true,
                 "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Set to false to omit the total count",
+                        "name": "count",
+                        "in": "query"
+                    },
                     {
                         "description": "Search Request",
                         "name": "request",
@@ -183,10 +407,7 @@ const docTemplate = `{
                     "200": {
                         "description": "Incomes found",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/db.Income"
-                            }
+                            "$ref": "#/definitions/api.listResponse-db_Income"
                         }
                     },
                     "400": {
@@ -342,14 +563,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/loans": {
+        "/invitations": {
             "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Create a new loan.",
+                "description": "Create a single-use, expiring invitation code bound to an email and role.",
                 "consumes": [
                     "application/json"
                 ],
@@ -357,25 +578,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "loans"
+                    "invitations"
                 ],
-                "summary": "Create a loan",
+                "summary": "Create an invitation",
                 "parameters": [
                     {
-                        "description": "Create Loan Request",
+                        "description": "Create Invitation Request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/api.createLoanRequest"
+                            "$ref": "#/definitions/api.createInvitationRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Loan created",
+                        "description": "Invitation created",
                         "schema": {
-                            "$ref": "#/definitions/db.Loan"
+                            "$ref": "#/definitions/db.Invitation"
                         }
                     },
                     "400": {
@@ -395,47 +616,134 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/api.errorResponse"
                         }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
                     }
                 }
             }
         },
-        "/loans/all": {
-            "post": {
+        "/livez": {
+            "get": {
+                "description": "Report whether the process is alive, dependencies are not checked.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Liveness probe",
+                "responses": {
+                    "200": {
+                        "description": "Alive",
+                        "schema": {
+                            "$ref": "#/definitions/api.healthResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/loans": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "List all loans.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "List loans, optionally filtered by amount, creation time and borrower, and sorted by sort, or search them with q.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "loans"
                 ],
-                "summary": "List all loans",
+                "summary": "List loans",
                 "parameters": [
                     {
-                        "description": "List Request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/api.listRequest"
-                        }
+                        "maxLength": 64,
+                        "type": "string",
+                        "description": "Borrower keeps the loans whose borrower contains Borrower, ignoring case.\nexample: john\nin: body",
+                        "name": "borrower",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "CreatedFrom keeps the items created at or after CreatedFrom.\nexample: 2024-01-01T00:00:00Z\nin: body",
+                        "name": "created_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "CreatedTo keeps the items created before CreatedTo.\nexample: 2024-02-01T00:00:00Z\nin: body",
+                        "name": "created_to",
+                        "in": "query"
+                    },
+                    {
+                        "maxLength": 256,
+                        "type": "string",
+                        "description": "Cursor is the next_cursor or prev_cursor of a previous response.\nin: body",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 0,
+                        "type": "number",
+                        "description": "MaxAmount keeps the items whose amount is at most MaxAmount.\nexample: 1000\nin: body",
+                        "name": "max_amount",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 0,
+                        "type": "number",
+                        "description": "MinAmount keeps the items whose amount is at least MinAmount.\nexample: 100\nin: body",
+                        "name": "min_amount",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "PageID is the page number, it defaults to 1 and is ignored when Cursor is set.\nexample: 1\nin: body\nminimum: 1",
+                        "name": "page_id",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 5,
+                        "type": "integer",
+                        "description": "PageSize is the number of projects per page.\nRequired: true\nexample: 5\nin: body\nminimum: 5\nmaximum: 100",
+                        "name": "page_size",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "maxLength": 128,
+                        "type": "string",
+                        "description": "Query searches the loans like the search endpoint, it cannot be combined with filters or sort.\nexample: john\nin: query",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "maxLength": 32,
+                        "type": "string",
+                        "description": "Sort is a sortable column, prefixed with - for a descending order. It defaults to created_at.\nexample: -amount\nin: body",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Set to false to omit the total count",
+                        "name": "count",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "List of loans",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/db.Loan"
-                            }
+                            "$ref": "#/definitions/api.listResponse-db_Loan"
                         }
                     },
                     "400": {
@@ -463,16 +771,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/loans/search": {
+            },
             "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Search loans by borrower.",
+                "description": "Create a new loan.",
                 "consumes": [
                     "application/json"
                 ],
@@ -482,26 +788,23 @@ const docTemplate = `{
                 "tags": [
                     "loans"
                 ],
-                "summary": "Search loans",
+                "summary": "Create a loan",
                 "parameters": [
                     {
-                        "description": "Search Request",
+                        "description": "Create Loan Request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/api.searchRequest"
+                            "$ref": "#/definitions/api.createLoanRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "List of loans",
+                        "description": "Loan created",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/db.Loan"
-                            }
+                            "$ref": "#/definitions/db.Loan"
                         }
                     },
                     "400": {
@@ -521,24 +824,18 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/api.errorResponse"
                         }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/api.errorResponse"
-                        }
                     }
                 }
             }
         },
-        "/loans/{id}": {
-            "get": {
+        "/loans/all": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Get a loan by ID.",
+                "description": "List loans like GET /loans. Deprecated, use GET /loans.",
                 "consumes": [
                     "application/json"
                 ],
@@ -548,19 +845,159 @@ const docTemplate = `{
                 "tags": [
                     "loans"
                 ],
-                "summary": "Get a loan",
+                "summary": "List loans",
+                "deprecated": true,
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Loan ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "type": "boolean",
+                        "description": "Set to false to omit the total count",
+                        "name": "count",
+                        "in": "query"
+                    },
+                    {
+                        "description": "List Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.listLoansRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Loan found",
+                        "description": "List of loans",
+                        "schema": {
+                            "$ref": "#/definitions/api.listResponse-db_Loan"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/loans/search": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Search loans like GET /loans?q=. Deprecated, use GET /loans?q=.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "loans"
+                ],
+                "summary": "Search loans",
+                "deprecated": true,
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Set to false to omit the total count",
+                        "name": "count",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Search Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.searchRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of loans",
+                        "schema": {
+                            "$ref": "#/definitions/api.listResponse-db_Loan"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/loans/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get a loan by ID.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "loans"
+                ],
+                "summary": "Get a loan",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Loan ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Loan found",
                         "schema": {
                             "$ref": "#/definitions/db.Loan"
                         }
@@ -664,6 +1101,131 @@ const docTemplate = `{
             }
         },
         "/pay_outs": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "List pay outs, optionally filtered by amount, creation time and owner, and sorted by sort, or search them with q.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pay_outs"
+                ],
+                "summary": "List pay outs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "CreatedFrom keeps the items created at or after CreatedFrom.\nexample: 2024-01-01T00:00:00Z\nin: body",
+                        "name": "created_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "CreatedTo keeps the items created before CreatedTo.\nexample: 2024-02-01T00:00:00Z\nin: body",
+                        "name": "created_to",
+                        "in": "query"
+                    },
+                    {
+                        "maxLength": 256,
+                        "type": "string",
+                        "description": "Cursor is the next_cursor or prev_cursor of a previous response.\nin: body",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 0,
+                        "type": "number",
+                        "description": "MaxAmount keeps the items whose amount is at most MaxAmount.\nexample: 1000\nin: body",
+                        "name": "max_amount",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 0,
+                        "type": "number",
+                        "description": "MinAmount keeps the items whose amount is at least MinAmount.\nexample: 100\nin: body",
+                        "name": "min_amount",
+                        "in": "query"
+                    },
+                    {
+                        "maxLength": 64,
+                        "type": "string",
+                        "description": "Owner keeps the pay outs whose owner contains Owner, ignoring case.\nexample: john\nin: body",
+                        "name": "owner",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "PageID is the page number, it defaults to 1 and is ignored when Cursor is set.\nexample: 1\nin: body\nminimum: 1",
+                        "name": "page_id",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 5,
+                        "type": "integer",
+                        "description": "PageSize is the number of projects per page.\nRequired: true\nexample: 5\nin: body\nminimum: 5\nmaximum: 100",
+                        "name": "page_size",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "maxLength": 128,
+                        "type": "string",
+                        "description": "Query searches the pay outs like the search endpoint, it cannot be combined with filters or sort.\nexample: john\nin: query",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "maxLength": 32,
+                        "type": "string",
+                        "description": "Sort is a sortable column, prefixed with - for a descending order. It defaults to created_at.\nexample: -amount\nin: body",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Set to false to omit the total count",
+                        "name": "count",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of pay outs",
+                        "schema": {
+                            "$ref": "#/definitions/api.listResponse-db_PayOut"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    }
+                }
+            },
             "post": {
                 "security": [
                     {
@@ -733,7 +1295,7 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "List all pay outs.",
+                "description": "List pay outs like GET /pay_outs. Deprecated, use GET /pay_outs.",
                 "consumes": [
                     "application/json"
                 ],
@@ -743,15 +1305,22 @@ const docTemplate = `{
                 "tags": [
                     "pay_outs"
                 ],
-                "summary": "List all pay outs",
+                "summary": "List pay outs",
+                "deprecated": true,
                 "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Set to false to omit the total count",
+                        "name": "count",
+                        "in": "query"
+                    },
                     {
                         "description": "List Request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/api.listRequest"
+                            "$ref": "#/definitions/api.listPayOutsRequest"
                         }
                     }
                 ],
@@ -759,10 +1328,7 @@ const docTemplate = `{
                     "200": {
                         "description": "List of pay outs",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/db.PayOut"
-                            }
+                            "$ref": "#/definitions/api.listResponse-db_PayOut"
                         }
                     },
                     "400": {
@@ -799,7 +1365,7 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Search pay outs by owner.",
+                "description": "Search pay outs like GET /pay_outs?q=. Deprecated, use GET /pay_outs?q=.",
                 "consumes": [
                     "application/json"
                 ],
@@ -810,7 +1376,14 @@ const docTemplate = `{
                     "pay_outs"
                 ],
                 "summary": "Search pay outs",
+                "deprecated": true,
                 "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Set to false to omit the total count",
+                        "name": "count",
+                        "in": "query"
+                    },
                     {
                         "description": "Search Request",
                         "name": "request",
@@ -825,10 +1398,7 @@ const docTemplate = `{
                     "200": {
                         "description": "Pay Outs found",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/db.PayOut"
-                            }
+                            "$ref": "#/definitions/api.listResponse-db_PayOut"
                         }
                     },
                     "400": {
@@ -997,39 +1567,103 @@ const docTemplate = `{
             }
         },
         "/projects": {
-            "post": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Create a new project.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "List projects, optionally filtered by amount, creation time and name, and sorted by sort, or search them with q.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "projects"
                 ],
-                "summary": "Create a project",
+                "summary": "List projects",
                 "parameters": [
                     {
-                        "description": "Create Project Request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/api.createProjectRequest"
-                        }
+                        "type": "string",
+                        "description": "CreatedFrom keeps the items created at or after CreatedFrom.\nexample: 2024-01-01T00:00:00Z\nin: body",
+                        "name": "created_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "CreatedTo keeps the items created before CreatedTo.\nexample: 2024-02-01T00:00:00Z\nin: body",
+                        "name": "created_to",
+                        "in": "query"
+                    },
+                    {
+                        "maxLength": 256,
+                        "type": "string",
+                        "description": "Cursor is the next_cursor or prev_cursor of a previous response.\nin: body",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 0,
+                        "type": "number",
+                        "description": "MaxAmount keeps the items whose amount is at most MaxAmount.\nexample: 1000\nin: body",
+                        "name": "max_amount",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 0,
+                        "type": "number",
+                        "description": "MinAmount keeps the items whose amount is at least MinAmount.\nexample: 100\nin: body",
+                        "name": "min_amount",
+                        "in": "query"
+                    },
+                    {
+                        "maxLength": 64,
+                        "type": "string",
+                        "description": "Name keeps the projects whose name contains Name, ignoring case.\nexample: project\nin: body",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "PageID is the page number, it defaults to 1 and is ignored when Cursor is set.\nexample: 1\nin: body\nminimum: 1",
+                        "name": "page_id",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 5,
+                        "type": "integer",
+                        "description": "PageSize is the number of projects per page.\nRequired: true\nexample: 5\nin: body\nminimum: 5\nmaximum: 100",
+                        "name": "page_size",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "maxLength": 128,
+                        "type": "string",
+                        "description": "Query searches the projects like the search endpoint, it cannot be combined with filters or sort.\nexample: john\nin: query",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "maxLength": 32,
+                        "type": "string",
+                        "description": "Sort is a sortable column, prefixed with - for a descending order. It defaults to created_at.\nexample: -amount\nin: body",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Set to false to omit the total count",
+                        "name": "count",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Project created",
+                        "description": "List of projects",
                         "schema": {
-                            "$ref": "#/definitions/db.Project"
+                            "$ref": "#/definitions/api.listResponse-db_Project"
                         }
                     },
                     "400": {
@@ -1057,16 +1691,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/projects/all": {
+            },
             "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "List all projects.",
+                "description": "Create a new project.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1076,15 +1708,85 @@ const docTemplate = `{
                 "tags": [
                     "projects"
                 ],
-                "summary": "List projects",
+                "summary": "Create a project",
                 "parameters": [
                     {
-                        "description": "List Request",
+                        "description": "Create Project Request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/api.listRequest"
+                            "$ref": "#/definitions/api.createProjectRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Project created",
+                        "schema": {
+                            "$ref": "#/definitions/db.Project"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/all": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "List projects like GET /projects. Deprecated, use GET /projects.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "List projects",
+                "deprecated": true,
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Set to false to omit the total count",
+                        "name": "count",
+                        "in": "query"
+                    },
+                    {
+                        "description": "List Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.listProjectsRequest"
                         }
                     }
                 ],
@@ -1092,13 +1794,7 @@ const docTemplate = `{
                     "200": {
                         "description": "List of projects",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "type": "array",
-                                "items": {
-                                    "$ref": "#/definitions/db.Project"
-                                }
-                            }
+                            "$ref": "#/definitions/api.listResponse-db_Project"
                         }
                     },
                     "400": {
@@ -1135,7 +1831,7 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Search projects.",
+                "description": "Search projects like GET /projects?q=. Deprecated, use GET /projects?q=.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1146,14 +1842,21 @@ const docTemplate = `{
                     "projects"
                 ],
                 "summary": "Search projects",
+                "deprecated": true,
                 "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Set to false to omit the total count",
+                        "name": "count",
+                        "in": "query"
+                    },
                     {
                         "description": "Search Request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/api.listRequest"
+                            "$ref": "#/definitions/api.searchRequest"
                         }
                     }
                 ],
@@ -1161,37 +1864,31 @@ const docTemplate = `{
                     "200": {
                         "description": "List of projects",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "type": "array",
-                                "items": {
-                                    "$ref": "#/definitions/db.Project"
-                                }
-                            }
+                            "$ref": "#/definitions/api.listResponse-db_Project"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/api.errorResponse"
                         }
                     },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/api.errorResponse"
                         }
                     },
                     "403": {
                         "description": "Forbidden",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/api.errorResponse"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/api.errorResponse"
                         }
                     }
                 }
@@ -1329,6 +2026,176 @@ const docTemplate = `{
                 }
             }
         },
+        "/readyz": {
+            "get": {
+                "description": "Run the readiness checks of every component, fails once shutdown has begun.\nErrors and durations are reported with verbose=true, which requires an admin token.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Readiness probe",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Report errors and durations",
+                        "name": "verbose",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Ready",
+                        "schema": {
+                            "$ref": "#/definitions/api.healthResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Not ready",
+                        "schema": {
+                            "$ref": "#/definitions/api.healthResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/search": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Search projects, incomes, loans and pay outs, ranked by relevance.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "search"
+                ],
+                "summary": "Search all records",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Search query",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Maximum number of hits",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Ranked hits",
+                        "schema": {
+                            "$ref": "#/definitions/api.searchAllResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/locale": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Sets the locale of the error messages sent to the logged-in user, it takes precedence over the Accept-Language header.\nThe locale is carried by the access tokens, the response holds a new access token, expiring with the current one, which carries it.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Sets the preferred locale of the logged-in user.",
+                "parameters": [
+                    {
+                        "description": "Update locale request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.updateLocaleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User and access token response",
+                        "schema": {
+                            "$ref": "#/definitions/api.loginUserResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/users/login": {
             "post": {
                 "description": "Logs in a user.",
@@ -1372,6 +2239,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/api.errorResponse"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    },
                     "404": {
                         "description": "Not found",
                         "schema": {
@@ -1387,9 +2260,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/signup": {
+        "/users/password": {
             "post": {
-                "description": "Creates a new user.",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Changes the password of the logged-in user. The new password must satisfy the password policy and must not match any recently used password.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1399,21 +2277,21 @@ const docTemplate = `{
                 "tags": [
                     "users"
                 ],
-                "summary": "Creates a new user.",
+                "summary": "Changes the password of the logged-in user.",
                 "parameters": [
                     {
-                        "description": "User creation request",
+                        "description": "Change password request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/api.createUserRequest"
+                            "$ref": "#/definitions/api.changePasswordRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "User creation response",
+                        "description": "User response",
                         "schema": {
                             "$ref": "#/definitions/api.userResponse"
                         }
@@ -1424,8 +2302,14 @@ const docTemplate = `{
                             "$ref": "#/definitions/api.errorResponse"
                         }
                     },
-                    "403": {
-                        "description": "Forbidden",
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
                         "schema": {
                             "$ref": "#/definitions/api.errorResponse"
                         }
@@ -1438,29 +2322,136 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "api.createIncomeRequest": {
-            "type": "object",
-            "required": [
-                "amount",
-                "payee",
-                "project_id"
-            ],
-            "properties": {
-                "amount": {
-                    "description": "Amount of the income.\nRequired: true\nexample: 1000\nin: body",
-                    "type": "number"
-                },
-                "payee": {
-                    "description": "Payee of the income.\nRequired: true\nexample: john_doe\nin: body",
-                    "type": "string"
-                },
-                "project_id": {
-                    "description": "ProjectID of the income.\nRequired: true\nswagger:strfmt uuid\nexample: 123e4567-e89b-12d3-a456-426614174000\nin: body",
-                    "type": "string"
-                }
+        },
+        "/users/signup": {
+            "post": {
+                "description": "Creates a new user.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Creates a new user.",
+                "parameters": [
+                    {
+                        "description": "User creation request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.createUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User creation response",
+                        "schema": {
+                            "$ref": "#/definitions/api.userResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "api.changePasswordRequest": {
+            "type": "object",
+            "required": [
+                "new_password",
+                "old_password"
+            ],
+            "properties": {
+                "new_password": {
+                    "description": "New password of the user, checked against the configured password policy.\nRequired: true\nexample: password456\nin: body",
+                    "type": "string"
+                },
+                "old_password": {
+                    "description": "Current password of the user.\nRequired: true\nexample: password123\nin: body",
+                    "type": "string",
+                    "maxLength": 72
+                }
+            }
+        },
+        "api.componentHealth": {
+            "type": "object",
+            "properties": {
+                "duration": {
+                    "description": "Duration is only reported in verbose mode.\nexample: 1.2ms",
+                    "type": "string"
+                },
+                "error": {
+                    "description": "Error is only reported in verbose mode.",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "example: ok",
+                    "type": "string"
+                }
+            }
+        },
+        "api.createIncomeRequest": {
+            "type": "object",
+            "required": [
+                "amount",
+                "payee",
+                "project_id"
+            ],
+            "properties": {
+                "amount": {
+                    "description": "Amount of the income.\nRequired: true\nexample: 1000\nin: body",
+                    "type": "number"
+                },
+                "payee": {
+                    "description": "Payee of the income.\nRequired: true\nexample: john_doe\nin: body",
+                    "type": "string"
+                },
+                "project_id": {
+                    "description": "ProjectID of the income.\nRequired: true\nswagger:strfmt uuid\nexample: 123e4567-e89b-12d3-a456-426614174000\nin: body",
+                    "type": "string"
+                }
+            }
+        },
+        "api.createInvitationRequest": {
+            "type": "object",
+            "required": [
+                "email"
+            ],
+            "properties": {
+                "email": {
+                    "description": "Email the invitation is bound to.\nRequired: true\nexample: john_doe@example.com\nin: body\nformat: email",
+                    "type": "string"
+                },
+                "role": {
+                    "description": "Role the invited user gets, defaults to user.\nexample: user\nin: body\nenum: admin,user",
+                    "type": "string",
+                    "enum": [
+                        "admin",
+                        "user"
+                    ]
+                }
             }
         },
         "api.createLoanRequest": {
@@ -1546,11 +2537,13 @@ const docTemplate = `{
                     "description": "Full name of the user.\nRequired: true\nexample: John Doe\nin: body\nminLength: 1\nmaxLength: 255",
                     "type": "string"
                 },
+                "invitation_code": {
+                    "description": "Invitation code, required when signup is invite-only.\nexample: 0e2gSXGEnh1hUj1V9vbqPMlwmW9oSU0f\nin: body",
+                    "type": "string"
+                },
                 "password": {
-                    "description": "Password of the user.\nRequired: true\nexample: password123\nin: body\nminLength: 6\nmaxLength: 32",
-                    "type": "string",
-                    "maxLength": 32,
-                    "minLength": 6
+                    "description": "Password of the user, checked against the configured password policy.\nRequired: true\nexample: password123\nin: body",
+                    "type": "string"
                 },
                 "username": {
                     "description": "Username of the user.\nRequired: true\nexample: john_doe\nin: body\nminLength: 1\nmaxLength: 255",
@@ -1561,28 +2554,470 @@ const docTemplate = `{
         "api.errorResponse": {
             "type": "object",
             "properties": {
-                "error": {
+                "code": {
+                    "description": "Code identifies the problem and never changes.",
+                    "type": "string",
+                    "example": "not_found"
+                },
+                "detail": {
+                    "description": "Detail explains this occurrence of the problem.",
+                    "type": "string",
+                    "example": "resource not found"
+                },
+                "errors": {
+                    "description": "Errors lists the invalid fields of a validation_failed problem.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.fieldError"
+                    }
+                },
+                "instance": {
+                    "description": "Instance is the path of the request.",
+                    "type": "string",
+                    "example": "/v1/projects/123e4567-e89b-12d3-a456-426614174000"
+                },
+                "request_id": {
+                    "description": "RequestID identifies the request in the server logs.",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "Status is the HTTP status code.",
+                    "type": "integer",
+                    "example": 404
+                },
+                "title": {
+                    "description": "Title is the HTTP status text.",
+                    "type": "string",
+                    "example": "Not Found"
+                },
+                "type": {
+                    "description": "Type is always about:blank, the code tells problems apart.",
+                    "type": "string",
+                    "example": "about:blank"
+                },
+                "violations": {
+                    "description": "Violations lists the rules broken by the password of a password_policy_violation problem.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.passwordViolation"
+                    }
+                }
+            }
+        },
+        "api.fieldError": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "description": "Field is the name of the field in the request.",
+                    "type": "string",
+                    "example": "page_size"
+                },
+                "message": {
+                    "description": "Message explains the error.",
+                    "type": "string",
+                    "example": "page_size must be 5 or greater"
+                },
+                "param": {
+                    "description": "Param is the parameter of the rule.",
+                    "type": "string",
+                    "example": "5"
+                },
+                "rule": {
+                    "description": "Rule is the validation rule the field breaks.",
+                    "type": "string",
+                    "example": "min"
+                }
+            }
+        },
+        "api.healthResponse": {
+            "type": "object",
+            "properties": {
+                "checks": {
+                    "description": "Checks holds the result of every component check.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/api.componentHealth"
+                    }
+                },
+                "status": {
+                    "description": "Status is ok when every check passed, fail otherwise.\nexample: ok",
                     "type": "string"
                 }
             }
         },
-        "api.listRequest": {
+        "api.listIncomesRequest": {
             "type": "object",
             "required": [
-                "page_id",
                 "page_size"
             ],
             "properties": {
+                "created_from": {
+                    "description": "CreatedFrom keeps the items created at or after CreatedFrom.\nexample: 2024-01-01T00:00:00Z\nin: body",
+                    "type": "string"
+                },
+                "created_to": {
+                    "description": "CreatedTo keeps the items created before CreatedTo.\nexample: 2024-02-01T00:00:00Z\nin: body",
+                    "type": "string"
+                },
+                "cursor": {
+                    "description": "Cursor is the next_cursor or prev_cursor of a previous response.\nin: body",
+                    "type": "string",
+                    "maxLength": 256
+                },
+                "max_amount": {
+                    "description": "MaxAmount keeps the items whose amount is at most MaxAmount.\nexample: 1000\nin: body",
+                    "type": "number",
+                    "minimum": 0
+                },
+                "min_amount": {
+                    "description": "MinAmount keeps the items whose amount is at least MinAmount.\nexample: 100\nin: body",
+                    "type": "number",
+                    "minimum": 0
+                },
                 "page_id": {
-                    "description": "PageID is the page number.\nRequired: true\nexample: 1\nin: body\nminimum: 1",
+                    "description": "PageID is the page number, it defaults to 1 and is ignored when Cursor is set.\nexample: 1\nin: body\nminimum: 1",
                     "type": "integer",
                     "minimum": 1
                 },
                 "page_size": {
-                    "description": "PageSize is the number of projects per page.\nRequired: true\nexample: 5\nin: body\nminimum: 5\nmaximum: 10",
+                    "description": "PageSize is the number of projects per page.\nRequired: true\nexample: 5\nin: body\nminimum: 5\nmaximum: 100",
                     "type": "integer",
                     "maximum": 100,
                     "minimum": 5
+                },
+                "payee": {
+                    "description": "Payee keeps the incomes whose payee contains Payee, ignoring case.\nexample: john\nin: body",
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "project_id": {
+                    "description": "ProjectID keeps the incomes of a project.\nexample: 2b7a7b3e-6c1a-4b8e-9f4e-1c2d3e4f5a6b\nin: body",
+                    "type": "string"
+                },
+                "q": {
+                    "description": "Query searches the incomes like the search endpoint, it cannot be combined with filters or sort.\nexample: john\nin: query",
+                    "type": "string",
+                    "maxLength": 128
+                },
+                "sort": {
+                    "description": "Sort is a sortable column, prefixed with - for a descending order. It defaults to created_at.\nexample: -amount\nin: body",
+                    "type": "string",
+                    "maxLength": 32
+                }
+            }
+        },
+        "api.listLoansRequest": {
+            "type": "object",
+            "required": [
+                "page_size"
+            ],
+            "properties": {
+                "borrower": {
+                    "description": "Borrower keeps the loans whose borrower contains Borrower, ignoring case.\nexample: john\nin: body",
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "created_from": {
+                    "description": "CreatedFrom keeps the items created at or after CreatedFrom.\nexample: 2024-01-01T00:00:00Z\nin: body",
+                    "type": "string"
+                },
+                "created_to": {
+                    "description": "CreatedTo keeps the items created before CreatedTo.\nexample: 2024-02-01T00:00:00Z\nin: body",
+                    "type": "string"
+                },
+                "cursor": {
+                    "description": "Cursor is the next_cursor or prev_cursor of a previous response.\nin: body",
+                    "type": "string",
+                    "maxLength": 256
+                },
+                "max_amount": {
+                    "description": "MaxAmount keeps the items whose amount is at most MaxAmount.\nexample: 1000\nin: body",
+                    "type": "number",
+                    "minimum": 0
+                },
+                "min_amount": {
+                    "description": "MinAmount keeps the items whose amount is at least MinAmount.\nexample: 100\nin: body",
+                    "type": "number",
+                    "minimum": 0
+                },
+                "page_id": {
+                    "description": "PageID is the page number, it defaults to 1 and is ignored when Cursor is set.\nexample: 1\nin: body\nminimum: 1",
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "page_size": {
+                    "description": "PageSize is the number of projects per page.\nRequired: true\nexample: 5\nin: body\nminimum: 5\nmaximum: 100",
+                    "type": "integer",
+                    "maximum": 100,
+                    "minimum": 5
+                },
+                "q": {
+                    "description": "Query searches the loans like the search endpoint, it cannot be combined with filters or sort.\nexample: john\nin: query",
+                    "type": "string",
+                    "maxLength": 128
+                },
+                "sort": {
+                    "description": "Sort is a sortable column, prefixed with - for a descending order. It defaults to created_at.\nexample: -amount\nin: body",
+                    "type": "string",
+                    "maxLength": 32
+                }
+            }
+        },
+        "api.listPayOutsRequest": {
+            "type": "object",
+            "required": [
+                "page_size"
+            ],
+            "properties": {
+                "created_from": {
+                    "description": "CreatedFrom keeps the items created at or after CreatedFrom.\nexample: 2024-01-01T00:00:00Z\nin: body",
+                    "type": "string"
+                },
+                "created_to": {
+                    "description": "CreatedTo keeps the items created before CreatedTo.\nexample: 2024-02-01T00:00:00Z\nin: body",
+                    "type": "string"
+                },
+                "cursor": {
+                    "description": "Cursor is the next_cursor or prev_cursor of a previous response.\nin: body",
+                    "type": "string",
+                    "maxLength": 256
+                },
+                "max_amount": {
+                    "description": "MaxAmount keeps the items whose amount is at most MaxAmount.\nexample: 1000\nin: body",
+                    "type": "number",
+                    "minimum": 0
+                },
+                "min_amount": {
+                    "description": "MinAmount keeps the items whose amount is at least MinAmount.\nexample: 100\nin: body",
+                    "type": "number",
+                    "minimum": 0
+                },
+                "owner": {
+                    "description": "Owner keeps the pay outs whose owner contains Owner, ignoring case.\nexample: john\nin: body",
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "page_id": {
+                    "description": "PageID is the page number, it defaults to 1 and is ignored when Cursor is set.\nexample: 1\nin: body\nminimum: 1",
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "page_size": {
+                    "description": "PageSize is the number of projects per page.\nRequired: true\nexample: 5\nin: body\nminimum: 5\nmaximum: 100",
+                    "type": "integer",
+                    "maximum": 100,
+                    "minimum": 5
+                },
+                "q": {
+                    "description": "Query searches the pay outs like the search endpoint, it cannot be combined with filters or sort.\nexample: john\nin: query",
+                    "type": "string",
+                    "maxLength": 128
+                },
+                "sort": {
+                    "description": "Sort is a sortable column, prefixed with - for a descending order. It defaults to created_at.\nexample: -amount\nin: body",
+                    "type": "string",
+                    "maxLength": 32
+                }
+            }
+        },
+        "api.listProjectsRequest": {
+            "type": "object",
+            "required": [
+                "page_size"
+            ],
+            "properties": {
+                "created_from": {
+                    "description": "CreatedFrom keeps the items created at or after CreatedFrom.\nexample: 2024-01-01T00:00:00Z\nin: body",
+                    "type": "string"
+                },
+                "created_to": {
+                    "description": "CreatedTo keeps the items created before CreatedTo.\nexample: 2024-02-01T00:00:00Z\nin: body",
+                    "type": "string"
+                },
+                "cursor": {
+                    "description": "Cursor is the next_cursor or prev_cursor of a previous response.\nin: body",
+                    "type": "string",
+                    "maxLength": 256
+                },
+                "max_amount": {
+                    "description": "MaxAmount keeps the items whose amount is at most MaxAmount.\nexample: 1000\nin: body",
+                    "type": "number",
+                    "minimum": 0
+                },
+                "min_amount": {
+                    "description": "MinAmount keeps the items whose amount is at least MinAmount.\nexample: 100\nin: body",
+                    "type": "number",
+                    "minimum": 0
+                },
+                "name": {
+                    "description": "Name keeps the projects whose name contains Name, ignoring case.\nexample: project\nin: body",
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "page_id": {
+                    "description": "PageID is the page number, it defaults to 1 and is ignored when Cursor is set.\nexample: 1\nin: body\nminimum: 1",
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "page_size": {
+                    "description": "PageSize is the number of projects per page.\nRequired: true\nexample: 5\nin: body\nminimum: 5\nmaximum: 100",
+                    "type": "integer",
+                    "maximum": 100,
+                    "minimum": 5
+                },
+                "q": {
+                    "description": "Query searches the projects like the search endpoint, it cannot be combined with filters or sort.\nexample: john\nin: query",
+                    "type": "string",
+                    "maxLength": 128
+                },
+                "sort": {
+                    "description": "Sort is a sortable column, prefixed with - for a descending order. It defaults to created_at.\nexample: -amount\nin: body",
+                    "type": "string",
+                    "maxLength": 32
+                }
+            }
+        },
+        "api.listResponse-db_Income": {
+            "type": "object",
+            "properties": {
+                "has_more": {
+                    "description": "HasMore tells whether there is a next page.",
+                    "type": "boolean"
+                },
+                "items": {
+                    "description": "Items of the page, ordered by creation time unless sorted otherwise, search results by rank.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/db.Income"
+                    }
+                },
+                "next_cursor": {
+                    "description": "NextCursor fetches the following page, it is omitted on the last page.",
+                    "type": "string"
+                },
+                "page": {
+                    "description": "Page is the page number, 0 when the page was reached by a cursor of unknown page.",
+                    "type": "integer"
+                },
+                "page_size": {
+                    "description": "PageSize is the maximum number of items per page.",
+                    "type": "integer"
+                },
+                "prev_cursor": {
+                    "description": "PrevCursor fetches the preceding page, it is omitted on the first page.",
+                    "type": "string"
+                },
+                "total": {
+                    "description": "Total is the number of items of every page, it is omitted when requested with count=false.",
+                    "type": "integer"
+                }
+            }
+        },
+        "api.listResponse-db_Loan": {
+            "type": "object",
+            "properties": {
+                "has_more": {
+                    "description": "HasMore tells whether there is a next page.",
+                    "type": "boolean"
+                },
+                "items": {
+                    "description": "Items of the page, ordered by creation time unless sorted otherwise, search results by rank.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/db.Loan"
+                    }
+                },
+                "next_cursor": {
+                    "description": "NextCursor fetches the following page, it is omitted on the last page.",
+                    "type": "string"
+                },
+                "page": {
+                    "description": "Page is the page number, 0 when the page was reached by a cursor of unknown page.",
+                    "type": "integer"
+                },
+                "page_size": {
+                    "description": "PageSize is the maximum number of items per page.",
+                    "type": "integer"
+                },
+                "prev_cursor": {
+                    "description": "PrevCursor fetches the preceding page, it is omitted on the first page.",
+                    "type": "string"
+                },
+                "total": {
+                    "description": "Total is the number of items of every page, it is omitted when requested with count=false.",
+                    "type": "integer"
+                }
+            }
+        },
+        "api.listResponse-db_PayOut": {
+            "type": "object",
+            "properties": {
+                "has_more": {
+                    "description": "HasMore tells whether there is a next page.",
+                    "type": "boolean"
+                },
+                "items": {
+                    "description": "Items of the page, ordered by creation time unless sorted otherwise, search results by rank.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/db.PayOut"
+                    }
+                },
+                "next_cursor": {
+                    "description": "NextCursor fetches the following page, it is omitted on the last page.",
+                    "type": "string"
+                },
+                "page": {
+                    "description": "Page is the page number, 0 when the page was reached by a cursor of unknown page.",
+                    "type": "integer"
+                },
+                "page_size": {
+                    "description": "PageSize is the maximum number of items per page.",
+                    "type": "integer"
+                },
+                "prev_cursor": {
+                    "description": "PrevCursor fetches the preceding page, it is omitted on the first page.",
+                    "type": "string"
+                },
+                "total": {
+                    "description": "Total is the number of items of every page, it is omitted when requested with count=false.",
+                    "type": "integer"
+                }
+            }
+        },
+        "api.listResponse-db_Project": {
+            "type": "object",
+            "properties": {
+                "has_more": {
+                    "description": "HasMore tells whether there is a next page.",
+                    "type": "boolean"
+                },
+                "items": {
+                    "description": "Items of the page, ordered by creation time unless sorted otherwise, search results by rank.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/db.Project"
+                    }
+                },
+                "next_cursor": {
+                    "description": "NextCursor fetches the following page, it is omitted on the last page.",
+                    "type": "string"
+                },
+                "page": {
+                    "description": "Page is the page number, 0 when the page was reached by a cursor of unknown page.",
+                    "type": "integer"
+                },
+                "page_size": {
+                    "description": "PageSize is the maximum number of items per page.",
+                    "type": "integer"
+                },
+                "prev_cursor": {
+                    "description": "PrevCursor fetches the preceding page, it is omitted on the first page.",
+                    "type": "string"
+                },
+                "total": {
+                    "description": "Total is the number of items of every page, it is omitted when requested with count=false.",
+                    "type": "integer"
                 }
             }
         },
@@ -1594,10 +3029,9 @@ const docTemplate = `{
             ],
             "properties": {
                 "password": {
-                    "description": "Password of the user.\nRequired: true\nexample: password123\nin: body\nminLength: 6\nmaxLength: 32",
+                    "description": "Password of the user.\nRequired: true\nexample: password123\nin: body\nmaxLength: 72",
                     "type": "string",
-                    "maxLength": 32,
-                    "minLength": 6
+                    "maxLength": 72
                 },
                 "username": {
                     "description": "Username of the user.\nRequired: true\nexample: john_doe\nin: body",
@@ -1630,31 +3064,80 @@ const docTemplate = `{
                 }
             }
         },
+        "api.passwordViolation": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "description": "Message explains the violation.",
+                    "type": "string",
+                    "example": "must be at least 8 characters long"
+                },
+                "param": {
+                    "description": "Param is the parameter of the rule.",
+                    "type": "string",
+                    "example": "8"
+                },
+                "rule": {
+                    "description": "Rule identifies the broken rule.",
+                    "type": "string",
+                    "example": "min_length"
+                }
+            }
+        },
+        "api.searchAllResponse": {
+            "type": "object",
+            "properties": {
+                "hits": {
+                    "description": "Hits are projects, incomes, loans and pay outs. Their snippet is HTML: the matched text,\nescaped, with the matching words wrapped in \u003cmark\u003e\u003c/mark\u003e.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/db.SearchAllRow"
+                    }
+                }
+            }
+        },
         "api.searchRequest": {
             "type": "object",
             "required": [
-                "page_id",
                 "page_size",
                 "query"
             ],
             "properties": {
+                "cursor": {
+                    "description": "Cursor is the next_cursor or prev_cursor of a previous response.\nin: body",
+                    "type": "string",
+                    "maxLength": 256
+                },
                 "page_id": {
-                    "description": "PageID is the page number.\nRequired: true\nexample: 1\nin: body\nminimum: 1",
+                    "description": "PageID is the page number, it defaults to 1 and is ignored when Cursor is set.\nexample: 1\nin: body\nminimum: 1",
                     "type": "integer",
                     "minimum": 1
                 },
                 "page_size": {
-                    "description": "PageSize is the number of projects per page.\nRequired: true\nexample: 5\nin: body\nminimum: 5\nmaximum: 10",
+                    "description": "PageSize is the number of projects per page.\nRequired: true\nexample: 5\nin: body\nminimum: 5\nmaximum: 100",
                     "type": "integer",
-                    "maximum": 10,
+                    "maximum": 100,
                     "minimum": 5
                 },
                 "query": {
-                    "description": "Query is the search query.\nRequired: true\nexample: project1\nin: body",
+                    "description": "Query is matched by full-text search against names, descriptions and subjects\nand by trigram similarity against names, so partial and misspelled names match.\nRequired: true\nexample: project1\nin: body",
                     "type": "string"
                 }
             }
         },
+        "api.updateLocaleRequest": {
+            "type": "object",
+            "properties": {
+                "locale": {
+                    "description": "Locale is the preferred locale of error messages, empty to follow the Accept-Language header.\nexample: zh\nin: body",
+                    "type": "string",
+                    "enum": [
+                        "en",
+                        "zh"
+                    ]
+                }
+            }
+        },
         "api.userResponse": {
             "type": "object",
             "properties": {
@@ -1670,6 +3153,10 @@ const docTemplate = `{
                     "description": "Full name of the user.\nexample: John Doe",
                     "type": "string"
                 },
+                "locale": {
+                    "description": "Locale is the preferred locale of the user, empty to follow the Accept-Language header.\nexample: zh",
+                    "type": "string"
+                },
                 "password_changed_at": {
                     "description": "PasswordChangedAt represents the timestamp when the password was last changed.\nswagger:strfmt date-time",
                     "type": "string"
@@ -1707,6 +3194,35 @@ const docTemplate = `{
                 }
             }
         },
+        "db.Invitation": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string"
+                },
+                "used_at": {
+                    "type": "string"
+                },
+                "used_by": {
+                    "type": "string"
+                }
+            }
+        },
         "db.Loan": {
             "type": "object",
             "properties": {
@@ -1775,6 +3291,32 @@ const docTemplate = `{
                     "type": "string"
                 }
             }
+        },
+        "db.SearchAllRow": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "rank": {
+                    "type": "number"
+                },
+                "resource": {
+                    "type": "string"
+                },
+                "snippet": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
         }
     },
     "securityDefinitions": {
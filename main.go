@@ -2,15 +2,14 @@ package main
 
 import (
 	"context"
-
-	"github.com/jackc/pgx/v5/pgxpool"
-	"github.com/lushenle/plam/pkg/api"
-	"github.com/lushenle/plam/pkg/db"
-	"github.com/lushenle/plam/pkg/log"
-	"github.com/lushenle/plam/pkg/token"
-	"github.com/lushenle/plam/pkg/util"
-	"go.uber.org/zap"
-	"go.uber.org/zap/zapcore"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/lushenle/plam/pkg/cmd"
 )
 
 //	@Title			PLAM API
@@ -37,44 +36,17 @@ import (
 //	@license.url	http://www.apache.org/licenses/LICENSE-2.0.html
 
 func main() {
-	// Setup config
-	config, err := util.LoadConfig(".")
-	if err != nil {
-		panic(err)
-	}
-
-	logLevel := map[string]zapcore.Level{
-		"debug": zapcore.DebugLevel,
-		"info":  zapcore.InfoLevel,
-		"warn":  zapcore.WarnLevel,
-		"error": zapcore.ErrorLevel,
-	}
-	plugin, closer := log.NewFilePlugin(config.Logfile, logLevel[config.Loglevel])
-	defer closer.Close()
-	logger := log.NewLogger(plugin)
-	logger.Info("service starting...")
-
-	// Tokenmaker
-	tokenMaker, err := token.NewPasetoMaker(config.Server.TokenSymmetricKey)
-	if err != nil {
-		logger.Fatal("cannot create token maker", zap.String("tokenMaker", err.Error()))
-	}
-
-	// Set database
-	conn, err := pgxpool.New(context.Background(), config.Database.DataSourceName)
-	if err != nil {
-		logger.Fatal("db connection failed", zap.String("db", err.Error()))
-	}
-
-	// Database schema migration
-	if err = util.DBMigration(config.Database.MigrationURL, config.Database.DataSourceName); err != nil {
-		logger.Fatal("db migration failed", zap.String("db", err.Error()))
-	}
-	logger.Info("db migrated successfully")
-	store := db.NewStore(conn)
-
-	srv := api.NewServer(config, api.WithStore(store), api.WithLogger(logger), api.WithTokenMaker(tokenMaker))
-	if err := srv.Start(config.Server.ServerAddress); err != nil {
-		logger.Fatal("failed to run server", zap.String("server", err.Error()))
+	// SIGTERM is sent by Kubernetes before a pod is stopped, SIGINT by Ctrl-C
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		// Restore the default behavior so that a second signal exits immediately
+		<-ctx.Done()
+		stop()
+	}()
+
+	if err := cmd.Execute(ctx, os.Args[1:]); err != nil && !errors.Is(err, flag.ErrHelp) {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
 	}
 }
@@ -0,0 +1,116 @@
+package api
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lushenle/plam/pkg/db"
+)
+
+// errSearchWithFilter is returned when a list request has both a search query and filters
+var errSearchWithFilter = errors.New("q cannot be combined with filters or sort")
+
+// listFilter holds the filters and the sort shared by the list requests.
+//
+//	@swagger:model
+type listFilter struct {
+	// MinAmount keeps the items whose amount is at least MinAmount.
+	// example: 100
+	// in: body
+	MinAmount *float32 `json:"min_amount" form:"min_amount" binding:"omitempty,min=0"`
+
+	// MaxAmount keeps the items whose amount is at most MaxAmount.
+	// example: 1000
+	// in: body
+	MaxAmount *float32 `json:"max_amount" form:"max_amount" binding:"omitempty,min=0"`
+
+	// CreatedFrom keeps the items created at or after CreatedFrom.
+	// example: 2024-01-01T00:00:00Z
+	// in: body
+	CreatedFrom *time.Time `json:"created_from" form:"created_from"`
+
+	// CreatedTo keeps the items created before CreatedTo.
+	// example: 2024-02-01T00:00:00Z
+	// in: body
+	CreatedTo *time.Time `json:"created_to" form:"created_to"`
+
+	// Sort is a sortable column, prefixed with - for a descending order. It defaults to created_at.
+	// example: -amount
+	// in: body
+	Sort string `json:"sort" form:"sort" binding:"omitempty,max=32"`
+}
+
+// dbFilter validates the filter and converts it, sort must be one of sortColumns
+func (f listFilter) dbFilter(sortColumns []string) (db.ListFilter, error) {
+	filter := db.ListFilter{
+		MinAmount:   f.MinAmount,
+		MaxAmount:   f.MaxAmount,
+		CreatedFrom: f.CreatedFrom,
+		CreatedTo:   f.CreatedTo,
+	}
+
+	if f.MinAmount != nil && f.MaxAmount != nil && *f.MinAmount > *f.MaxAmount {
+		return filter, errors.New("min_amount must not be greater than max_amount")
+	}
+	if f.CreatedFrom != nil && f.CreatedTo != nil && !f.CreatedFrom.Before(*f.CreatedTo) {
+		return filter, errors.New("created_from must be before created_to")
+	}
+	if f.Sort != "" {
+		sort, err := db.ParseSort(f.Sort, sortColumns)
+		if err != nil {
+			return filter, err
+		}
+		filter.Sort = sort
+	}
+	return filter, nil
+}
+
+// parseProjectID returns the project ID of a filter, nil when id is empty
+func parseProjectID(id string) *uuid.UUID {
+	if id == "" {
+		return nil
+	}
+	projectID := uuid.MustParse(id)
+	return &projectID
+}
+
+// filterPageQueries returns the queries of a filtered list, the cursors carry the sort of filter
+func filterPageQueries[T any](
+	filter db.ListFilter,
+	list func(arg db.FilterParams) ([]T, error),
+	count func(filter db.ListFilter) (int64, error),
+	key func(item T) pageCursor,
+	sortValue func(item T, column string) string,
+) pageQueries[T] {
+	sort := filter.Sort
+	if sort.Column == "" {
+		sort.Column = "created_at"
+	}
+
+	keyset := func(cursor pageCursor) *db.Keyset {
+		return &db.Keyset{Value: cursor.Value, ID: cursor.ID, Backward: cursor.Backward}
+	}
+
+	return pageQueries[T]{
+		offset: func(offset, limit int32) ([]T, error) {
+			return list(db.FilterParams{ListFilter: filter, Offset: offset, Limit: limit})
+		},
+		after: func(cursor pageCursor, limit int32) ([]T, error) {
+			return list(db.FilterParams{ListFilter: filter, Keyset: keyset(cursor), Limit: limit})
+		},
+		before: func(cursor pageCursor, limit int32) ([]T, error) {
+			return list(db.FilterParams{ListFilter: filter, Keyset: keyset(cursor), Limit: limit})
+		},
+		count: func() (int64, error) {
+			return count(filter)
+		},
+		key: func(item T) pageCursor {
+			cursor := key(item)
+			cursor.Sort = sort.String()
+			cursor.Value = sortValue(item, sort.Column)
+			return cursor
+		},
+		sort: sort.String(),
+	}
+}
@@ -0,0 +1,72 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lushenle/plam/pkg/db"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListFilter(t *testing.T) {
+	amount := func(v float32) *float32 { return &v }
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+
+	filter, err := listFilter{MinAmount: amount(10), CreatedFrom: &from, CreatedTo: &to, Sort: "-amount"}.dbFilter(db.LoanSortColumns)
+	require.NoError(t, err)
+	require.Equal(t, db.ListFilter{
+		MinAmount:   amount(10),
+		CreatedFrom: &from,
+		CreatedTo:   &to,
+		Sort:        db.Sort{Column: "amount", Desc: true},
+	}, filter)
+
+	filter, err = listFilter{}.dbFilter(db.LoanSortColumns)
+	require.NoError(t, err)
+	require.True(t, filter.IsZero())
+
+	for _, f := range []listFilter{
+		{MinAmount: amount(10), MaxAmount: amount(5)},
+		{CreatedFrom: &to, CreatedTo: &from},
+		{Sort: "payee"},
+	} {
+		_, err = f.dbFilter(db.LoanSortColumns)
+		require.Error(t, err)
+	}
+}
+
+func TestFilterPageQueries(t *testing.T) {
+	loan := db.Loan{ID: uuid.New(), Borrower: "john", Amount: 12.5, CreatedAt: time.Now()}
+
+	var got db.FilterParams
+	filter := db.ListFilter{Sort: db.Sort{Column: "amount", Desc: true}}
+	queries := filterPageQueries(filter,
+		func(arg db.FilterParams) ([]db.Loan, error) {
+			got = arg
+			return []db.Loan{loan}, nil
+		},
+		func(db.ListFilter) (int64, error) { return 1, nil },
+		loanCursor, db.Loan.SortValue)
+
+	cursor := queries.key(loan)
+	require.Equal(t, "-amount", cursor.Sort)
+	require.Equal(t, "12.5", cursor.Value)
+	require.Equal(t, "-amount", queries.sort)
+
+	cursor.Backward = true
+	_, err := queries.before(cursor, 6)
+	require.NoError(t, err)
+	require.Equal(t, db.FilterParams{
+		ListFilter: filter,
+		Keyset:     &db.Keyset{Value: "12.5", ID: loan.ID, Backward: true},
+		Limit:      6,
+	}, got)
+
+	// Cursors of the created_at, id order are rejected by sorted lists and the other way around
+	_, err = fetchPage(page{limit: 5, cursor: &pageCursor{CreatedAt: loan.CreatedAt, ID: loan.ID}}, queries)
+	require.ErrorIs(t, err, errInvalidCursor)
+	_, err = fetchPage(page{limit: 5, cursor: &cursor}, pageQueries[db.Loan]{key: loanCursor})
+	require.ErrorIs(t, err, errInvalidCursor)
+}
@@ -0,0 +1,219 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lushenle/plam/pkg/util"
+)
+
+// healthCheckTimeout bounds every readiness check
+const healthCheckTimeout = 2 * time.Second
+
+const (
+	healthStatusOK   = "ok"
+	healthStatusFail = "fail"
+)
+
+// HealthCheck returns an error when the component it checks cannot serve traffic
+type HealthCheck func(ctx context.Context) error
+
+type namedHealthCheck struct {
+	name  string
+	check HealthCheck
+}
+
+// WithHealthCheck registers an additional readiness check reported under name
+func WithHealthCheck(name string, check HealthCheck) ServerOption {
+	return func(server *Server) {
+		server.healthChecks = append(server.healthChecks, namedHealthCheck{name: name, check: check})
+	}
+}
+
+// defaultHealthChecks checks the database, the schema version and the token maker.
+// The checks read the server fields when run, so options applied later are honored.
+func (server *Server) defaultHealthChecks() []namedHealthCheck {
+	expectedVersion, versionErr := util.LatestMigrationVersion(server.config.Database.MigrationURL)
+
+	return []namedHealthCheck{
+		{
+			name: "database",
+			check: func(ctx context.Context) error {
+				if server.store == nil {
+					return errors.New("store is not initialized")
+				}
+				return server.store.Ping(ctx)
+			},
+		},
+		{
+			name: "migration",
+			check: func(ctx context.Context) error {
+				if versionErr != nil {
+					return fmt.Errorf("cannot read migrations: %w", versionErr)
+				}
+				if server.store == nil {
+					return errors.New("store is not initialized")
+				}
+
+				version, dirty, err := server.store.MigrationVersion(ctx)
+				if err != nil {
+					return err
+				}
+				if dirty {
+					return fmt.Errorf("schema version %d is dirty", version)
+				}
+				if version != int64(expectedVersion) {
+					return fmt.Errorf("schema version is %d, expected %d", version, expectedVersion)
+				}
+				return nil
+			},
+		},
+		{
+			name: "token",
+			check: func(_ context.Context) error {
+				if server.tokenMaker == nil {
+					return errors.New("token maker is not initialized")
+				}
+
+				accessToken, _, err := server.tokenMaker.CreateToken("healthcheck", util.RoleUser, time.Minute)
+				if err != nil {
+					return err
+				}
+				_, err = server.tokenMaker.VerifyToken(accessToken)
+				return err
+			},
+		},
+	}
+}
+
+// healthResponse is a struct that represents the result of a probe.
+//
+//	@swagger:model
+type healthResponse struct {
+	// Status is ok when every check passed, fail otherwise.
+	// example: ok
+	Status string `json:"status"`
+
+	// Checks holds the result of every component check.
+	Checks map[string]componentHealth `json:"checks,omitempty"`
+}
+
+// componentHealth is a struct that represents the result of a single check.
+//
+//	@swagger:model
+type componentHealth struct {
+	// example: ok
+	Status string `json:"status"`
+
+	// Error is only reported in verbose mode.
+	Error string `json:"error,omitempty"`
+
+	// Duration is only reported in verbose mode.
+	// example: 1.2ms
+	Duration string `json:"duration,omitempty"`
+}
+
+// livez reports whether the process is alive.
+//
+//	@Summary		Liveness probe
+//	@Description	Report whether the process is alive, dependencies are not checked.
+//	@Tags			health
+//	@Produce		json
+//	@Success		200	{object}	healthResponse	"Alive"
+//	@Router			/livez [get]
+func (server *Server) livez(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, healthResponse{Status: healthStatusOK})
+}
+
+// readyz reports whether the server can serve traffic.
+//
+//	@Summary		Readiness probe
+//	@Description	Run the readiness checks of every component, fails once shutdown has begun.
+//	@Description	Errors and durations are reported with verbose=true, which requires an admin token.
+//	@Tags			health
+//	@Produce		json
+//	@Param			verbose	query		bool			false	"Report errors and durations"
+//	@Success		200		{object}	healthResponse	"Ready"
+//	@Failure		401		{object}	errorResponse	"Unauthorized"
+//	@Failure		403		{object}	errorResponse	"Forbidden"
+//	@Failure		503		{object}	healthResponse	"Not ready"
+//	@Router			/readyz [get]
+func (server *Server) readyz(ctx *gin.Context) {
+	verbose, _ := strconv.ParseBool(ctx.Query("verbose"))
+	if verbose {
+		payload, err := verifyAuthorization(server.tokenMaker, ctx.GetHeader(authorizationHeaderKey))
+		if err != nil {
+			writeProblem(ctx, http.StatusUnauthorized, err)
+			return
+		}
+		if payload.Role != util.RoleAdmin {
+			writeProblem(ctx, http.StatusForbidden, errors.New("permission denied"))
+			return
+		}
+	}
+
+	if server.shuttingDown.Load() {
+		ctx.JSON(http.StatusServiceUnavailable, healthResponse{
+			Status: healthStatusFail,
+			Checks: map[string]componentHealth{"shutdown": {Status: healthStatusFail}},
+		})
+		return
+	}
+
+	rsp := server.runHealthChecks(ctx.Request.Context(), verbose)
+	if rsp.Status != healthStatusOK {
+		ctx.JSON(http.StatusServiceUnavailable, rsp)
+		return
+	}
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+// runHealthChecks runs every check concurrently, each bounded by healthCheckTimeout
+func (server *Server) runHealthChecks(ctx context.Context, verbose bool) healthResponse {
+	rsp := healthResponse{
+		Status: healthStatusOK,
+		Checks: make(map[string]componentHealth, len(server.healthChecks)),
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, hc := range server.healthChecks {
+		wg.Add(1)
+		go func(hc namedHealthCheck) {
+			defer wg.Done()
+
+			checkCtx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+			defer cancel()
+
+			start := time.Now()
+			err := hc.check(checkCtx)
+
+			result := componentHealth{Status: healthStatusOK}
+			if err != nil {
+				result.Status = healthStatusFail
+			}
+			if verbose {
+				result.Duration = time.Since(start).String()
+				if err != nil {
+					result.Error = err.Error()
+				}
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			rsp.Checks[hc.name] = result
+			if err != nil {
+				rsp.Status = healthStatusFail
+			}
+		}(hc)
+	}
+	wg.Wait()
+
+	return rsp
+}
@@ -0,0 +1,235 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/lushenle/plam/pkg/db/mock"
+	"github.com/lushenle/plam/pkg/token"
+	"github.com/lushenle/plam/pkg/util"
+	"github.com/stretchr/testify/require"
+)
+
+func requireBodyHealth(t *testing.T, recorder *httptest.ResponseRecorder) healthResponse {
+	var rsp healthResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+	return rsp
+}
+
+func TestLivezAPI(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().Ping(gomock.Any()).Times(0)
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, "/v1/livez", nil)
+	require.NoError(t, err)
+
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, healthStatusOK, requireBodyHealth(t, recorder).Status)
+}
+
+func TestReadyzAPI(t *testing.T) {
+	latest, err := util.LatestMigrationVersion("")
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name          string
+		query         string
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:      "OK",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().Ping(gomock.Any()).Times(1).Return(nil)
+				store.EXPECT().MigrationVersion(gomock.Any()).Times(1).Return(int64(latest), false, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				rsp := requireBodyHealth(t, recorder)
+				require.Equal(t, healthStatusOK, rsp.Status)
+				require.Len(t, rsp.Checks, 3)
+				for _, check := range rsp.Checks {
+					require.Equal(t, healthStatusOK, check.Status)
+					require.Empty(t, check.Duration)
+				}
+			},
+		},
+		{
+			name:      "DatabaseDown",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().Ping(gomock.Any()).Times(1).Return(errors.New("connection refused"))
+				store.EXPECT().MigrationVersion(gomock.Any()).Times(1).Return(int64(0), false, errors.New("connection refused"))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+
+				rsp := requireBodyHealth(t, recorder)
+				require.Equal(t, healthStatusFail, rsp.Status)
+				require.Equal(t, healthStatusFail, rsp.Checks["database"].Status)
+				require.Empty(t, rsp.Checks["database"].Error)
+				require.Equal(t, healthStatusOK, rsp.Checks["token"].Status)
+			},
+		},
+		{
+			name:      "MigrationBehind",
+			query:     "?verbose=true",
+			setupAuth: addAdminAuthorization,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().Ping(gomock.Any()).Times(1).Return(nil)
+				store.EXPECT().MigrationVersion(gomock.Any()).Times(1).Return(int64(latest-1), false, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+
+				rsp := requireBodyHealth(t, recorder)
+				require.Equal(t, healthStatusFail, rsp.Checks["migration"].Status)
+				require.Contains(t, rsp.Checks["migration"].Error, "expected")
+				require.NotEmpty(t, rsp.Checks["migration"].Duration)
+			},
+		},
+		{
+			name:      "MigrationDirty",
+			query:     "?verbose=true",
+			setupAuth: addAdminAuthorization,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().Ping(gomock.Any()).Times(1).Return(nil)
+				store.EXPECT().MigrationVersion(gomock.Any()).Times(1).Return(int64(latest), true, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+				require.Contains(t, requireBodyHealth(t, recorder).Checks["migration"].Error, "dirty")
+			},
+		},
+		{
+			name:      "VerboseOK",
+			query:     "?verbose=true",
+			setupAuth: addAdminAuthorization,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().Ping(gomock.Any()).Times(1).Return(nil)
+				store.EXPECT().MigrationVersion(gomock.Any()).Times(1).Return(int64(latest), false, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				for _, check := range requireBodyHealth(t, recorder).Checks {
+					require.NotEmpty(t, check.Duration)
+				}
+			},
+		},
+		{
+			name:  "VerboseNoAuthorization",
+			query: "?verbose=true",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().Ping(gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:  "VerboseNoPermission",
+			query: "?verbose=true",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "user", util.RoleUser, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().Ping(gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/v1/readyz"+tc.query, nil)
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func addAdminAuthorization(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+	addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "admin", util.RoleAdmin, time.Minute)
+}
+
+func TestReadyzCustomCheckAPI(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().Ping(gomock.Any()).AnyTimes().Return(nil)
+	store.EXPECT().MigrationVersion(gomock.Any()).AnyTimes().Return(int64(0), false, nil)
+
+	server := newTestServer(t, store)
+	WithHealthCheck("cache", func(ctx context.Context) error {
+		_, ok := ctx.Deadline()
+		require.True(t, ok)
+		return errors.New("cache unavailable")
+	})(server)
+
+	rsp := server.runHealthChecks(context.Background(), true)
+	require.Equal(t, healthStatusFail, rsp.Status)
+	require.Len(t, rsp.Checks, 4)
+	require.Equal(t, "cache unavailable", rsp.Checks["cache"].Error)
+}
+
+func TestReadyzShuttingDownAPI(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().Ping(gomock.Any()).Times(0)
+
+	server := newTestServer(t, store)
+	require.NoError(t, server.Shutdown(context.Background()))
+
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodGet, "/v1/readyz", nil)
+	require.NoError(t, err)
+
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+	require.Equal(t, healthStatusFail, requireBodyHealth(t, recorder).Checks["shutdown"].Status)
+
+	// Liveness is not affected by shutdown
+	recorder = httptest.NewRecorder()
+	request, err = http.NewRequest(http.MethodGet, "/v1/livez", nil)
+	require.NoError(t, err)
+
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+}
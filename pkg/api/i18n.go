@@ -0,0 +1,169 @@
+package api
+
+import (
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/zh"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	enTranslations "github.com/go-playground/validator/v10/translations/en"
+	zhTranslations "github.com/go-playground/validator/v10/translations/zh"
+	"github.com/lushenle/plam/pkg/db"
+	"github.com/lushenle/plam/pkg/sso"
+	"github.com/lushenle/plam/pkg/token"
+	"golang.org/x/text/language"
+)
+
+// Supported locales, English is the fallback
+const (
+	localeEnglish = "en"
+	localeChinese = "zh"
+)
+
+const (
+	acceptLanguageHeader  = "Accept-Language"
+	contentLanguageHeader = "Content-Language"
+)
+
+// localeMatcher negotiates the Accept-Language header, in the order of the supported locales
+var localeMatcher = language.NewMatcher([]language.Tag{language.English, language.Chinese})
+
+// messages translates the details of domain errors, keyed by their English text.
+// Every domain message has a Chinese translation, see isDomainMessage.
+var messages = map[string]map[string]string{
+	localeChinese: {
+		"bad request":                      "请求无效",
+		"unauthorized":                     "未授权",
+		"forbidden":                        "禁止访问",
+		"not found":                        "未找到",
+		"conflict":                         "冲突",
+		"request entity too large":         "请求体过大",
+		"the request has invalid fields":   "请求包含无效字段",
+		"password does not satisfy policy": "密码不符合安全策略",
+		"resource not found":               "资源不存在",
+		"resource already exists":          "资源已存在",
+		"resource references a missing resource or is still referenced": "资源引用了不存在的资源或仍被其他资源引用",
+		"internal server error":                          "服务器内部错误",
+		"service unavailable":                            "服务暂不可用",
+		"route not found":                                "路由不存在",
+		"permission denied":                              "没有权限",
+		"too many requests":                              "请求过于频繁",
+		"invalid cursor":                                 "游标无效",
+		"invalid count parameter":                        "count 参数无效",
+		"request body is too large":                      "请求体过大",
+		"invalid username or password":                   "用户名或密码错误",
+		"password is incorrect":                          "密码错误",
+		"single sign-on failed":                          "单点登录失败",
+		sso.ErrNotAuthorized.Error():                     "用户不属于任何授权组",
+		db.ErrEmailRequired.Error():                      "身份提供者未提供电子邮件",
+		db.ErrProvisioningDisabled.Error():               "没有与该身份关联的用户，且注册未开放",
+		"unsupported authorization type":                 "不支持的授权类型",
+		"q cannot be combined with filters or sort":      "q 不能与过滤条件或排序同时使用",
+		"min_amount must not be greater than max_amount": "min_amount 不能大于 max_amount",
+		"created_from must be before created_to":         "created_from 必须早于 created_to",
+		"signup is disabled":                             "注册已关闭",
+		"signup requires an invitation code":             "注册需要邀请码",
+		"password login is disabled, use single sign-on": "密码登录已禁用，请使用单点登录",
+		"authorization header is not provided":           "未提供 Authorization 请求头",
+		"invalid authorization header format":            "Authorization 请求头格式无效",
+		"authorization payload is not found":             "未找到授权信息",
+		"invalid oidc state":                             "OIDC state 无效",
+		"authorization code is not provided":             "未提供授权码",
+		"invitation is invalid, expired or already used": "邀请码无效、已过期或已被使用",
+		token.ErrExpiredToken.Error():                    "令牌已过期",
+		token.ErrInvalidToken.Error():                    "令牌无效",
+		// Rules of the password policy, {0} is the parameter of the rule
+		"must be at least {0} characters long":         "长度至少为 {0} 个字符",
+		"must be at most {0} characters long":          "长度最多为 {0} 个字符",
+		"must contain an upper case letter":            "必须包含大写字母",
+		"must contain a lower case letter":             "必须包含小写字母",
+		"must contain a digit":                         "必须包含数字",
+		"must contain a symbol":                        "必须包含符号",
+		"must not contain the username":                "不能包含用户名",
+		"must not contain the email address":           "不能包含电子邮件地址",
+		"has appeared in a data breach":                "已在数据泄露中出现",
+		"must not reuse any of the last {0} passwords": "不能与最近 {0} 次使用的密码相同",
+	},
+}
+
+var (
+	registerTranslations sync.Once
+	translators          *ut.UniversalTranslator
+)
+
+// useTranslations registers the validator messages and the domain messages of every
+// supported locale
+func useTranslations() {
+	registerTranslations.Do(func() {
+		translators = ut.New(en.New(), en.New(), zh.New())
+
+		validate, ok := binding.Validator.Engine().(*validator.Validate)
+		if ok {
+			enTrans, _ := translators.GetTranslator(localeEnglish)
+			zhTrans, _ := translators.GetTranslator(localeChinese)
+			_ = enTranslations.RegisterDefaultTranslations(validate, enTrans)
+			_ = zhTranslations.RegisterDefaultTranslations(validate, zhTrans)
+		}
+
+		for locale, catalog := range messages {
+			trans, _ := translators.GetTranslator(locale)
+			for key, text := range catalog {
+				_ = trans.Add(key, text, false)
+			}
+		}
+	})
+}
+
+// requestLocale returns the locale of the response: the preferred locale of the
+// authenticated user, else the best match of the Accept-Language header
+func requestLocale(ctx *gin.Context) string {
+	if value, ok := ctx.Get(authorizationPayloadKey); ok {
+		if payload, ok := value.(*token.Payload); ok && isSupportedLocale(payload.Locale) {
+			return payload.Locale
+		}
+	}
+	return negotiateLocale(ctx.GetHeader(acceptLanguageHeader))
+}
+
+// negotiateLocale returns the supported locale best matching an Accept-Language header
+func negotiateLocale(acceptLanguage string) string {
+	tags, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil || len(tags) == 0 {
+		return localeEnglish
+	}
+	_, index, confidence := localeMatcher.Match(tags...)
+	if confidence == language.No || index == 0 {
+		return localeEnglish
+	}
+	return localeChinese
+}
+
+func isSupportedLocale(locale string) bool {
+	return locale == localeEnglish || locale == localeChinese
+}
+
+// isDomainMessage reports whether message is the detail of a domain error, the messages
+// written for clients
+func isDomainMessage(message string) bool {
+	_, ok := messages[localeChinese][message]
+	return ok
+}
+
+// translator returns the translator of locale
+func translator(locale string) ut.Translator {
+	useTranslations()
+	trans, _ := translators.GetTranslator(locale)
+	return trans
+}
+
+// translate returns the translation of an English message, or the message itself
+func translate(trans ut.Translator, message string) string {
+	text, err := trans.T(message)
+	if err != nil {
+		return message
+	}
+	return text
+}
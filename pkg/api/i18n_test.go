@@ -0,0 +1,170 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lushenle/plam/pkg/db"
+	"github.com/lushenle/plam/pkg/token"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNegotiateLocale(t *testing.T) {
+	testCases := []struct {
+		acceptLanguage string
+		want           string
+	}{
+		{acceptLanguage: "", want: localeEnglish},
+		{acceptLanguage: "zh-CN,zh;q=0.9,en;q=0.8", want: localeChinese},
+		{acceptLanguage: "zh-TW", want: localeChinese},
+		{acceptLanguage: "en-US,en;q=0.9,zh;q=0.8", want: localeEnglish},
+		{acceptLanguage: "fr-FR", want: localeEnglish},
+		{acceptLanguage: "fr;q=0.9,zh;q=0.5", want: localeChinese},
+		{acceptLanguage: "not a language;;", want: localeEnglish},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.acceptLanguage, func(t *testing.T) {
+			require.Equal(t, tc.want, negotiateLocale(tc.acceptLanguage))
+		})
+	}
+}
+
+func TestLocalizedProblem(t *testing.T) {
+	// Binding errors describe the request, they are sent in English
+	_, strconvErr := strconv.ParseBool("maybe")
+
+	testCases := []struct {
+		name           string
+		acceptLanguage string
+		payload        *token.Payload
+		status         int
+		err            error
+		wantCode       string
+		wantLocale     string
+		wantDetail     string
+	}{
+		{
+			name:       "Default",
+			status:     http.StatusNotFound,
+			err:        db.ErrRecordNotFound,
+			wantCode:   codeNotFound,
+			wantLocale: localeEnglish,
+			wantDetail: "resource not found",
+		},
+		{
+			name:           "AcceptLanguage",
+			acceptLanguage: "zh-CN,zh;q=0.9",
+			status:         http.StatusNotFound,
+			err:            db.ErrRecordNotFound,
+			wantCode:       codeNotFound,
+			wantLocale:     localeChinese,
+			wantDetail:     "资源不存在",
+		},
+		{
+			name:           "UserLocale",
+			acceptLanguage: "en-US",
+			payload:        &token.Payload{Locale: localeChinese},
+			status:         http.StatusForbidden,
+			err:            db.ErrInvalidInvitation,
+			wantCode:       codeForbidden,
+			wantLocale:     localeChinese,
+			wantDetail:     "邀请码无效、已过期或已被使用",
+		},
+		{
+			name:           "UserWithoutLocale",
+			acceptLanguage: "zh",
+			payload:        &token.Payload{},
+			status:         http.StatusConflict,
+			err:            db.ErrUniqueViolation,
+			wantCode:       codeConflict,
+			wantLocale:     localeChinese,
+			wantDetail:     "资源已存在",
+		},
+		{
+			name:           "FilterError",
+			acceptLanguage: "zh",
+			status:         http.StatusBadRequest,
+			err:            errSearchWithFilter,
+			wantCode:       codeBadRequest,
+			wantLocale:     localeChinese,
+			wantDetail:     "q 不能与过滤条件或排序同时使用",
+		},
+		{
+			name:           "Untranslated",
+			acceptLanguage: "zh",
+			status:         http.StatusBadRequest,
+			err:            strconvErr,
+			wantCode:       codeBadRequest,
+			wantLocale:     localeChinese,
+			wantDetail:     strconvErr.Error(),
+		},
+		{
+			name:           "Undisclosed",
+			acceptLanguage: "zh",
+			status:         http.StatusUnauthorized,
+			err:            errors.New("crypto/bcrypt: hashedPassword is not the hash of the given password"),
+			wantCode:       codeUnauthorized,
+			wantLocale:     localeChinese,
+			wantDetail:     "未授权",
+		},
+		{
+			name:       "UndisclosedDefault",
+			status:     http.StatusBadRequest,
+			err:        errors.New("oauth2: cannot fetch token: 400 Bad Request Response: {}"),
+			wantCode:   codeBadRequest,
+			wantLocale: localeEnglish,
+			wantDetail: "bad request",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(recorder)
+			ctx.Request = httptest.NewRequest(http.MethodGet, "/v1/projects", nil)
+			ctx.Request.Header.Set(acceptLanguageHeader, tc.acceptLanguage)
+			if tc.payload != nil {
+				ctx.Set(authorizationPayloadKey, tc.payload)
+			}
+
+			writeProblem(ctx, tc.status, tc.err)
+
+			problem := requireProblem(t, recorder, tc.status, tc.wantCode)
+			require.Equal(t, tc.wantLocale, recorder.Header().Get(contentLanguageHeader))
+			require.Equal(t, tc.wantDetail, problem.Detail)
+		})
+	}
+}
+
+func TestLocalizedValidationProblem(t *testing.T) {
+	useRequestFieldNames()
+
+	router := gin.New()
+	router.POST("/items", func(ctx *gin.Context) {
+		var req struct {
+			PageSize int32 `json:"page_size" binding:"required,min=5"`
+		}
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			writeProblem(ctx, http.StatusBadRequest, err)
+			return
+		}
+		ctx.JSON(http.StatusOK, req)
+	})
+
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodPost, "/items", strings.NewReader(`{"page_size": 1}`))
+	request.Header.Set(acceptLanguageHeader, "zh-CN")
+	router.ServeHTTP(recorder, request)
+
+	problem := requireProblem(t, recorder, http.StatusBadRequest, codeValidationFailed)
+	require.Equal(t, "请求包含无效字段", problem.Detail)
+	require.Equal(t, []fieldError{
+		{Field: "page_size", Rule: "min", Param: "5", Message: "page_size最小只能为5"},
+	}, problem.Errors)
+}
@@ -50,7 +50,7 @@ type createIncomeRequest struct {
 func (server *Server) createIncome(ctx *gin.Context) {
 	var req createIncomeRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errResponse(err))
+		writeProblem(ctx, http.StatusBadRequest, err)
 		return
 	}
 
@@ -61,53 +61,183 @@ func (server *Server) createIncome(ctx *gin.Context) {
 	}
 	income, err := server.store.CreateIncome(ctx, arg)
 	if err != nil {
-		errCode := db.ErrorCode(err)
-		if errCode == db.ForeignKeyViolation || errCode == db.UniqueViolation {
-			ctx.JSON(http.StatusForbidden, errResponse(err))
-			return
-		}
-
-		ctx.JSON(http.StatusInternalServerError, errResponse(err))
+		writeError(ctx, err)
 		return
 	}
+	recordCreated(ctx, resourceIncome, req.Amount)
 
 	ctx.JSON(http.StatusOK, income)
 }
 
-// listIncomes lists all incomes.
+// listIncomesRequest is the request to list incomes, optionally filtered and sorted.
 //
-//	@Summary		List all incomes
-//	@Description	List all incomes.
+//	@swagger:model
+type listIncomesRequest struct {
+	listRequest
+	listFilter
+
+	// Payee keeps the incomes whose payee contains Payee, ignoring case.
+	// example: john
+	// in: body
+	Payee string `json:"payee" form:"payee" binding:"omitempty,max=64"`
+
+	// ProjectID keeps the incomes of a project.
+	// example: 2b7a7b3e-6c1a-4b8e-9f4e-1c2d3e4f5a6b
+	// in: body
+	ProjectID string `json:"project_id" form:"project_id" binding:"omitempty,uuid"`
+
+	// Query searches the incomes like the search endpoint, it cannot be combined with filters or sort.
+	// example: john
+	// in: query
+	Query string `json:"q" form:"q" binding:"omitempty,max=128"`
+}
+
+// listIncomes lists incomes, searching them when q is set.
+//
+//	@Summary		List incomes
+//	@Description	List incomes, optionally filtered by amount, creation time and payee or project, and sorted by sort, or search them with q.
 //	@Tags			incomes
-//	@Accept			json
 //	@Produce		json
-//	@Param			request	body		listRequest	true	"List Request"
-//	@Success		200		{object}	[]db.Income
-//	@Failure		400		{object}	errorResponse	"Bad Request"
-//	@Failure		401		{object}	errorResponse	"Unauthorized"
-//	@Failure		403		{object}	errorResponse	"Forbidden"
-//	@Failure		500		{object}	errorResponse	"Internal Server Error"
-//	@Router			/incomes/all [post]
+//	@Param			request	query		listIncomesRequest		true	"List Request"
+//	@Param			count	query		bool					false	"Set to false to omit the total count"
+//	@Success		200		{object}	listResponse[db.Income]	"List of incomes"
+//	@Failure		400		{object}	errorResponse			"Bad Request"
+//	@Failure		401		{object}	errorResponse			"Unauthorized"
+//	@Failure		403		{object}	errorResponse			"Forbidden"
+//	@Failure		500		{object}	errorResponse			"Internal Server Error"
+//	@Router			/incomes [get]
 //	@security		ApiKeyAuth
 func (server *Server) listIncomes(ctx *gin.Context) {
-	var req listRequest
+	var req listIncomesRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		writeProblem(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	server.findIncomes(ctx, req)
+}
+
+// listIncomesPost lists incomes like listIncomes, with the request in the body.
+//
+//	@Summary		List incomes
+//	@Description	List incomes like GET /incomes. Deprecated, use GET /incomes.
+//	@Tags			incomes
+//	@Accept			json
+//	@Produce		json
+//	@Param			count	query		bool					false	"Set to false to omit the total count"
+//	@Param			request	body		listIncomesRequest		true	"List Request"
+//	@Success		200		{object}	listResponse[db.Income]	"List of incomes"
+//	@Failure		400		{object}	errorResponse			"Bad Request"
+//	@Failure		401		{object}	errorResponse			"Unauthorized"
+//	@Failure		403		{object}	errorResponse			"Forbidden"
+//	@Failure		500		{object}	errorResponse			"Internal Server Error"
+//	@Router			/incomes/all [post]
+//	@Deprecated
+//	@security	ApiKeyAuth
+func (server *Server) listIncomesPost(ctx *gin.Context) {
+	var req listIncomesRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errResponse(err))
+		writeProblem(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	arg := db.ListIncomesParams{
-		Offset: (req.PageID - 1) * req.PageSize,
-		Limit:  req.PageSize,
+	server.findIncomes(ctx, req)
+}
+
+// findIncomes responds with the page of incomes selected by req
+func (server *Server) findIncomes(ctx *gin.Context, req listIncomesRequest) {
+	filter, err := req.dbFilter(db.IncomeSortColumns)
+	if err != nil {
+		writeProblem(ctx, http.StatusBadRequest, err)
+		return
 	}
+	filter.Name = req.Payee
+	filter.ProjectID = parseProjectID(req.ProjectID)
 
-	incomes, err := server.store.ListIncomes(ctx, arg)
+	if req.Query != "" && !filter.IsZero() {
+		writeProblem(ctx, http.StatusBadRequest, errSearchWithFilter)
+		return
+	}
+
+	p, err := requestPage(ctx, req.PageID, req.PageSize, req.Cursor)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errResponse(err))
+		writeProblem(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	response, err := fetchPage(p, server.incomeQueries(ctx, req.Query, filter))
+	if err != nil {
+		if errors.Is(err, errInvalidCursor) {
+			writeProblem(ctx, http.StatusBadRequest, err)
+			return
+		}
+		writeError(ctx, err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, incomes)
+	ctx.JSON(http.StatusOK, response)
+}
+
+// incomeQueries returns the queries searching incomes for query when set, filtering them otherwise
+func (server *Server) incomeQueries(ctx *gin.Context, query string, filter db.ListFilter) pageQueries[db.Income] {
+	switch {
+	case query != "":
+		return pageQueries[db.Income]{
+			offset: func(offset, limit int32) ([]db.Income, error) {
+				return server.store.SearchIncomes(ctx, db.SearchIncomesParams{Query: query, Offset: offset, Limit: limit})
+			},
+			after: func(cursor pageCursor, limit int32) ([]db.Income, error) {
+				return server.store.SearchIncomesAfter(ctx, db.SearchIncomesAfterParams{
+					Query:     query,
+					CreatedAt: cursor.CreatedAt,
+					ID:        cursor.ID,
+					Limit:     limit,
+				})
+			},
+			before: func(cursor pageCursor, limit int32) ([]db.Income, error) {
+				return server.store.SearchIncomesBefore(ctx, db.SearchIncomesBeforeParams{
+					Query:     query,
+					CreatedAt: cursor.CreatedAt,
+					ID:        cursor.ID,
+					Limit:     limit,
+				})
+			},
+			count: func() (int64, error) {
+				return server.store.CountSearchIncomes(ctx, query)
+			},
+			key: incomeCursor,
+		}
+	case !filter.IsZero():
+		return filterPageQueries(filter,
+			func(arg db.FilterParams) ([]db.Income, error) {
+				return server.store.FilterIncomes(ctx, arg)
+			},
+			func(filter db.ListFilter) (int64, error) {
+				return server.store.CountFilterIncomes(ctx, filter)
+			},
+			incomeCursor, db.Income.SortValue)
+	}
+
+	return pageQueries[db.Income]{
+		offset: func(offset, limit int32) ([]db.Income, error) {
+			return server.store.ListIncomes(ctx, db.ListIncomesParams{Offset: offset, Limit: limit})
+		},
+		after: func(cursor pageCursor, limit int32) ([]db.Income, error) {
+			return server.store.ListIncomesAfter(ctx, db.ListIncomesAfterParams{CreatedAt: cursor.CreatedAt, ID: cursor.ID, Limit: limit})
+		},
+		before: func(cursor pageCursor, limit int32) ([]db.Income, error) {
+			return server.store.ListIncomesBefore(ctx, db.ListIncomesBeforeParams{CreatedAt: cursor.CreatedAt, ID: cursor.ID, Limit: limit})
+		},
+		count: func() (int64, error) {
+			return server.store.CountIncomes(ctx)
+		},
+		key: incomeCursor,
+	}
+}
+
+// incomeCursor returns the position of income in list and search results
+func incomeCursor(income db.Income) pageCursor {
+	return pageCursor{CreatedAt: income.CreatedAt, ID: income.ID}
 }
 
 // getIncome gets an income by ID.
@@ -129,59 +259,44 @@ func (server *Server) listIncomes(ctx *gin.Context) {
 func (server *Server) getIncome(ctx *gin.Context) {
 	var req getRequest
 	if err := ctx.ShouldBindUri(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errResponse(err))
+		writeProblem(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	income, err := server.store.GetIncome(ctx, uuid.MustParse(req.ID))
 	if err != nil {
-		if errors.Is(err, db.ErrRecordNotFound) {
-			ctx.JSON(http.StatusNotFound, errResponse(err))
-			return
-		}
-
-		ctx.JSON(http.StatusInternalServerError, errResponse(err))
+		writeError(ctx, err)
 		return
 	}
 
 	ctx.JSON(http.StatusOK, income)
 }
 
-// searchIncomes searches incomes by payee.
+// searchIncomes searches incomes like listIncomes with q set.
 //
 //	@Summary		Search incomes
-//	@Description	Search incomes by payee.
+//	@Description	Search incomes like GET /incomes?q=. Deprecated, use GET /incomes?q=.
 //	@Tags			incomes
 //	@Accept			json
 //	@Produce		json
-//	@Param			request	body		searchRequest	true	"Search Request"
-//	@Success		200		{object}	[]db.Income		"Incomes found"
-//	@Failure		400		{object}	errorResponse	"Bad Request"
-//	@Failure		401		{object}	errorResponse	"Unauthorized"
-//	@Failure		403		{object}	errorResponse	"Forbidden"
-//	@Failure		500		{object}	errorResponse	"Internal Server Error"
+//	@Param			count	query		bool					false	"Set to false to omit the total count"
+//	@Param			request	body		searchRequest			true	"Search Request"
+//	@Success		200		{object}	listResponse[db.Income]	"Incomes found"
+//	@Failure		400		{object}	errorResponse			"Bad Request"
+//	@Failure		401		{object}	errorResponse			"Unauthorized"
+//	@Failure		403		{object}	errorResponse			"Forbidden"
+//	@Failure		500		{object}	errorResponse			"Internal Server Error"
 //	@Router			/incomes/search [post]
-//	@security		ApiKeyAuth
+//	@Deprecated
+//	@security	ApiKeyAuth
 func (server *Server) searchIncomes(ctx *gin.Context) {
 	var req searchRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errResponse(err))
-		return
-	}
-
-	arg := db.SearchIncomesParams{
-		Payee:  req.Query,
-		Offset: (req.PageID - 1) * req.PageSize,
-		Limit:  req.PageSize,
-	}
-
-	incomes, err := server.store.SearchIncomes(ctx, arg)
-	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errResponse(err))
+		writeProblem(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, incomes)
+	server.findIncomes(ctx, listIncomesRequest{listRequest: req.pagination(), Query: req.Query})
 }
 
 // deleteIncome deletes an income by ID.
@@ -202,19 +317,16 @@ func (server *Server) searchIncomes(ctx *gin.Context) {
 func (server *Server) deleteIncome(ctx *gin.Context) {
 	var req getRequest
 	if err := ctx.ShouldBindUri(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errResponse(err))
+		writeProblem(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	income, err := server.store.DeleteIncome(ctx, uuid.MustParse(req.ID))
 	if err != nil {
-		if errors.Is(err, db.ErrRecordNotFound) {
-			ctx.JSON(http.StatusNotFound, errResponse(err))
-			return
-		}
-		ctx.JSON(http.StatusInternalServerError, errResponse(err))
+		writeError(ctx, err)
 		return
 	}
+	recordDeleted(ctx, resourceIncome)
 
 	ctx.JSON(http.StatusOK, income)
 }
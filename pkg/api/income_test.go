@@ -136,7 +136,7 @@ func TestCreateIncomeAPI(t *testing.T) {
 				store.EXPECT().CreateIncome(gomock.Any(), gomock.Any()).Times(1).Return(db.Income{}, db.ErrForeignKeyViolation)
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
-				require.Equal(t, http.StatusForbidden, recorder.Code)
+				requireProblem(t, recorder, http.StatusConflict, codeConflict)
 			},
 		},
 	}
@@ -272,269 +272,38 @@ func TestGetIncomeAPI(t *testing.T) {
 }
 
 func TestListIncomeAPI(t *testing.T) {
-	project := randomProject(t)
+	resource := incomeListResource(t)
 	user, _ := randomUser(t)
-
-	n := 6
-	incomes := make([]db.Income, n)
-	for i := 0; i < n; i++ {
-		incomes[i] = randomIncome(t, project)
-	}
-
-	testCases := []struct {
-		name          string
-		body          gin.H
-		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
-		buildStubs    func(store *mockdb.MockStore)
-		checkResponse func(recorder *httptest.ResponseRecorder)
-	}{
-		{
-			name: "OK",
-			body: gin.H{
-				"page_id":   1,
-				"page_size": n,
-			},
-			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, util.RoleUser, time.Minute)
-			},
-			buildStubs: func(store *mockdb.MockStore) {
-				arg := db.ListIncomesParams{
-					Offset: 0,
-					Limit:  int32(n),
-				}
-				store.EXPECT().ListIncomes(gomock.Any(), gomock.Eq(arg)).Times(1).Return(incomes, nil)
-			},
-			checkResponse: func(recorder *httptest.ResponseRecorder) {
-				require.Equal(t, http.StatusOK, recorder.Code)
-				requireBodyMatchIncomes(t, recorder.Body, incomes)
-			},
+	n := len(resource.items)
+	projectID := uuid.New()
+
+	testListPostAPI(t, resource, listTestCase{
+		name: "FilteredByProject",
+		body: gin.H{
+			"page_size":  n,
+			"payee":      "john",
+			"project_id": projectID,
 		},
-		{
-			name: "NoAuthorization",
-			body: gin.H{
-				"page_id":   1,
-				"page_size": n,
-			},
-			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {},
-			buildStubs: func(store *mockdb.MockStore) {
-				store.EXPECT().ListIncomes(gomock.Any(), gomock.Any()).Times(0)
-			},
-			checkResponse: func(recorder *httptest.ResponseRecorder) {
-				require.Equal(t, http.StatusUnauthorized, recorder.Code)
-			},
+		setupAuth: userAuth(user.Username),
+		buildStubs: func(store *mockdb.MockStore) {
+			filter := db.ListFilter{Name: "john", ProjectID: &projectID}
+			arg := db.FilterParams{ListFilter: filter, Limit: int32(n) + 1}
+			store.EXPECT().FilterIncomes(gomock.Any(), gomock.Eq(arg)).Times(1).Return(resource.items, nil)
+			store.EXPECT().CountFilterIncomes(gomock.Any(), gomock.Eq(filter)).Times(1).Return(int64(n), nil)
 		},
-		{
-			name: "InternalError",
-			body: gin.H{
-				"page_id":   1,
-				"page_size": n,
-			},
-			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, util.RoleUser, time.Minute)
-			},
-			buildStubs: func(store *mockdb.MockStore) {
-				store.EXPECT().ListIncomes(gomock.Any(), gomock.Any()).Times(1).Return([]db.Income{}, sql.ErrConnDone)
-			},
-			checkResponse: func(recorder *httptest.ResponseRecorder) {
-				require.Equal(t, http.StatusInternalServerError, recorder.Code)
-			},
+		checkResponse: func(recorder *httptest.ResponseRecorder) {
+			require.Equal(t, http.StatusOK, recorder.Code)
+			requireBodyMatchList(t, recorder.Body, resource.items)
 		},
-		{
-			name: "InvalidPageID",
-			body: gin.H{
-				"page_id":   -1,
-				"page_size": n,
-			},
-			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, util.RoleUser, time.Minute)
-			},
-			buildStubs: func(store *mockdb.MockStore) {
-				store.EXPECT().ListIncomes(gomock.Any(), gomock.Any()).Times(0)
-			},
-			checkResponse: func(recorder *httptest.ResponseRecorder) {
-				require.Equal(t, http.StatusBadRequest, recorder.Code)
-			},
-		},
-		{
-			name: "InvalidPageSize",
-			body: gin.H{
-				"page_id":   1,
-				"page_size": 1000,
-			},
-			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, util.RoleUser, time.Minute)
-			},
-			buildStubs: func(store *mockdb.MockStore) {
-				store.EXPECT().ListIncomes(gomock.Any(), gomock.Any()).Times(0)
-			},
-			checkResponse: func(recorder *httptest.ResponseRecorder) {
-				require.Equal(t, http.StatusBadRequest, recorder.Code)
-			},
-		},
-	}
-
-	for _, tc := range testCases {
-		t.Run(tc.name, func(t *testing.T) {
-			ctrl := gomock.NewController(t)
-			defer ctrl.Finish()
-
-			store := mockdb.NewMockStore(ctrl)
-			tc.buildStubs(store)
-
-			server := newTestServer(t, store)
-			recorder := httptest.NewRecorder()
-
-			// Marshal body data to JSON
-			data, err := json.Marshal(tc.body)
-			require.NoError(t, err)
-
-			url := "/v1/incomes/all"
-			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
-			require.NoError(t, err)
-
-			request.Header.Set("Content-Type", "application/json")
-			tc.setupAuth(t, request, server.tokenMaker)
+	})
+}
 
-			server.router.ServeHTTP(recorder, request)
-			tc.checkResponse(recorder)
-		})
-	}
+func TestListIncomesGetAPI(t *testing.T) {
+	testListGetAPI(t, incomeListResource(t))
 }
 
 func TestSearchIncomeAPI(t *testing.T) {
-	project := randomProject(t)
-	user, _ := randomUser(t)
-
-	n := 6
-	incomes := make([]db.Income, n)
-	for i := 0; i < n; i++ {
-		incomes[i] = randomIncome(t, project)
-	}
-
-	testCases := []struct {
-		name          string
-		body          gin.H
-		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
-		buildStubs    func(store *mockdb.MockStore)
-		checkResponse func(recorder *httptest.ResponseRecorder)
-	}{
-		{
-			name: "OK",
-			body: gin.H{
-				"query":     incomes[0].Payee,
-				"page_id":   1,
-				"page_size": n,
-			},
-			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, util.RoleUser, time.Minute)
-			},
-			buildStubs: func(store *mockdb.MockStore) {
-				arg := db.SearchIncomesParams{
-					Payee:  incomes[0].Payee,
-					Offset: 0,
-					Limit:  int32(n),
-				}
-				store.EXPECT().SearchIncomes(gomock.Any(), gomock.Eq(arg)).Times(1).Return(incomes, nil)
-			},
-			checkResponse: func(recorder *httptest.ResponseRecorder) {
-				require.Equal(t, http.StatusOK, recorder.Code)
-				requireBodyMatchIncomes(t, recorder.Body, incomes)
-			},
-		},
-		{
-			name: "NoAuthorization",
-			body: gin.H{
-				"query":     incomes[0].Payee,
-				"page_id":   1,
-				"page_size": n,
-			},
-			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {},
-			buildStubs: func(store *mockdb.MockStore) {
-				store.EXPECT().SearchIncomes(gomock.Any(), gomock.Any()).Times(0)
-			},
-			checkResponse: func(recorder *httptest.ResponseRecorder) {
-				require.Equal(t, http.StatusUnauthorized, recorder.Code)
-			},
-		},
-		{
-			name: "InternalError",
-			body: gin.H{
-				"query":     incomes[0].Payee,
-				"page_id":   1,
-				"page_size": n,
-			},
-			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, util.RoleUser, time.Minute)
-			},
-			buildStubs: func(store *mockdb.MockStore) {
-				store.EXPECT().SearchIncomes(gomock.Any(), gomock.Any()).Times(1).Return([]db.Income{}, sql.ErrConnDone)
-			},
-			checkResponse: func(recorder *httptest.ResponseRecorder) {
-				require.Equal(t, http.StatusInternalServerError, recorder.Code)
-			},
-		},
-		{
-			name: "InvalidPageID",
-			body: gin.H{
-				"query":     incomes[0].Payee,
-				"page_id":   -1,
-				"page_size": n,
-			},
-			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, util.RoleUser, time.Minute)
-			},
-			buildStubs: func(store *mockdb.MockStore) {
-				store.EXPECT().SearchIncomes(gomock.Any(), gomock.Any()).Times(0)
-			},
-			checkResponse: func(recorder *httptest.ResponseRecorder) {
-				require.Equal(t, http.StatusBadRequest, recorder.Code)
-			},
-		},
-		{
-			name: "InvalidPageSize",
-			body: gin.H{
-				"query":     incomes[0].Payee,
-				"page_id":   1,
-				"page_size": 10000,
-			},
-			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, util.RoleUser, time.Minute)
-			},
-			buildStubs: func(store *mockdb.MockStore) {
-				store.EXPECT().SearchIncomes(gomock.Any(), gomock.Any()).Times(0)
-			},
-			checkResponse: func(recorder *httptest.ResponseRecorder) {
-				require.Equal(t, http.StatusBadRequest, recorder.Code)
-			},
-		},
-	}
-
-	for _, tc := range testCases {
-		t.Run(tc.name, func(t *testing.T) {
-			ctrl := gomock.NewController(t)
-			defer ctrl.Finish()
-
-			store := mockdb.NewMockStore(ctrl)
-			tc.buildStubs(store)
-
-			server := newTestServer(t, store)
-			recorder := httptest.NewRecorder()
-
-			// Marshal body data to JSON
-			data, err := json.Marshal(tc.body)
-			require.NoError(t, err)
-
-			url := "/v1/incomes/search"
-			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
-			require.NoError(t, err)
-
-			request.Header.Set("Content-Type", "application/json")
-			tc.setupAuth(t, request, server.tokenMaker)
-
-			server.router.ServeHTTP(recorder, request)
-			tc.checkResponse(recorder)
-		})
-	}
+	testSearchPostAPI(t, incomeListResource(t))
 }
 
 func TestDeleteIncomeAPI(t *testing.T) {
@@ -675,12 +444,46 @@ func requireBodyMatchIncome(t *testing.T, body *bytes.Buffer, income db.Income)
 	require.Equal(t, income, gotIncome)
 }
 
-func requireBodyMatchIncomes(t *testing.T, body *bytes.Buffer, incomes []db.Income) {
-	data, err := io.ReadAll(body)
-	require.NoError(t, err)
+// incomeListResource returns the income list endpoints with random incomes as results
+func incomeListResource(t *testing.T) listResource[db.Income] {
+	project := randomProject(t)
+	items := make([]db.Income, 6)
+	for i := range items {
+		items[i] = randomIncome(t, project)
+	}
 
-	var gotIncomes []db.Income
-	err = json.Unmarshal(data, &gotIncomes)
-	require.NoError(t, err)
-	require.Equal(t, incomes, gotIncomes)
+	return listResource[db.Income]{
+		path:      "/v1/incomes",
+		nameField: "payee",
+		items:     items,
+		stubs: listStubs{
+			list: func(store *mockdb.MockStore, offset, limit int32) *gomock.Call {
+				return store.EXPECT().ListIncomes(gomock.Any(), gomock.Eq(db.ListIncomesParams{Offset: offset, Limit: limit}))
+			},
+			listAfter: func(store *mockdb.MockStore, cursor pageCursor, limit int32) *gomock.Call {
+				arg := db.ListIncomesAfterParams{CreatedAt: cursor.CreatedAt, ID: cursor.ID, Limit: limit}
+				return store.EXPECT().ListIncomesAfter(gomock.Any(), gomock.Eq(arg))
+			},
+			count: func(store *mockdb.MockStore) *gomock.Call {
+				return store.EXPECT().CountIncomes(gomock.Any())
+			},
+			filter: func(store *mockdb.MockStore, arg db.FilterParams) *gomock.Call {
+				return store.EXPECT().FilterIncomes(gomock.Any(), gomock.Eq(arg))
+			},
+			countFilter: func(store *mockdb.MockStore, filter db.ListFilter) *gomock.Call {
+				return store.EXPECT().CountFilterIncomes(gomock.Any(), gomock.Eq(filter))
+			},
+			search: func(store *mockdb.MockStore, query string, offset, limit int32) *gomock.Call {
+				arg := db.SearchIncomesParams{Query: query, Offset: offset, Limit: limit}
+				return store.EXPECT().SearchIncomes(gomock.Any(), gomock.Eq(arg))
+			},
+			searchAfter: func(store *mockdb.MockStore, query string, cursor pageCursor, limit int32) *gomock.Call {
+				arg := db.SearchIncomesAfterParams{Query: query, CreatedAt: cursor.CreatedAt, ID: cursor.ID, Limit: limit}
+				return store.EXPECT().SearchIncomesAfter(gomock.Any(), gomock.Eq(arg))
+			},
+			countSearch: func(store *mockdb.MockStore, query string) *gomock.Call {
+				return store.EXPECT().CountSearchIncomes(gomock.Any(), gomock.Eq(query))
+			},
+		},
+	}
 }
@@ -0,0 +1,87 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lushenle/plam/pkg/db"
+	"github.com/lushenle/plam/pkg/token"
+	"github.com/lushenle/plam/pkg/util"
+)
+
+const defaultInvitationDuration = 72 * time.Hour
+
+// createInvitationRequest is a struct that represents the request to create an invitation.
+//
+//	@swagger:model
+type createInvitationRequest struct {
+	// Email the invitation is bound to.
+	// Required: true
+	// example: john_doe@example.com
+	// in: body
+	// format: email
+	Email string `json:"email" binding:"required,email"`
+
+	// Role the invited user gets, defaults to user.
+	// example: user
+	// in: body
+	// enum: admin,user
+	Role string `json:"role" binding:"omitempty,oneof=admin user"`
+}
+
+// createInvitation creates a single-use invitation code.
+//
+//	@Summary		Create an invitation
+//	@Description	Create a single-use, expiring invitation code bound to an email and role.
+//	@Tags			invitations
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		createInvitationRequest	true	"Create Invitation Request"
+//	@Success		200		{object}	db.Invitation			"Invitation created"
+//	@Failure		400		{object}	errorResponse			"Bad Request"
+//	@Failure		401		{object}	errorResponse			"Unauthorized"
+//	@Failure		403		{object}	errorResponse			"Forbidden"
+//	@Failure		500		{object}	errorResponse			"Internal Server Error"
+//	@Router			/invitations [post]
+//	@security		ApiKeyAuth
+func (server *Server) createInvitation(ctx *gin.Context) {
+	var req createInvitationRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		writeProblem(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	role := req.Role
+	if role == "" {
+		role = util.RoleUser
+	}
+
+	code, err := util.RandomToken(24)
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	duration := server.config.Signup.InvitationDuration
+	if duration <= 0 {
+		duration = defaultInvitationDuration
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	arg := db.CreateInvitationParams{
+		Code:      code,
+		Email:     req.Email,
+		Role:      role,
+		CreatedBy: authPayload.Username,
+		ExpiresAt: time.Now().Add(duration),
+	}
+
+	invitation, err := server.store.CreateInvitation(ctx, arg)
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, invitation)
+}
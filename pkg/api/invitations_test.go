@@ -0,0 +1,204 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/lushenle/plam/pkg/db"
+	mockdb "github.com/lushenle/plam/pkg/db/mock"
+	"github.com/lushenle/plam/pkg/token"
+	"github.com/lushenle/plam/pkg/util"
+	"github.com/stretchr/testify/require"
+)
+
+func randomInvitation(t *testing.T, createdBy string) db.Invitation {
+	code, err := util.RandomToken(24)
+	require.NoError(t, err)
+
+	return db.Invitation{
+		Code:      code,
+		Email:     util.RandomEmail(),
+		Role:      util.RoleUser,
+		CreatedBy: createdBy,
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+}
+
+func TestCreateInvitationAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	invitation := randomInvitation(t, user.Username)
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{
+				"email": invitation.Email,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, util.RoleAdmin, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateInvitation(gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(_ any, arg db.CreateInvitationParams) (db.Invitation, error) {
+						require.NotEmpty(t, arg.Code)
+						require.Equal(t, invitation.Email, arg.Email)
+						require.Equal(t, util.RoleUser, arg.Role)
+						require.Equal(t, user.Username, arg.CreatedBy)
+						require.WithinDuration(t, time.Now().Add(defaultInvitationDuration), arg.ExpiresAt, time.Second)
+						return invitation, nil
+					})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchInvitation(t, recorder.Body, invitation)
+			},
+		},
+		{
+			name: "AdminRole",
+			body: gin.H{
+				"email": invitation.Email,
+				"role":  util.RoleAdmin,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, util.RoleAdmin, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateInvitation(gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(_ any, arg db.CreateInvitationParams) (db.Invitation, error) {
+						require.Equal(t, util.RoleAdmin, arg.Role)
+						return invitation, nil
+					})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "NoPermission",
+			body: gin.H{
+				"email": invitation.Email,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, util.RoleUser, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateInvitation(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "NoAuthorization",
+			body: gin.H{
+				"email": invitation.Email,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateInvitation(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidEmail",
+			body: gin.H{
+				"email": "invalid-email233",
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, util.RoleAdmin, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateInvitation(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidRole",
+			body: gin.H{
+				"email": invitation.Email,
+				"role":  "root",
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, util.RoleAdmin, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateInvitation(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InternalError",
+			body: gin.H{
+				"email": invitation.Email,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, util.RoleAdmin, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateInvitation(gomock.Any(), gomock.Any()).Times(1).Return(db.Invitation{}, sql.ErrConnDone)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			url := "/v1/invitations"
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func requireBodyMatchInvitation(t *testing.T, body *bytes.Buffer, invitation db.Invitation) {
+	data, err := io.ReadAll(body)
+	require.NoError(t, err)
+
+	var gotInvitation db.Invitation
+	err = json.Unmarshal(data, &gotInvitation)
+	require.NoError(t, err)
+
+	require.Equal(t, invitation.Code, gotInvitation.Code)
+	require.Equal(t, invitation.Email, gotInvitation.Email)
+	require.Equal(t, invitation.Role, gotInvitation.Role)
+	require.Equal(t, invitation.CreatedBy, gotInvitation.CreatedBy)
+	require.WithinDuration(t, invitation.ExpiresAt, gotInvitation.ExpiresAt, time.Second)
+}
@@ -49,7 +49,7 @@ type createLoanRequest struct {
 func (server *Server) createLoan(ctx *gin.Context) {
 	var req createLoanRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errResponse(err))
+		writeProblem(ctx, http.StatusBadRequest, err)
 		return
 	}
 
@@ -61,51 +61,177 @@ func (server *Server) createLoan(ctx *gin.Context) {
 
 	loan, err := server.store.CreateLoan(ctx, arg)
 	if err != nil {
-		if err == db.ErrUniqueViolation {
-			ctx.JSON(http.StatusForbidden, errResponse(err))
-			return
-		}
-		ctx.JSON(http.StatusInternalServerError, errResponse(err))
+		writeError(ctx, err)
 		return
 	}
+	recordCreated(ctx, resourceLoan, req.Amount)
 
 	ctx.JSON(http.StatusOK, loan)
 }
 
-// listLoans lists all loans.
+// listLoansRequest is the request to list loans, optionally filtered and sorted.
+//
+//	@swagger:model
+type listLoansRequest struct {
+	listRequest
+	listFilter
+
+	// Borrower keeps the loans whose borrower contains Borrower, ignoring case.
+	// example: john
+	// in: body
+	Borrower string `json:"borrower" form:"borrower" binding:"omitempty,max=64"`
+
+	// Query searches the loans like the search endpoint, it cannot be combined with filters or sort.
+	// example: john
+	// in: query
+	Query string `json:"q" form:"q" binding:"omitempty,max=128"`
+}
+
+// listLoans lists loans, searching them when q is set.
 //
-//	@Summary		List all loans
-//	@Description	List all loans.
+//	@Summary		List loans
+//	@Description	List loans, optionally filtered by amount, creation time and borrower, and sorted by sort, or search them with q.
 //	@Tags			loans
-//	@Accept			json
 //	@Produce		json
-//	@Param			request	body		listRequest		true	"List Request"
-//	@Success		200		{object}	[]db.Loan		"List of loans"
-//	@Failure		400		{object}	errorResponse	"Bad Request"
-//	@Failure		401		{object}	errorResponse	"Unauthorized"
-//	@Failure		403		{object}	errorResponse	"Forbidden"
-//	@Failure		500		{object}	errorResponse	"Internal Server Error"
-//	@Router			/loans/all [post]
+//	@Param			request	query		listLoansRequest		true	"List Request"
+//	@Param			count	query		bool					false	"Set to false to omit the total count"
+//	@Success		200		{object}	listResponse[db.Loan]	"List of loans"
+//	@Failure		400		{object}	errorResponse			"Bad Request"
+//	@Failure		401		{object}	errorResponse			"Unauthorized"
+//	@Failure		403		{object}	errorResponse			"Forbidden"
+//	@Failure		500		{object}	errorResponse			"Internal Server Error"
+//	@Router			/loans [get]
 //	@security		ApiKeyAuth
 func (server *Server) listLoans(ctx *gin.Context) {
-	var req listRequest
+	var req listLoansRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		writeProblem(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	server.findLoans(ctx, req)
+}
+
+// listLoansPost lists loans like listLoans, with the request in the body.
+//
+//	@Summary		List loans
+//	@Description	List loans like GET /loans. Deprecated, use GET /loans.
+//	@Tags			loans
+//	@Accept			json
+//	@Produce		json
+//	@Param			count	query		bool					false	"Set to false to omit the total count"
+//	@Param			request	body		listLoansRequest		true	"List Request"
+//	@Success		200		{object}	listResponse[db.Loan]	"List of loans"
+//	@Failure		400		{object}	errorResponse			"Bad Request"
+//	@Failure		401		{object}	errorResponse			"Unauthorized"
+//	@Failure		403		{object}	errorResponse			"Forbidden"
+//	@Failure		500		{object}	errorResponse			"Internal Server Error"
+//	@Router			/loans/all [post]
+//	@Deprecated
+//	@security	ApiKeyAuth
+func (server *Server) listLoansPost(ctx *gin.Context) {
+	var req listLoansRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errResponse(err))
+		writeProblem(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	arg := db.ListLoansParams{
-		Offset: (req.PageID - 1) * req.PageSize,
-		Limit:  req.PageSize,
+	server.findLoans(ctx, req)
+}
+
+// findLoans responds with the page of loans selected by req
+func (server *Server) findLoans(ctx *gin.Context, req listLoansRequest) {
+	filter, err := req.dbFilter(db.LoanSortColumns)
+	if err != nil {
+		writeProblem(ctx, http.StatusBadRequest, err)
+		return
 	}
+	filter.Name = req.Borrower
 
-	loans, err := server.store.ListLoans(ctx, arg)
+	if req.Query != "" && !filter.IsZero() {
+		writeProblem(ctx, http.StatusBadRequest, errSearchWithFilter)
+		return
+	}
+
+	p, err := requestPage(ctx, req.PageID, req.PageSize, req.Cursor)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errResponse(err))
+		writeProblem(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	response, err := fetchPage(p, server.loanQueries(ctx, req.Query, filter))
+	if err != nil {
+		if errors.Is(err, errInvalidCursor) {
+			writeProblem(ctx, http.StatusBadRequest, err)
+			return
+		}
+		writeError(ctx, err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, loans)
+	ctx.JSON(http.StatusOK, response)
+}
+
+// loanQueries returns the queries searching loans for query when set, filtering them otherwise
+func (server *Server) loanQueries(ctx *gin.Context, query string, filter db.ListFilter) pageQueries[db.Loan] {
+	switch {
+	case query != "":
+		return pageQueries[db.Loan]{
+			offset: func(offset, limit int32) ([]db.Loan, error) {
+				return server.store.SearchLoans(ctx, db.SearchLoansParams{Query: query, Offset: offset, Limit: limit})
+			},
+			after: func(cursor pageCursor, limit int32) ([]db.Loan, error) {
+				return server.store.SearchLoansAfter(ctx, db.SearchLoansAfterParams{
+					Query:     query,
+					CreatedAt: cursor.CreatedAt,
+					ID:        cursor.ID,
+					Limit:     limit,
+				})
+			},
+			before: func(cursor pageCursor, limit int32) ([]db.Loan, error) {
+				return server.store.SearchLoansBefore(ctx, db.SearchLoansBeforeParams{
+					Query:     query,
+					CreatedAt: cursor.CreatedAt,
+					ID:        cursor.ID,
+					Limit:     limit,
+				})
+			},
+			count: func() (int64, error) {
+				return server.store.CountSearchLoans(ctx, query)
+			},
+			key: loanCursor,
+		}
+	case !filter.IsZero():
+		return filterPageQueries(filter,
+			func(arg db.FilterParams) ([]db.Loan, error) {
+				return server.store.FilterLoans(ctx, arg)
+			},
+			func(filter db.ListFilter) (int64, error) {
+				return server.store.CountFilterLoans(ctx, filter)
+			},
+			loanCursor, db.Loan.SortValue)
+	}
+
+	return pageQueries[db.Loan]{
+		offset: func(offset, limit int32) ([]db.Loan, error) {
+			return server.store.ListLoans(ctx, db.ListLoansParams{Offset: offset, Limit: limit})
+		},
+		after: func(cursor pageCursor, limit int32) ([]db.Loan, error) {
+			return server.store.ListLoansAfter(ctx, db.ListLoansAfterParams{CreatedAt: cursor.CreatedAt, ID: cursor.ID, Limit: limit})
+		},
+		before: func(cursor pageCursor, limit int32) ([]db.Loan, error) {
+			return server.store.ListLoansBefore(ctx, db.ListLoansBeforeParams{CreatedAt: cursor.CreatedAt, ID: cursor.ID, Limit: limit})
+		},
+		count: func() (int64, error) {
+			return server.store.CountLoans(ctx)
+		},
+		key: loanCursor,
+	}
+}
+
+// loanCursor returns the position of loan in list and search results
+func loanCursor(loan db.Loan) pageCursor {
+	return pageCursor{CreatedAt: loan.CreatedAt, ID: loan.ID}
 }
 
 // getLoan gets a loan by ID.
@@ -127,58 +253,44 @@ func (server *Server) listLoans(ctx *gin.Context) {
 func (server *Server) getLoan(ctx *gin.Context) {
 	var req getRequest
 	if err := ctx.ShouldBindUri(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errResponse(err))
+		writeProblem(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	loan, err := server.store.GetLoan(ctx, uuid.MustParse(req.ID))
 	if err != nil {
-		if errors.Is(err, db.ErrRecordNotFound) {
-			ctx.JSON(http.StatusNotFound, errResponse(err))
-			return
-		}
-		ctx.JSON(http.StatusInternalServerError, errResponse(err))
+		writeError(ctx, err)
 		return
 	}
 
 	ctx.JSON(http.StatusOK, loan)
 }
 
-// searchLoans searches loans by borrower.
+// searchLoans searches loans like listLoans with q set.
 //
 //	@Summary		Search loans
-//	@Description	Search loans by borrower.
+//	@Description	Search loans like GET /loans?q=. Deprecated, use GET /loans?q=.
 //	@Tags			loans
 //	@Accept			json
 //	@Produce		json
-//	@Param			request	body		searchRequest	true	"Search Request"
-//	@Success		200		{object}	[]db.Loan		"List of loans"
-//	@Failure		400		{object}	errorResponse	"Bad Request"
-//	@Failure		401		{object}	errorResponse	"Unauthorized"
-//	@Failure		403		{object}	errorResponse	"Forbidden"
-//	@Failure		500		{object}	errorResponse	"Internal Server Error"
+//	@Param			count	query		bool					false	"Set to false to omit the total count"
+//	@Param			request	body		searchRequest			true	"Search Request"
+//	@Success		200		{object}	listResponse[db.Loan]	"List of loans"
+//	@Failure		400		{object}	errorResponse			"Bad Request"
+//	@Failure		401		{object}	errorResponse			"Unauthorized"
+//	@Failure		403		{object}	errorResponse			"Forbidden"
+//	@Failure		500		{object}	errorResponse			"Internal Server Error"
 //	@Router			/loans/search [post]
-//	@security		ApiKeyAuth
+//	@Deprecated
+//	@security	ApiKeyAuth
 func (server *Server) searchLoans(ctx *gin.Context) {
 	var req searchRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errResponse(err))
+		writeProblem(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	arg := db.SearchLoansParams{
-		Borrower: req.Query,
-		Offset:   (req.PageID - 1) * req.PageSize,
-		Limit:    req.PageSize,
-	}
-
-	loans, err := server.store.SearchLoans(ctx, arg)
-	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errResponse(err))
-		return
-	}
-
-	ctx.JSON(http.StatusOK, loans)
+	server.findLoans(ctx, listLoansRequest{listRequest: req.pagination(), Query: req.Query})
 }
 
 // deleteLoan deletes a loan by ID.
@@ -200,19 +312,16 @@ func (server *Server) searchLoans(ctx *gin.Context) {
 func (server *Server) deleteLoan(ctx *gin.Context) {
 	var req getRequest
 	if err := ctx.ShouldBindUri(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errResponse(err))
+		writeProblem(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	loan, err := server.store.DeleteLoan(ctx, uuid.MustParse(req.ID))
 	if err != nil {
-		if errors.Is(err, db.ErrRecordNotFound) {
-			ctx.JSON(http.StatusNotFound, errResponse(err))
-			return
-		}
-		ctx.JSON(http.StatusInternalServerError, errResponse(err))
+		writeError(ctx, err)
 		return
 	}
+	recordDeleted(ctx, resourceLoan)
 
 	ctx.JSON(http.StatusOK, loan)
 }
@@ -253,266 +253,15 @@ func TestGetLoanAPI(t *testing.T) {
 }
 
 func TestListLoansAPI(t *testing.T) {
-	user, _ := randomUser(t)
-
-	n := 6
-	loans := make([]db.Loan, n)
-	for i := 0; i < n; i++ {
-		loans[i] = randomLoan(t)
-	}
-
-	testCases := []struct {
-		name          string
-		body          gin.H
-		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
-		buildStubs    func(store *mockdb.MockStore)
-		checkResponse func(recorder *httptest.ResponseRecorder)
-	}{
-		{
-			name: "OK",
-			body: gin.H{
-				"page_id":   1,
-				"page_size": n,
-			},
-			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, util.RoleUser, time.Minute)
-			},
-			buildStubs: func(store *mockdb.MockStore) {
-				arg := db.ListLoansParams{
-					Offset: 0,
-					Limit:  int32(n),
-				}
-				store.EXPECT().ListLoans(gomock.Any(), gomock.Eq(arg)).Times(1).Return(loans, nil)
-			},
-			checkResponse: func(recorder *httptest.ResponseRecorder) {
-				require.Equal(t, http.StatusOK, recorder.Code)
-				requireBodyMatchLoans(t, recorder.Body, loans)
-			},
-		},
-		{
-			name: "NoAuthorization",
-			body: gin.H{
-				"page_id":   1,
-				"page_size": n,
-			},
-			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {},
-			buildStubs: func(store *mockdb.MockStore) {
-				store.EXPECT().ListLoans(gomock.Any(), gomock.Any()).Times(0)
-			},
-			checkResponse: func(recorder *httptest.ResponseRecorder) {
-				require.Equal(t, http.StatusUnauthorized, recorder.Code)
-			},
-		},
-		{
-			name: "InternalError",
-			body: gin.H{
-				"page_id":   1,
-				"page_size": n,
-			},
-			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, util.RoleUser, time.Minute)
-			},
-			buildStubs: func(store *mockdb.MockStore) {
-				store.EXPECT().ListLoans(gomock.Any(), gomock.Any()).Times(1).Return([]db.Loan{}, sql.ErrConnDone)
-			},
-			checkResponse: func(recorder *httptest.ResponseRecorder) {
-				require.Equal(t, http.StatusInternalServerError, recorder.Code)
-			},
-		},
-		{
-			name: "InvalidPageID",
-			body: gin.H{
-				"page_id":   -1,
-				"page_size": n,
-			},
-			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, util.RoleUser, time.Minute)
-			},
-			buildStubs: func(store *mockdb.MockStore) {
-				store.EXPECT().ListLoans(gomock.Any(), gomock.Any()).Times(0)
-			},
-			checkResponse: func(recorder *httptest.ResponseRecorder) {
-				require.Equal(t, http.StatusBadRequest, recorder.Code)
-			},
-		},
-		{
-			name: "InvalidPageSize",
-			body: gin.H{
-				"page_id":   1,
-				"page_size": 1000,
-			},
-			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, util.RoleUser, time.Minute)
-			},
-			buildStubs: func(store *mockdb.MockStore) {
-				store.EXPECT().ListLoans(gomock.Any(), gomock.Any()).Times(0)
-			},
-			checkResponse: func(recorder *httptest.ResponseRecorder) {
-				require.Equal(t, http.StatusBadRequest, recorder.Code)
-			},
-		},
-	}
-
-	for _, tc := range testCases {
-		t.Run(tc.name, func(t *testing.T) {
-			ctrl := gomock.NewController(t)
-			defer ctrl.Finish()
-
-			store := mockdb.NewMockStore(ctrl)
-			tc.buildStubs(store)
-
-			server := newTestServer(t, store)
-			recorder := httptest.NewRecorder()
-
-			// Marshal body data to JSON
-			data, err := json.Marshal(tc.body)
-			require.NoError(t, err)
-
-			url := "/v1/loans/all"
-			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
-			require.NoError(t, err)
-
-			request.Header.Set("Content-Type", "application/json")
-			tc.setupAuth(t, request, server.tokenMaker)
+	testListPostAPI(t, loanListResource(t))
+}
 
-			server.router.ServeHTTP(recorder, request)
-			tc.checkResponse(recorder)
-		})
-	}
+func TestListLoansGetAPI(t *testing.T) {
+	testListGetAPI(t, loanListResource(t))
 }
 
 func TestSearchLoansAPI(t *testing.T) {
-	user, _ := randomUser(t)
-
-	n := 6
-	loans := make([]db.Loan, n)
-	for i := 0; i < n; i++ {
-		loans[i] = randomLoan(t)
-	}
-	testCases := []struct {
-		name          string
-		body          gin.H
-		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
-		buildStubs    func(store *mockdb.MockStore)
-		checkResponse func(recorder *httptest.ResponseRecorder)
-	}{
-		{
-			name: "OK",
-			body: gin.H{
-				"query":     loans[0].Borrower,
-				"page_id":   1,
-				"page_size": n,
-			},
-			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, util.RoleUser, time.Minute)
-			},
-			buildStubs: func(store *mockdb.MockStore) {
-				arg := db.SearchLoansParams{
-					Borrower: loans[0].Borrower,
-					Offset:   0,
-					Limit:    int32(n),
-				}
-				store.EXPECT().SearchLoans(gomock.Any(), gomock.Eq(arg)).Times(1).Return(loans, nil)
-			},
-			checkResponse: func(recorder *httptest.ResponseRecorder) {
-				require.Equal(t, http.StatusOK, recorder.Code)
-				requireBodyMatchLoans(t, recorder.Body, loans)
-			},
-		},
-		{
-			name: "NoAuthorization",
-			body: gin.H{
-				"query":     loans[0].Borrower,
-				"page_id":   1,
-				"page_size": n,
-			},
-			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {},
-			buildStubs: func(store *mockdb.MockStore) {
-				store.EXPECT().SearchLoans(gomock.Any(), gomock.Any()).Times(0)
-			},
-			checkResponse: func(recorder *httptest.ResponseRecorder) {
-				require.Equal(t, http.StatusUnauthorized, recorder.Code)
-			},
-		},
-		{
-			name: "InternalError",
-			body: gin.H{
-				"query":     loans[0].Borrower,
-				"page_id":   1,
-				"page_size": n,
-			},
-			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, util.RoleUser, time.Minute)
-			},
-			buildStubs: func(store *mockdb.MockStore) {
-				store.EXPECT().SearchLoans(gomock.Any(), gomock.Any()).Times(1).Return([]db.Loan{}, sql.ErrConnDone)
-			},
-			checkResponse: func(recorder *httptest.ResponseRecorder) {
-				require.Equal(t, http.StatusInternalServerError, recorder.Code)
-			},
-		},
-		{
-			name: "InvalidPageID",
-			body: gin.H{
-				"query":     loans[0].Borrower,
-				"page_id":   -1,
-				"page_size": n,
-			},
-			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, util.RoleUser, time.Minute)
-			},
-			buildStubs: func(store *mockdb.MockStore) {
-				store.EXPECT().SearchLoans(gomock.Any(), gomock.Any()).Times(0)
-			},
-			checkResponse: func(recorder *httptest.ResponseRecorder) {
-				require.Equal(t, http.StatusBadRequest, recorder.Code)
-			},
-		},
-		{
-			name: "InvalidPageSize",
-			body: gin.H{
-				"query":     loans[0].Borrower,
-				"page_id":   1,
-				"page_size": 1000,
-			},
-			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, util.RoleUser, time.Minute)
-			},
-			buildStubs: func(store *mockdb.MockStore) {
-				store.EXPECT().SearchLoans(gomock.Any(), gomock.Any()).Times(0)
-			},
-			checkResponse: func(recorder *httptest.ResponseRecorder) {
-				require.Equal(t, http.StatusBadRequest, recorder.Code)
-			},
-		},
-	}
-
-	for _, tc := range testCases {
-		t.Run(tc.name, func(t *testing.T) {
-			ctrl := gomock.NewController(t)
-			defer ctrl.Finish()
-
-			store := mockdb.NewMockStore(ctrl)
-			tc.buildStubs(store)
-
-			server := newTestServer(t, store)
-			recorder := httptest.NewRecorder()
-
-			// Marshal body data to JSON
-			data, err := json.Marshal(tc.body)
-			require.NoError(t, err)
-
-			url := "/v1/loans/search"
-			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
-			require.NoError(t, err)
-
-			request.Header.Set("Content-Type", "application/json")
-			tc.setupAuth(t, request, server.tokenMaker)
-
-			server.router.ServeHTTP(recorder, request)
-			tc.checkResponse(recorder)
-		})
-	}
+	testSearchPostAPI(t, loanListResource(t))
 }
 
 func TestDeleteLoanAPI(t *testing.T) {
@@ -651,12 +400,45 @@ func requireBodyMatchLoan(t *testing.T, body *bytes.Buffer, loan db.Loan) {
 	require.Equal(t, loan, gotLoan)
 }
 
-func requireBodyMatchLoans(t *testing.T, body *bytes.Buffer, loans []db.Loan) {
-	data, err := io.ReadAll(body)
-	require.NoError(t, err)
+// loanListResource returns the loan list endpoints with random loans as results
+func loanListResource(t *testing.T) listResource[db.Loan] {
+	items := make([]db.Loan, 6)
+	for i := range items {
+		items[i] = randomLoan(t)
+	}
 
-	var gotLoans []db.Loan
-	err = json.Unmarshal(data, &gotLoans)
-	require.NoError(t, err)
-	require.Equal(t, loans, gotLoans)
+	return listResource[db.Loan]{
+		path:      "/v1/loans",
+		nameField: "borrower",
+		items:     items,
+		stubs: listStubs{
+			list: func(store *mockdb.MockStore, offset, limit int32) *gomock.Call {
+				return store.EXPECT().ListLoans(gomock.Any(), gomock.Eq(db.ListLoansParams{Offset: offset, Limit: limit}))
+			},
+			listAfter: func(store *mockdb.MockStore, cursor pageCursor, limit int32) *gomock.Call {
+				arg := db.ListLoansAfterParams{CreatedAt: cursor.CreatedAt, ID: cursor.ID, Limit: limit}
+				return store.EXPECT().ListLoansAfter(gomock.Any(), gomock.Eq(arg))
+			},
+			count: func(store *mockdb.MockStore) *gomock.Call {
+				return store.EXPECT().CountLoans(gomock.Any())
+			},
+			filter: func(store *mockdb.MockStore, arg db.FilterParams) *gomock.Call {
+				return store.EXPECT().FilterLoans(gomock.Any(), gomock.Eq(arg))
+			},
+			countFilter: func(store *mockdb.MockStore, filter db.ListFilter) *gomock.Call {
+				return store.EXPECT().CountFilterLoans(gomock.Any(), gomock.Eq(filter))
+			},
+			search: func(store *mockdb.MockStore, query string, offset, limit int32) *gomock.Call {
+				arg := db.SearchLoansParams{Query: query, Offset: offset, Limit: limit}
+				return store.EXPECT().SearchLoans(gomock.Any(), gomock.Eq(arg))
+			},
+			searchAfter: func(store *mockdb.MockStore, query string, cursor pageCursor, limit int32) *gomock.Call {
+				arg := db.SearchLoansAfterParams{Query: query, CreatedAt: cursor.CreatedAt, ID: cursor.ID, Limit: limit}
+				return store.EXPECT().SearchLoansAfter(gomock.Any(), gomock.Eq(arg))
+			},
+			countSearch: func(store *mockdb.MockStore, query string) *gomock.Call {
+				return store.EXPECT().CountSearchLoans(gomock.Any(), gomock.Eq(query))
+			},
+		},
+	}
 }
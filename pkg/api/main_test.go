@@ -15,12 +15,18 @@ import (
 )
 
 func newTestServer(t *testing.T, store db.Store) *Server {
+	return newTestServerWithConfig(t, store, func(config *util.Config) {})
+}
+
+// newTestServerWithConfig creates a test server after modify has adjusted the config
+func newTestServerWithConfig(t *testing.T, store db.Store, modify func(config *util.Config)) *Server {
 	config := util.Config{
 		Server: util.Server{
 			TokenSymmetricKey:   util.RandomString(32),
 			AccessTokenDuration: time.Minute,
 		},
 	}
+	modify(&config)
 
 	tokenMaker, err := token.NewPasetoMaker(config.Server.TokenSymmetricKey)
 	require.NoError(t, err)
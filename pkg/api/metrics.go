@@ -0,0 +1,111 @@
+package api
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lushenle/plam/pkg/token"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Resources counted by the record metrics
+const (
+	resourceIncome = "income"
+	resourceLoan   = "loan"
+	resourcePayOut = "pay_out"
+)
+
+// Login methods and results counted by the login metric
+const (
+	loginMethodPassword = "password"
+	loginMethodOIDC     = "oidc"
+	loginSuccess        = "success"
+	loginFailure        = "failure"
+)
+
+// Reasons of the token verification failures
+const (
+	tokenFailureExpired = "expired"
+	tokenFailureInvalid = "invalid"
+	// tokenFailureHeader is a missing or malformed authorization header
+	tokenFailureHeader = "header"
+)
+
+// The business metrics are served on /metrics next to the request metrics of gin-metrics,
+// both use the default Prometheus registry
+var (
+	recordsCreated = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "plam",
+		Name:      "records_created_total",
+		Help:      "Number of records created, by resource and role of the user.",
+	}, []string{"resource", "role"})
+
+	recordsDeleted = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "plam",
+		Name:      "records_deleted_total",
+		Help:      "Number of records deleted, by resource and role of the user.",
+	}, []string{"resource", "role"})
+
+	recordAmounts = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "plam",
+		Name:      "record_amount",
+		Help:      "Amounts of the records created, by resource.",
+		Buckets:   prometheus.ExponentialBuckets(100, 10, 7),
+	}, []string{"resource"})
+
+	logins = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "plam",
+		Name:      "logins_total",
+		Help:      "Number of login attempts, by method and result.",
+	}, []string{"method", "result"})
+
+	tokenFailures = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "plam",
+		Name:      "token_verification_failures_total",
+		Help:      "Number of requests rejected by the token verification, by reason.",
+	}, []string{"reason"})
+)
+
+// recordCreated counts a record created by the user of the request
+func recordCreated(ctx *gin.Context, resource string, amount float32) {
+	recordsCreated.WithLabelValues(resource, requestRole(ctx)).Inc()
+	recordAmounts.WithLabelValues(resource).Observe(float64(amount))
+}
+
+// recordDeleted counts a record deleted by the user of the request
+func recordDeleted(ctx *gin.Context, resource string) {
+	recordsDeleted.WithLabelValues(resource, requestRole(ctx)).Inc()
+}
+
+// recordLogin counts a login attempt, a failure is a login rejected because of the credentials
+// or of the identity provider
+func recordLogin(method string, success bool) {
+	result := loginFailure
+	if success {
+		result = loginSuccess
+	}
+	logins.WithLabelValues(method, result).Inc()
+}
+
+// recordTokenFailure counts a request rejected by verifyAuthorization
+func recordTokenFailure(err error) {
+	reason := tokenFailureHeader
+	switch {
+	case errors.Is(err, token.ErrExpiredToken):
+		reason = tokenFailureExpired
+	case errors.Is(err, token.ErrInvalidToken):
+		reason = tokenFailureInvalid
+	}
+	tokenFailures.WithLabelValues(reason).Inc()
+}
+
+// requestRole returns the role of the authenticated user of the request
+func requestRole(ctx *gin.Context) string {
+	if value, ok := ctx.Get(authorizationPayloadKey); ok {
+		if payload, ok := value.(*token.Payload); ok {
+			return payload.Role
+		}
+	}
+	return ""
+}
@@ -0,0 +1,157 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/lushenle/plam/pkg/db/mock"
+	"github.com/lushenle/plam/pkg/token"
+	"github.com/lushenle/plam/pkg/util"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBusinessMetrics(t *testing.T) {
+	project := randomProject(t)
+	income := randomIncome(t, project)
+	user, password := randomUser(t)
+
+	testCases := []struct {
+		name       string
+		method     string
+		url        string
+		body       gin.H
+		setupAuth  func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs func(store *mockdb.MockStore)
+		counter    prometheus.Counter
+	}{
+		{
+			name:      "LoginSuccess",
+			method:    http.MethodPost,
+			url:       "/v1/users/login",
+			body:      gin.H{"username": user.Username, "password": password},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+			},
+			counter: logins.WithLabelValues(loginMethodPassword, loginSuccess),
+		},
+		{
+			name:      "LoginFailure",
+			method:    http.MethodPost,
+			url:       "/v1/users/login",
+			body:      gin.H{"username": user.Username, "password": "incorrect"},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+			},
+			counter: logins.WithLabelValues(loginMethodPassword, loginFailure),
+		},
+		{
+			name:   "RecordCreated",
+			method: http.MethodPost,
+			url:    "/v1/incomes",
+			body:   gin.H{"payee": income.Payee, "amount": income.Amount, "project_id": income.ProjectID},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, util.RoleAdmin, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateIncome(gomock.Any(), gomock.Any()).Times(1).Return(income, nil)
+			},
+			counter: recordsCreated.WithLabelValues(resourceIncome, util.RoleAdmin),
+		},
+		{
+			name:   "RecordDeleted",
+			method: http.MethodDelete,
+			url:    fmt.Sprintf("/v1/incomes/%s", income.ID),
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, util.RoleAdmin, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().DeleteIncome(gomock.Any(), gomock.Eq(income.ID)).Times(1).Return(income, nil)
+			},
+			counter: recordsDeleted.WithLabelValues(resourceIncome, util.RoleAdmin),
+		},
+		{
+			name:   "ExpiredToken",
+			method: http.MethodDelete,
+			url:    fmt.Sprintf("/v1/incomes/%s", income.ID),
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, util.RoleAdmin, -time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().DeleteIncome(gomock.Any(), gomock.Any()).Times(0)
+			},
+			counter: tokenFailures.WithLabelValues(tokenFailureExpired),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			var body bytes.Buffer
+			if tc.body != nil {
+				require.NoError(t, json.NewEncoder(&body).Encode(tc.body))
+			}
+			request, err := http.NewRequest(tc.method, tc.url, &body)
+			require.NoError(t, err)
+			tc.setupAuth(t, request, server.tokenMaker)
+
+			before := testutil.ToFloat64(tc.counter)
+			server.router.ServeHTTP(recorder, request)
+			require.Equal(t, before+1, testutil.ToFloat64(tc.counter))
+		})
+	}
+}
+
+func TestRecordTokenFailure(t *testing.T) {
+	testCases := []struct {
+		err    error
+		reason string
+	}{
+		{err: token.ErrExpiredToken, reason: tokenFailureExpired},
+		{err: token.ErrInvalidToken, reason: tokenFailureInvalid},
+		{err: fmt.Errorf("verify: %w", token.ErrInvalidToken), reason: tokenFailureInvalid},
+		{err: errors.New("authorization header is not provided"), reason: tokenFailureHeader},
+	}
+
+	for _, tc := range testCases {
+		counter := tokenFailures.WithLabelValues(tc.reason)
+		before := testutil.ToFloat64(counter)
+		recordTokenFailure(tc.err)
+		require.Equal(t, before+1, testutil.ToFloat64(counter), tc.err.Error())
+	}
+}
+
+func TestMetricsEndpoint(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	server := newTestServer(t, mockdb.NewMockStore(ctrl))
+	recordTokenFailure(token.ErrInvalidToken)
+
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodGet, metricsPath, nil)
+	require.NoError(t, err)
+
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Contains(t, recorder.Body.String(), `plam_token_verification_failures_total{reason="invalid"}`)
+}
@@ -1,14 +1,25 @@
 package api
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
+	"runtime/debug"
+	"strconv"
 	"strings"
+	"syscall"
+	"time"
+	"unicode"
 
+	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/lushenle/plam/pkg/db"
+	"github.com/lushenle/plam/pkg/log"
 	"github.com/lushenle/plam/pkg/token"
 	"github.com/lushenle/plam/pkg/util"
+	"go.uber.org/zap"
 )
 
 const (
@@ -17,33 +28,31 @@ const (
 	authorizationPayloadKey = "authorization_payload"
 )
 
-func authMiddleware(tokenMaker token.Maker) gin.HandlerFunc {
-	return func(ctx *gin.Context) {
-		authorizationHeader := ctx.GetHeader(authorizationHeaderKey)
-		if len(authorizationHeader) == 0 {
-			err := errors.New("authorization header is not provided")
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errResponse(err))
-			return
-		}
+const (
+	// readConsistencyHeader set to readConsistencyStrong sends the reads of a request to the
+	// primary database, so that they observe the writes made just before
+	readConsistencyHeader = "X-Read-Consistency"
+	readConsistencyStrong = "strong"
+)
 
-		fields := strings.Fields(authorizationHeader)
-		if len(fields) < 2 {
-			err := errors.New("invalid authorization header format")
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errResponse(err))
-			return
-		}
+const (
+	deprecationHeader = "Deprecation"
+	linkHeader        = "Link"
+	// requestIDHeader carries the ID of a request, which responses and error bodies repeat
+	requestIDHeader = "X-Request-ID"
+	// maxRequestIDLength bounds the request IDs accepted from clients
+	maxRequestIDLength = 128
+)
 
-		authorizationType := strings.ToLower(fields[0])
-		if authorizationType != authorizationTypeBearer {
-			err := fmt.Errorf("unspported authorization type %s ", authorizationType)
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errResponse(err))
-			return
-		}
+// listRoutesDeprecatedAt is when the POST list and search routes were deprecated in favor of the GET list routes
+var listRoutesDeprecatedAt = time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC)
 
-		accessToken := fields[1]
-		payload, err := tokenMaker.VerifyToken(accessToken)
+func authMiddleware(tokenMaker token.Maker) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		payload, err := verifyAuthorization(tokenMaker, ctx.GetHeader(authorizationHeaderKey))
 		if err != nil {
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errResponse(err))
+			recordTokenFailure(err)
+			abortWithProblem(ctx, http.StatusUnauthorized, err)
 			return
 		}
 
@@ -52,20 +61,193 @@ func authMiddleware(tokenMaker token.Maker) gin.HandlerFunc {
 	}
 }
 
+// verifyAuthorization verifies the bearer token of an authorization header
+func verifyAuthorization(tokenMaker token.Maker, authorizationHeader string) (*token.Payload, error) {
+	if len(authorizationHeader) == 0 {
+		return nil, errors.New("authorization header is not provided")
+	}
+
+	fields := strings.Fields(authorizationHeader)
+	if len(fields) < 2 {
+		return nil, errors.New("invalid authorization header format")
+	}
+
+	authorizationType := strings.ToLower(fields[0])
+	if authorizationType != authorizationTypeBearer {
+		return nil, errors.New("unsupported authorization type")
+	}
+
+	accessToken := fields[1]
+	return tokenMaker.VerifyToken(accessToken)
+}
+
 func rbacMiddleware() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
 		if payload == nil {
 			err := errors.New("authorization payload is not found")
-			ctx.AbortWithStatusJSON(http.StatusForbidden, errResponse(err))
+			abortWithProblem(ctx, http.StatusForbidden, err)
 			return
 		}
 
 		if payload.Role != util.RoleAdmin {
-			ctx.AbortWithStatusJSON(http.StatusForbidden, errResponse(errors.New("permission denied")))
+			abortWithProblem(ctx, http.StatusForbidden, errors.New("permission denied"))
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
+// corsMiddleware handles cross-origin requests for the origins currently allowed by the server,
+// it does nothing while no origin is configured
+func (server *Server) corsMiddleware() gin.HandlerFunc {
+	handler := cors.New(cors.Config{
+		AllowOriginFunc: server.corsOriginAllowed,
+		AllowMethods:    []string{http.MethodGet, http.MethodPost, http.MethodDelete, http.MethodOptions},
+		AllowHeaders:    []string{"Origin", "Content-Type", "Authorization", readConsistencyHeader, requestIDHeader},
+		ExposeHeaders:   []string{deprecationHeader, linkHeader, requestIDHeader},
+		MaxAge:          12 * time.Hour,
+	})
+
+	return func(ctx *gin.Context) {
+		if len(*server.corsOrigins.Load()) == 0 {
+			ctx.Next()
+			return
+		}
+		handler(ctx)
+	}
+}
+
+func (server *Server) corsOriginAllowed(origin string) bool {
+	for _, allowed := range *server.corsOrigins.Load() {
+		if allowed == "*" || strings.EqualFold(allowed, origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// bodyLimitMiddleware rejects request bodies larger than maxBytes, a limit of 0 disables it
+func bodyLimitMiddleware(maxBytes int64) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if maxBytes <= 0 {
+			ctx.Next()
+			return
+		}
+
+		if ctx.Request.ContentLength > maxBytes {
+			abortWithProblem(ctx, http.StatusRequestEntityTooLarge, errors.New("request body is too large"))
 			return
 		}
 
+		// Bodies without a Content-Length fail to bind once the limit is reached
+		ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxBytes)
+		ctx.Next()
+	}
+}
+
+// readConsistencyMiddleware sends the reads of requests asking for strong consistency to the primary
+func readConsistencyMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if strings.EqualFold(ctx.GetHeader(readConsistencyHeader), readConsistencyStrong) {
+			ctx.Request = ctx.Request.WithContext(db.WithPrimary(ctx.Request.Context()))
+		}
+		ctx.Next()
+	}
+}
+
+// requestIDMiddleware accepts the X-Request-ID header of the request or generates one,
+// echoes it in the response and stores it in the request context along with logger,
+// so that log.FromContext returns a logger whose lines carry the request ID
+func requestIDMiddleware(logger *zap.Logger) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id := ctx.GetHeader(requestIDHeader)
+		if !validRequestID(id) {
+			id = uuid.NewString()
+		}
+		ctx.Header(requestIDHeader, id)
+
+		reqCtx := log.WithLogger(ctx.Request.Context(), logger)
+		ctx.Request = ctx.Request.WithContext(log.WithRequestID(reqCtx, id))
+		ctx.Next()
+	}
+}
+
+// recoveryMiddleware responds with an internal server error problem when a handler panics.
+// The panic is logged through log.FromContext, so that the line carries the request ID and
+// the trace; the request headers, which hold credentials, are not logged.
+func recoveryMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+
+			logger := log.FromContext(ctx.Request.Context()).With(
+				zap.Any("error", recovered),
+				zap.String("method", ctx.Request.Method),
+				zap.String("path", ctx.Request.URL.Path),
+			)
+			// Nothing can be written to a connection closed by the client
+			if err, ok := recovered.(error); ok && (errors.Is(err, syscall.EPIPE) || errors.Is(err, syscall.ECONNRESET)) {
+				logger.Warn("connection closed by the client")
+				_ = ctx.Error(err)
+				ctx.Abort()
+				return
+			}
+
+			logger.Error("recovered from panic", zap.ByteString("stack", debug.Stack()))
+			abortWithProblem(ctx, http.StatusInternalServerError, fmt.Errorf("panic: %v", recovered))
+		}()
+		ctx.Next()
+	}
+}
+
+// validRequestID checks that a request ID from a client is safe to log and repeat
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, c := range id {
+		if c > unicode.MaxASCII || !(unicode.IsLetter(c) || unicode.IsDigit(c) || strings.ContainsRune("-_.:", c)) {
+			return false
+		}
+	}
+	return true
+}
+
+// requestID returns the ID of the request, empty outside requestIDMiddleware
+func requestID(ctx *gin.Context) string {
+	return log.RequestID(ctx.Request.Context())
+}
+
+// deprecationMiddleware marks the responses of a deprecated route with the date it was deprecated,
+// as defined by RFC 9745, and links to the route replacing it
+func deprecationMiddleware(deprecatedAt time.Time, successor string) gin.HandlerFunc {
+	deprecation := "@" + strconv.FormatInt(deprecatedAt.Unix(), 10)
+	link := fmt.Sprintf(`<%s>; rel="successor-version"`, successor)
+
+	return func(ctx *gin.Context) {
+		ctx.Header(deprecationHeader, deprecation)
+		ctx.Header(linkHeader, link)
+		ctx.Next()
+	}
+}
+
+// timeoutMiddleware cancels the request context after timeout, a timeout of 0 disables it
+func timeoutMiddleware(timeout time.Duration) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if timeout <= 0 {
+			ctx.Next()
+			return
+		}
+
+		timeoutCtx, cancel := context.WithTimeout(ctx.Request.Context(), timeout)
+		defer cancel()
+
+		ctx.Request = ctx.Request.WithContext(timeoutCtx)
 		ctx.Next()
 	}
 }
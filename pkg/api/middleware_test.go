@@ -1,16 +1,24 @@
 package api
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/lushenle/plam/pkg/db"
+	"github.com/lushenle/plam/pkg/log"
 	"github.com/lushenle/plam/pkg/token"
 	"github.com/lushenle/plam/pkg/util"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
 )
 
 func addAuthorization(
@@ -151,3 +159,274 @@ func TestRBACMiddware(t *testing.T) {
 		})
 	}
 }
+
+func TestCORSMiddleware(t *testing.T) {
+	server := newTestServer(t, nil)
+	const origin = "https://plam.example.com"
+
+	sendPreflight := func() *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodOptions, "/v1/users/login", nil)
+		require.NoError(t, err)
+		request.Header.Set("Origin", origin)
+		request.Header.Set("Access-Control-Request-Method", http.MethodPost)
+
+		server.router.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	// CORS is disabled until an origin is configured
+	require.Empty(t, sendPreflight().Header().Get("Access-Control-Allow-Origin"))
+
+	config := server.config
+	config.Server.CORS.AllowedOrigins = []string{origin}
+	server.Reload(config)
+
+	recorder := sendPreflight()
+	require.Equal(t, http.StatusNoContent, recorder.Code)
+	require.Equal(t, origin, recorder.Header().Get("Access-Control-Allow-Origin"))
+
+	config.Server.CORS.AllowedOrigins = []string{"https://other.example.com"}
+	server.Reload(config)
+	require.Equal(t, http.StatusForbidden, sendPreflight().Code)
+}
+
+func TestBodyLimitMiddleware(t *testing.T) {
+	testCases := []struct {
+		name          string
+		body          string
+		contentLength int64
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:          "OK",
+			body:          `{"a":1}`,
+			contentLength: 7,
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:          "ContentLengthTooLarge",
+			body:          `{"a":"0123456789"}`,
+			contentLength: 18,
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusRequestEntityTooLarge, recorder.Code)
+			},
+		},
+		{
+			name:          "UnknownLengthTooLarge",
+			body:          `{"a":"0123456789"}`,
+			contentLength: -1,
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			router := gin.New()
+			router.POST("/body", bodyLimitMiddleware(16), func(ctx *gin.Context) {
+				var req map[string]any
+				if err := ctx.ShouldBindJSON(&req); err != nil {
+					writeProblem(ctx, http.StatusBadRequest, err)
+					return
+				}
+				ctx.JSON(http.StatusOK, req)
+			})
+
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodPost, "/body", strings.NewReader(tc.body))
+			require.NoError(t, err)
+			request.ContentLength = tc.contentLength
+
+			router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestTimeoutMiddleware(t *testing.T) {
+	router := gin.New()
+	router.ContextWithFallback = true
+	router.GET("/slow", timeoutMiddleware(20*time.Millisecond), func(ctx *gin.Context) {
+		_, ok := ctx.Deadline()
+		require.True(t, ok)
+
+		select {
+		case <-ctx.Done():
+			writeProblem(ctx, http.StatusServiceUnavailable, ctx.Err())
+		case <-time.After(time.Second):
+			ctx.JSON(http.StatusOK, gin.H{})
+		}
+	})
+
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodGet, "/slow", nil)
+	require.NoError(t, err)
+
+	router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+}
+
+func TestReadConsistencyMiddleware(t *testing.T) {
+	testCases := []struct {
+		name        string
+		header      string
+		wantPrimary bool
+	}{
+		{name: "Default"},
+		{name: "Strong", header: "strong", wantPrimary: true},
+		{name: "StrongUpperCase", header: "STRONG", wantPrimary: true},
+		{name: "Eventual", header: "eventual"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			router := gin.New()
+			router.ContextWithFallback = true
+			router.GET("/read", readConsistencyMiddleware(), func(ctx *gin.Context) {
+				// The gin context is what handlers pass to the store
+				require.Equal(t, tc.wantPrimary, db.ReadsFromPrimary(ctx))
+				ctx.JSON(http.StatusOK, gin.H{})
+			})
+
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodGet, "/read", nil)
+			require.NoError(t, err)
+			if tc.header != "" {
+				request.Header.Set(readConsistencyHeader, tc.header)
+			}
+
+			router.ServeHTTP(recorder, request)
+			require.Equal(t, http.StatusOK, recorder.Code)
+		})
+	}
+}
+
+func TestDeprecationMiddleware(t *testing.T) {
+	deprecatedAt := time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC)
+
+	router := gin.New()
+	router.POST("/old", deprecationMiddleware(deprecatedAt, "/new"), func(ctx *gin.Context) {
+		ctx.JSON(http.StatusOK, gin.H{})
+	})
+
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodPost, "/old", nil)
+	require.NoError(t, err)
+
+	router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, "@1792368000", recorder.Header().Get(deprecationHeader))
+	require.Equal(t, `</new>; rel="successor-version"`, recorder.Header().Get(linkHeader))
+}
+
+func TestRequestIDMiddleware(t *testing.T) {
+	testCases := []struct {
+		name      string
+		requestID string
+		checkID   func(t *testing.T, id string)
+	}{
+		{
+			name:      "Accepted",
+			requestID: "client-id_1.2:3",
+			checkID: func(t *testing.T, id string) {
+				require.Equal(t, "client-id_1.2:3", id)
+			},
+		},
+		{
+			name: "Generated",
+			checkID: func(t *testing.T, id string) {
+				_, err := uuid.Parse(id)
+				require.NoError(t, err)
+			},
+		},
+		{
+			name:      "Unsafe",
+			requestID: "id\nlevel=error",
+			checkID: func(t *testing.T, id string) {
+				_, err := uuid.Parse(id)
+				require.NoError(t, err)
+			},
+		},
+		{
+			name:      "TooLong",
+			requestID: strings.Repeat("a", maxRequestIDLength+1),
+			checkID: func(t *testing.T, id string) {
+				_, err := uuid.Parse(id)
+				require.NoError(t, err)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			core, logs := observer.New(zapcore.InfoLevel)
+
+			router := gin.New()
+			router.ContextWithFallback = true
+			router.Use(requestIDMiddleware(zap.New(core)))
+			router.GET("/ok", func(ctx *gin.Context) {
+				log.FromContext(ctx).Info("handled")
+				ctx.JSON(http.StatusOK, gin.H{})
+			})
+			router.GET("/error", func(ctx *gin.Context) {
+				writeProblem(ctx, http.StatusBadRequest, errors.New("bad request"))
+			})
+
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodGet, "/ok", nil)
+			request.Header.Set(requestIDHeader, tc.requestID)
+			router.ServeHTTP(recorder, request)
+
+			require.Equal(t, http.StatusOK, recorder.Code)
+			id := recorder.Header().Get(requestIDHeader)
+			tc.checkID(t, id)
+
+			entries := logs.AllUntimed()
+			require.Len(t, entries, 1)
+			require.Equal(t, []zapcore.Field{zap.String(log.RequestIDField, id)}, entries[0].Context)
+
+			recorder = httptest.NewRecorder()
+			request = httptest.NewRequest(http.MethodGet, "/error", nil)
+			request.Header.Set(requestIDHeader, tc.requestID)
+			router.ServeHTTP(recorder, request)
+
+			problem := requireProblem(t, recorder, http.StatusBadRequest, codeBadRequest)
+			require.Equal(t, recorder.Header().Get(requestIDHeader), problem.RequestID)
+			tc.checkID(t, problem.RequestID)
+		})
+	}
+}
+
+func TestRecoveryMiddleware(t *testing.T) {
+	core, logs := observer.New(zapcore.InfoLevel)
+
+	router := gin.New()
+	router.Use(requestIDMiddleware(zap.New(core)))
+	router.Use(recoveryMiddleware())
+	router.GET("/panic", func(ctx *gin.Context) {
+		panic("boom")
+	})
+
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodGet, "/panic", nil)
+	request.Header.Set(requestIDHeader, "panic-1")
+	request.Header.Set(authorizationHeaderKey, "Bearer secret")
+	router.ServeHTTP(recorder, request)
+
+	problem := requireProblem(t, recorder, http.StatusInternalServerError, codeInternal)
+	require.Equal(t, "panic-1", problem.RequestID)
+	require.NotContains(t, recorder.Body.String(), "boom")
+
+	entries := logs.FilterMessage("recovered from panic").AllUntimed()
+	require.Len(t, entries, 1)
+	fields := entries[0].ContextMap()
+	require.Equal(t, "panic-1", fields[log.RequestIDField])
+	require.Equal(t, "boom", fields["error"])
+	require.Equal(t, "/panic", fields["path"])
+	require.NotEmpty(t, fields["stack"])
+	require.NotContains(t, fmt.Sprint(fields), "secret")
+}
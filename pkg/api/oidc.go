@@ -0,0 +1,194 @@
+package api
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lushenle/plam/pkg/db"
+	"github.com/lushenle/plam/pkg/token"
+	"github.com/lushenle/plam/pkg/util"
+)
+
+const (
+	oidcStateCookie    = "plam_oidc_state"
+	oidcNonceCookie    = "plam_oidc_nonce"
+	oidcVerifierCookie = "plam_oidc_verifier"
+	oidcCookiePath     = "/v1/auth/oidc"
+	oidcCookieMaxAge   = 600
+)
+
+// errSingleSignOnFailed is the detail of the failed logins at the identity provider
+var errSingleSignOnFailed = errors.New("single sign-on failed")
+
+// oidcLogin starts the OpenID Connect login.
+//
+//	@Summary		Starts the OpenID Connect login.
+//	@Description	Redirects to the identity provider using the authorization code flow with PKCE.
+//	@Tags			auth
+//	@Produce		json
+//	@Success		302
+//	@Failure		500	{object}	errorResponse	"Internal server error"
+//	@Router			/auth/oidc/login [get]
+func (server *Server) oidcLogin(ctx *gin.Context) {
+	authRequest, err := server.oidcProvider.NewAuthRequest()
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	server.setOIDCCookie(ctx, oidcStateCookie, authRequest.State, oidcCookieMaxAge)
+	server.setOIDCCookie(ctx, oidcNonceCookie, authRequest.Nonce, oidcCookieMaxAge)
+	server.setOIDCCookie(ctx, oidcVerifierCookie, authRequest.CodeVerifier, oidcCookieMaxAge)
+
+	ctx.Redirect(http.StatusFound, authRequest.URL)
+}
+
+// oidcCallbackRequest represents the redirect from the identity provider.
+//
+//	@swagger:model
+type oidcCallbackRequest struct {
+	// Code is the authorization code.
+	// in: query
+	Code string `form:"code"`
+
+	// State is the opaque value sent in the authorization request.
+	// in: query
+	State string `form:"state" binding:"required"`
+
+	// Error is set when the identity provider rejected the authorization request.
+	// in: query
+	Error string `form:"error"`
+
+	// ErrorDescription is the human-readable error from the identity provider.
+	// in: query
+	ErrorDescription string `form:"error_description"`
+}
+
+// oidcCallback completes the OpenID Connect login.
+//
+//	@Summary		Completes the OpenID Connect login.
+//	@Description	Exchanges the authorization code, provisions or links the user and returns an access token.
+//	@Tags			auth
+//	@Produce		json
+//	@Param			code	query		string				false	"Authorization code"
+//	@Param			state	query		string				true	"State"
+//	@Success		200		{object}	loginUserResponse	"User login response"
+//	@Failure		400		{object}	errorResponse		"Bad request"
+//	@Failure		401		{object}	errorResponse		"Unauthorized"
+//	@Failure		403		{object}	errorResponse		"Forbidden"
+//	@Failure		500		{object}	errorResponse		"Internal server error"
+//	@Router			/auth/oidc/callback [get]
+func (server *Server) oidcCallback(ctx *gin.Context) {
+	var req oidcCallbackRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		writeProblem(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	state, _ := ctx.Cookie(oidcStateCookie)
+	nonce, _ := ctx.Cookie(oidcNonceCookie)
+	codeVerifier, _ := ctx.Cookie(oidcVerifierCookie)
+
+	// The login attempt can only be completed once
+	server.setOIDCCookie(ctx, oidcStateCookie, "", -1)
+	server.setOIDCCookie(ctx, oidcNonceCookie, "", -1)
+	server.setOIDCCookie(ctx, oidcVerifierCookie, "", -1)
+
+	if state == "" || subtle.ConstantTimeCompare([]byte(state), []byte(req.State)) != 1 {
+		writeProblem(ctx, http.StatusBadRequest, errors.New("invalid oidc state"))
+		return
+	}
+
+	// The errors of the identity provider are logged, not disclosed
+	if req.Error != "" {
+		_ = ctx.Error(fmt.Errorf("identity provider returned %s: %s", req.Error, req.ErrorDescription))
+		recordLogin(loginMethodOIDC, false)
+		writeProblem(ctx, http.StatusUnauthorized, errSingleSignOnFailed)
+		return
+	}
+	if req.Code == "" {
+		writeProblem(ctx, http.StatusBadRequest, errors.New("authorization code is not provided"))
+		return
+	}
+
+	identity, err := server.oidcProvider.Exchange(ctx, req.Code, codeVerifier, nonce)
+	if err != nil {
+		_ = ctx.Error(err)
+		recordLogin(loginMethodOIDC, false)
+		writeProblem(ctx, http.StatusUnauthorized, errSingleSignOnFailed)
+		return
+	}
+
+	role, err := server.oidcProvider.Role(identity.Groups)
+	if err != nil {
+		recordLogin(loginMethodOIDC, false)
+		writeProblem(ctx, http.StatusForbidden, err)
+		return
+	}
+
+	// Provisioned users can only log in through the identity provider. Users are only
+	// provisioned when signup is open, an identity provider login carries no invitation code.
+	password, err := util.RandomToken(32)
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+	hashedPassword, err := util.HashPassword(password)
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	result, err := server.store.LoginOIDCUserTx(ctx, db.LoginOIDCUserTxParams{
+		Issuer:         identity.Issuer,
+		Subject:        identity.Subject,
+		Username:       identity.Username(),
+		FullName:       identity.Name,
+		Email:          identity.Email,
+		Role:           role,
+		LinkByEmail:    identity.EmailVerified && identity.Email != "",
+		Provision:      server.config.Signup.Mode == "" || server.config.Signup.Mode == util.SignupModeOpen,
+		HashedPassword: hashedPassword,
+	})
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, db.ErrProvisioningDisabled), errors.Is(err, db.ErrEmailRequired):
+			recordLogin(loginMethodOIDC, false)
+			status = http.StatusForbidden
+		case db.ErrorCode(err) == db.UniqueViolation:
+			status = http.StatusConflict
+		}
+		writeProblem(ctx, status, err)
+		return
+	}
+
+	user := result.User
+	accessToken, accessPayload, err := server.tokenMaker.CreateToken(user.Username, user.Role, server.tokenDuration(),
+		token.WithLocale(user.Locale))
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	rsp := loginUserResponse{
+		AccessTokenID:        accessPayload.ID,
+		AccessToken:          accessToken,
+		AccessTokenExpiresAt: accessPayload.ExpiredAt,
+		User:                 newUserResponse(user),
+	}
+
+	recordLogin(loginMethodOIDC, true)
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+func (server *Server) setOIDCCookie(ctx *gin.Context, name, value string, maxAge int) {
+	secure := strings.HasPrefix(server.config.OIDC.RedirectURL, "https://")
+
+	ctx.SetSameSite(http.SameSiteLaxMode)
+	ctx.SetCookie(name, value, maxAge, oidcCookiePath, "", secure, true)
+}
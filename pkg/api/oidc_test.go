@@ -0,0 +1,325 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/lushenle/plam/pkg/db"
+	mockdb "github.com/lushenle/plam/pkg/db/mock"
+	"github.com/lushenle/plam/pkg/sso"
+	"github.com/lushenle/plam/pkg/sso/ssotest"
+	"github.com/lushenle/plam/pkg/util"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestOIDCServer(t *testing.T, store db.Store) (*Server, *ssotest.IdP) {
+	idp, err := ssotest.NewIdP("plam")
+	require.NoError(t, err)
+	t.Cleanup(idp.Close)
+
+	server := newTestServer(t, store)
+	server.config.OIDC = util.OIDC{
+		Enabled:     true,
+		IssuerURL:   idp.URL,
+		ClientID:    idp.ClientID,
+		RedirectURL: "http://localhost:8080/v1/auth/oidc/callback",
+		AdminGroups: []string{"plam-admins"},
+		UserGroups:  []string{"plam-users"},
+	}
+
+	provider, err := sso.NewProvider(context.Background(), server.config.OIDC)
+	require.NoError(t, err)
+
+	server.oidcProvider = provider
+	server.setupRouter()
+
+	return server, idp
+}
+
+// oidcLogin starts the login on the server, lets the stand-in IdP authorize it and
+// returns the callback request carrying the login cookies
+func oidcLogin(t *testing.T, server *Server) *http.Request {
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodGet, "/v1/auth/oidc/login", nil)
+	require.NoError(t, err)
+
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusFound, recorder.Code)
+
+	client := &http.Client{
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	rsp, err := client.Get(recorder.Header().Get("Location"))
+	require.NoError(t, err)
+	defer rsp.Body.Close()
+	require.Equal(t, http.StatusFound, rsp.StatusCode)
+
+	callbackURL, err := url.Parse(rsp.Header.Get("Location"))
+	require.NoError(t, err)
+
+	callback, err := http.NewRequest(http.MethodGet, callbackURL.RequestURI(), nil)
+	require.NoError(t, err)
+	for _, cookie := range recorder.Result().Cookies() {
+		callback.AddCookie(cookie)
+	}
+
+	return callback
+}
+
+func TestOIDCLoginAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	subject := util.RandomString(10)
+
+	testCases := []struct {
+		name          string
+		signupMode    string
+		claims        ssotest.Claims
+		tamper        func(request *http.Request)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			claims: ssotest.Claims{
+				Subject:           subject,
+				Email:             user.Email,
+				EmailVerified:     true,
+				Name:              user.FullName,
+				PreferredUsername: user.Username,
+				Groups:            []string{"plam-admins"},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().LoginOIDCUserTx(gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(_ context.Context, arg db.LoginOIDCUserTxParams) (db.LoginOIDCUserTxResult, error) {
+						require.Equal(t, subject, arg.Subject)
+						require.Equal(t, user.Username, arg.Username)
+						require.Equal(t, user.Email, arg.Email)
+						require.Equal(t, util.RoleAdmin, arg.Role)
+						require.True(t, arg.LinkByEmail)
+						require.True(t, arg.Provision)
+						require.NotEmpty(t, arg.HashedPassword)
+
+						adminUser := user
+						adminUser.Role = arg.Role
+						return db.LoginOIDCUserTxResult{User: adminUser, Provisioned: true}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				data, err := io.ReadAll(recorder.Body)
+				require.NoError(t, err)
+
+				var rsp loginUserResponse
+				require.NoError(t, json.Unmarshal(data, &rsp))
+				require.Equal(t, user.Username, rsp.User.Username)
+
+				payload, err := server.tokenMaker.VerifyToken(rsp.AccessToken)
+				require.NoError(t, err)
+				require.Equal(t, user.Username, payload.Username)
+				require.Equal(t, util.RoleAdmin, payload.Role)
+			},
+		},
+		{
+			name: "UnverifiedEmail",
+			claims: ssotest.Claims{
+				Subject:           subject,
+				Email:             user.Email,
+				PreferredUsername: user.Username,
+				Groups:            []string{"plam-users"},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().LoginOIDCUserTx(gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(_ context.Context, arg db.LoginOIDCUserTxParams) (db.LoginOIDCUserTxResult, error) {
+						require.False(t, arg.LinkByEmail)
+						require.Equal(t, util.RoleUser, arg.Role)
+						return db.LoginOIDCUserTxResult{User: user}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidState",
+			claims: ssotest.Claims{
+				Subject: subject,
+				Groups:  []string{"plam-users"},
+			},
+			tamper: func(request *http.Request) {
+				query := request.URL.Query()
+				query.Set("state", "invalid-state")
+				request.URL.RawQuery = query.Encode()
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().LoginOIDCUserTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "MissingCookies",
+			claims: ssotest.Claims{
+				Subject: subject,
+				Groups:  []string{"plam-users"},
+			},
+			tamper: func(request *http.Request) {
+				request.Header.Del("Cookie")
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().LoginOIDCUserTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidCode",
+			claims: ssotest.Claims{
+				Subject: subject,
+				Groups:  []string{"plam-users"},
+			},
+			tamper: func(request *http.Request) {
+				query := request.URL.Query()
+				query.Set("code", "invalid-code")
+				request.URL.RawQuery = query.Encode()
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().LoginOIDCUserTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				// The token endpoint response is not disclosed
+				problem := requireProblem(t, recorder, http.StatusUnauthorized, codeUnauthorized)
+				require.Equal(t, errSingleSignOnFailed.Error(), problem.Detail)
+			},
+		},
+		{
+			name: "ProviderError",
+			claims: ssotest.Claims{
+				Subject: subject,
+				Groups:  []string{"plam-users"},
+			},
+			tamper: func(request *http.Request) {
+				query := request.URL.Query()
+				query.Set("error", "access_denied")
+				query.Set("error_description", "internal realm details")
+				request.URL.RawQuery = query.Encode()
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().LoginOIDCUserTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				problem := requireProblem(t, recorder, http.StatusUnauthorized, codeUnauthorized)
+				require.Equal(t, errSingleSignOnFailed.Error(), problem.Detail)
+			},
+		},
+		{
+			name: "NotAuthorizedGroup",
+			claims: ssotest.Claims{
+				Subject: subject,
+				Groups:  []string{"others"},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().LoginOIDCUserTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:       "SignupInviteOnly",
+			signupMode: util.SignupModeInvite,
+			claims: ssotest.Claims{
+				Subject:           subject,
+				Email:             user.Email,
+				EmailVerified:     true,
+				PreferredUsername: user.Username,
+				Groups:            []string{"plam-users"},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().LoginOIDCUserTx(gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(_ context.Context, arg db.LoginOIDCUserTxParams) (db.LoginOIDCUserTxResult, error) {
+						require.False(t, arg.Provision)
+						require.True(t, arg.LinkByEmail)
+						return db.LoginOIDCUserTxResult{}, db.ErrProvisioningDisabled
+					})
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				problem := requireProblem(t, recorder, http.StatusForbidden, codeForbidden)
+				require.Equal(t, db.ErrProvisioningDisabled.Error(), problem.Detail)
+			},
+		},
+		{
+			name: "MissingEmail",
+			claims: ssotest.Claims{
+				Subject: subject,
+				Groups:  []string{"plam-users"},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().LoginOIDCUserTx(gomock.Any(), gomock.Any()).Times(1).
+					Return(db.LoginOIDCUserTxResult{}, db.ErrEmailRequired)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				problem := requireProblem(t, recorder, http.StatusForbidden, codeForbidden)
+				require.Equal(t, db.ErrEmailRequired.Error(), problem.Detail)
+			},
+		},
+		{
+			name: "InternalError",
+			claims: ssotest.Claims{
+				Subject: subject,
+				Groups:  []string{"plam-users"},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().LoginOIDCUserTx(gomock.Any(), gomock.Any()).Times(1).
+					Return(db.LoginOIDCUserTxResult{}, db.ErrRecordNotFound)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server, idp := newTestOIDCServer(t, store)
+			server.config.Signup.Mode = tc.signupMode
+			idp.SetClaims(tc.claims)
+
+			request := oidcLogin(t, server)
+			if tc.tamper != nil {
+				tc.tamper(request)
+			}
+
+			recorder := httptest.NewRecorder()
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, server, recorder)
+		})
+	}
+}
+
+func TestOIDCRoutesDisabled(t *testing.T) {
+	server := newTestServer(t, nil)
+
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodGet, "/v1/auth/oidc/login", nil)
+	require.NoError(t, err)
+
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusNotFound, recorder.Code)
+}
@@ -0,0 +1,228 @@
+package api
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/lushenle/plam/pkg/db"
+)
+
+var (
+	errInvalidCursor = errors.New("invalid cursor")
+	errInvalidCount  = errors.New("invalid count parameter")
+)
+
+// countQueryKey is the query parameter which disables the total count when set to false
+const countQueryKey = "count"
+
+// pageCursor is the position of a row in the created_at, id order used by list queries, in the rank
+// order of search queries, where the rank is taken from the row, or in the order of a sorted list
+type pageCursor struct {
+	CreatedAt time.Time `json:"t"`
+	ID        uuid.UUID `json:"i"`
+	// Sort is the sort of the list the cursor belongs to, empty for the created_at, id order
+	Sort string `json:"s,omitempty"`
+	// Value is the value of the sort column of the row
+	Value string `json:"v,omitempty"`
+	// Backward selects the rows before the position instead of the rows after it
+	Backward bool `json:"b,omitempty"`
+	// Page is the number of the page the cursor leads to
+	Page int32 `json:"p,omitempty"`
+}
+
+// encode returns the opaque token sent to clients
+func (cursor pageCursor) encode() string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(token string) (pageCursor, error) {
+	var cursor pageCursor
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return cursor, errInvalidCursor
+	}
+	if err = json.Unmarshal(data, &cursor); err != nil || cursor.CreatedAt.IsZero() || cursor.ID == uuid.Nil || cursor.Page < 0 {
+		return cursor, errInvalidCursor
+	}
+	// The value is checked against the type of the sort column before it reaches a query
+	if cursor.Sort != "" {
+		if _, err = db.ParseSortValue(strings.TrimPrefix(cursor.Sort, "-"), cursor.Value); err != nil {
+			return cursor, errInvalidCursor
+		}
+	}
+	return cursor, nil
+}
+
+// page selects the rows of a list or search request, either by page number or by cursor
+type page struct {
+	number int32
+	offset int32
+	limit  int32
+	cursor *pageCursor
+	// count requests the total number of rows
+	count bool
+}
+
+// newPage validates the pagination fields of a request, a cursor takes the place of pageID
+func newPage(pageID, pageSize int32, cursor string) (page, error) {
+	p := page{limit: pageSize, count: true}
+	if cursor != "" {
+		c, err := decodeCursor(cursor)
+		if err != nil {
+			return p, err
+		}
+		p.cursor = &c
+		p.number = c.Page
+		return p, nil
+	}
+
+	if pageID < 1 {
+		pageID = 1
+	}
+	p.number = pageID
+	p.offset = (pageID - 1) * pageSize
+	return p, nil
+}
+
+// requestPage returns the page selected by the pagination fields and the count query parameter of a request
+func requestPage(ctx *gin.Context, pageID, pageSize int32, cursor string) (page, error) {
+	p, err := newPage(pageID, pageSize, cursor)
+	if err != nil {
+		return p, err
+	}
+
+	if value, ok := ctx.GetQuery(countQueryKey); ok {
+		if p.count, err = strconv.ParseBool(value); err != nil {
+			return p, errInvalidCount
+		}
+	}
+	return p, nil
+}
+
+// listResponse is a page of a list or search request.
+//
+//	@swagger:model
+type listResponse[T any] struct {
+	// Items of the page, ordered by creation time unless sorted otherwise, search results by rank.
+	Items []T `json:"items"`
+
+	// Page is the page number, 0 when the page was reached by a cursor of unknown page.
+	Page int32 `json:"page"`
+
+	// PageSize is the maximum number of items per page.
+	PageSize int32 `json:"page_size"`
+
+	// Total is the number of items of every page, it is omitted when requested with count=false.
+	Total *int64 `json:"total,omitempty"`
+
+	// HasMore tells whether there is a next page.
+	HasMore bool `json:"has_more"`
+
+	// NextCursor fetches the following page, it is omitted on the last page.
+	NextCursor string `json:"next_cursor,omitempty"`
+
+	// PrevCursor fetches the preceding page, it is omitted on the first page.
+	PrevCursor string `json:"prev_cursor,omitempty"`
+}
+
+// pageQueries runs the queries of one resource, each one returns at most limit rows
+type pageQueries[T any] struct {
+	// offset returns the rows at offset in ascending order
+	offset func(offset, limit int32) ([]T, error)
+	// after returns the rows following cursor in ascending order
+	after func(cursor pageCursor, limit int32) ([]T, error)
+	// before returns the rows preceding cursor in descending order
+	before func(cursor pageCursor, limit int32) ([]T, error)
+	// count returns the number of rows of every page
+	count func() (int64, error)
+	// key returns the position of a row
+	key func(item T) pageCursor
+	// sort is the sort of the rows, cursors of another sort are rejected
+	sort string
+}
+
+// fetchPage runs the query selected by p and sets the cursors of the neighbouring pages.
+// One more row than the page size is fetched to find out whether there is a next page.
+func fetchPage[T any](p page, queries pageQueries[T]) (listResponse[T], error) {
+	if p.cursor != nil && (p.cursor.Sort != queries.sort || (queries.sort != "" && p.cursor.Value == "")) {
+		return listResponse[T]{}, errInvalidCursor
+	}
+
+	var (
+		items            []T
+		err              error
+		hasPrev, hasNext bool
+	)
+
+	switch {
+	case p.cursor == nil:
+		items, err = queries.offset(p.offset, p.limit+1)
+		hasPrev = p.offset > 0
+		hasNext = len(items) > int(p.limit)
+	case !p.cursor.Backward:
+		items, err = queries.after(*p.cursor, p.limit+1)
+		hasPrev = true
+		hasNext = len(items) > int(p.limit)
+	default:
+		items, err = queries.before(*p.cursor, p.limit+1)
+		hasPrev = len(items) > int(p.limit)
+		hasNext = true
+	}
+	if err != nil {
+		return listResponse[T]{}, err
+	}
+
+	if len(items) > int(p.limit) {
+		items = items[:p.limit]
+	}
+	if p.cursor != nil && p.cursor.Backward {
+		for i, j := 0, len(items)-1; i < j; i, j = i+1, j-1 {
+			items[i], items[j] = items[j], items[i]
+		}
+	}
+
+	response := listResponse[T]{
+		Items:    items,
+		Page:     p.number,
+		PageSize: p.limit,
+	}
+	if p.count {
+		total, err := queries.count()
+		if err != nil {
+			return listResponse[T]{}, err
+		}
+		response.Total = &total
+	}
+	if len(items) == 0 {
+		return response, nil
+	}
+
+	response.HasMore = hasNext
+	if hasNext {
+		next := queries.key(items[len(items)-1])
+		next.Page = nextPage(p.number, 1)
+		response.NextCursor = next.encode()
+	}
+	if hasPrev {
+		prev := queries.key(items[0])
+		prev.Backward = true
+		prev.Page = nextPage(p.number, -1)
+		response.PrevCursor = prev.encode()
+	}
+	return response, nil
+}
+
+// nextPage returns the number of the page delta pages away, 0 when the current page is unknown
+func nextPage(number, delta int32) int32 {
+	if number == 0 {
+		return 0
+	}
+	return max(number+delta, 0)
+}
@@ -0,0 +1,618 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/lushenle/plam/pkg/db"
+	mockdb "github.com/lushenle/plam/pkg/db/mock"
+	"github.com/lushenle/plam/pkg/token"
+	"github.com/lushenle/plam/pkg/util"
+	"github.com/stretchr/testify/require"
+)
+
+// randomCursor returns a cursor which survives an encode and decode round trip unchanged
+func randomCursor() pageCursor {
+	return pageCursor{
+		CreatedAt: time.Date(2024, 1, 2, 3, 4, 5, 6000, time.UTC),
+		ID:        uuid.New(),
+	}
+}
+
+func TestPageCursor(t *testing.T) {
+	cursor := randomCursor()
+	cursor.Backward = true
+
+	decoded, err := decodeCursor(cursor.encode())
+	require.NoError(t, err)
+	require.Equal(t, cursor, decoded)
+
+	sorted := randomCursor()
+	sorted.Sort = "-amount"
+	sorted.Value = "12.5"
+	decoded, err = decodeCursor(sorted.encode())
+	require.NoError(t, err)
+	require.Equal(t, sorted, decoded)
+
+	badAmount := sorted
+	badAmount.Value = "abc"
+	badTime := randomCursor()
+	badTime.Sort = "created_at"
+	badTime.Value = "yesterday"
+
+	for _, token := range []string{"", "not base64!", "e30", randomCursor().encode()[1:], badAmount.encode(), badTime.encode()} {
+		_, err = decodeCursor(token)
+		require.ErrorIs(t, err, errInvalidCursor, token)
+	}
+}
+
+func TestNewPage(t *testing.T) {
+	p, err := newPage(3, 10, "")
+	require.NoError(t, err)
+	require.Equal(t, page{number: 3, offset: 20, limit: 10, count: true}, p)
+
+	// The first page is used when neither page_id nor cursor are given
+	p, err = newPage(0, 10, "")
+	require.NoError(t, err)
+	require.Equal(t, page{number: 1, offset: 0, limit: 10, count: true}, p)
+
+	// The cursor takes precedence over page_id
+	cursor := randomCursor()
+	cursor.Page = 5
+	p, err = newPage(3, 10, cursor.encode())
+	require.NoError(t, err)
+	require.Equal(t, page{number: 5, limit: 10, cursor: &cursor, count: true}, p)
+
+	_, err = newPage(1, 10, "invalid")
+	require.ErrorIs(t, err, errInvalidCursor)
+}
+
+// testRow stands for a row of a list query
+type testRow struct {
+	createdAt time.Time
+	id        uuid.UUID
+}
+
+func testRowCursor(row testRow) pageCursor {
+	return pageCursor{CreatedAt: row.createdAt, ID: row.id}
+}
+
+// testPageQueries implements the queries of a list on top of rows, which are in ascending order
+func testPageQueries(rows []testRow) pageQueries[testRow] {
+	index := func(cursor pageCursor) int {
+		for i, row := range rows {
+			if row.id == cursor.ID {
+				return i
+			}
+		}
+		panic("unknown cursor")
+	}
+
+	return pageQueries[testRow]{
+		offset: func(offset, limit int32) ([]testRow, error) {
+			start := min(int(offset), len(rows))
+			return rows[start:min(start+int(limit), len(rows))], nil
+		},
+		after: func(cursor pageCursor, limit int32) ([]testRow, error) {
+			start := index(cursor) + 1
+			return rows[start:min(start+int(limit), len(rows))], nil
+		},
+		before: func(cursor pageCursor, limit int32) ([]testRow, error) {
+			var result []testRow
+			for i := index(cursor) - 1; i >= 0 && len(result) < int(limit); i-- {
+				result = append(result, rows[i])
+			}
+			return result, nil
+		},
+		count: func() (int64, error) {
+			return int64(len(rows)), nil
+		},
+		key: testRowCursor,
+	}
+}
+
+func TestFetchPage(t *testing.T) {
+	rows := make([]testRow, 7)
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := range rows {
+		rows[i] = testRow{createdAt: start.Add(time.Duration(i) * time.Minute), id: uuid.New()}
+	}
+	queries := testPageQueries(rows)
+
+	next := func(rsp listResponse[testRow]) page {
+		require.NotEmpty(t, rsp.NextCursor)
+		p, err := newPage(0, 3, rsp.NextCursor)
+		require.NoError(t, err)
+		return p
+	}
+	prev := func(rsp listResponse[testRow]) page {
+		require.NotEmpty(t, rsp.PrevCursor)
+		p, err := newPage(0, 3, rsp.PrevCursor)
+		require.NoError(t, err)
+		return p
+	}
+
+	first, err := fetchPage(page{number: 1, limit: 3, count: true}, queries)
+	require.NoError(t, err)
+	require.Equal(t, rows[0:3], first.Items)
+	require.Equal(t, int32(1), first.Page)
+	require.Equal(t, int32(3), first.PageSize)
+	require.Equal(t, int64(len(rows)), *first.Total)
+	require.True(t, first.HasMore)
+	require.Empty(t, first.PrevCursor)
+
+	second, err := fetchPage(next(first), queries)
+	require.NoError(t, err)
+	require.Equal(t, rows[3:6], second.Items)
+	require.Equal(t, int32(2), second.Page)
+
+	last, err := fetchPage(next(second), queries)
+	require.NoError(t, err)
+	require.Equal(t, rows[6:7], last.Items)
+	require.Equal(t, int32(3), last.Page)
+	require.False(t, last.HasMore)
+	require.Empty(t, last.NextCursor)
+
+	// Going backward from the last page returns the same pages in the same order
+	back, err := fetchPage(prev(last), queries)
+	require.NoError(t, err)
+	require.Equal(t, second.Items, back.Items)
+	require.Equal(t, second.Page, back.Page)
+	require.Equal(t, second.NextCursor, back.NextCursor)
+
+	back, err = fetchPage(prev(back), queries)
+	require.NoError(t, err)
+	require.Equal(t, first.Items, back.Items)
+	require.Empty(t, back.PrevCursor)
+
+	// Page numbers keep working next to cursors
+	byNumber, err := fetchPage(page{number: 2, offset: 3, limit: 3}, queries)
+	require.NoError(t, err)
+	require.Equal(t, second.Items, byNumber.Items)
+	require.Equal(t, second.PrevCursor, byNumber.PrevCursor)
+	require.Equal(t, second.NextCursor, byNumber.NextCursor)
+	require.Nil(t, byNumber.Total)
+}
+
+func TestRequestPage(t *testing.T) {
+	testCases := []struct {
+		query     string
+		wantCount bool
+		wantErr   bool
+	}{
+		{query: "", wantCount: true},
+		{query: "?count=true", wantCount: true},
+		{query: "?count=false", wantCount: false},
+		{query: "?count=0", wantCount: false},
+		{query: "?count=maybe", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.query, func(t *testing.T) {
+			ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+			ctx.Request = httptest.NewRequest(http.MethodPost, "/v1/projects/all"+tc.query, nil)
+
+			p, err := requestPage(ctx, 1, 5, "")
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.wantCount, p.count)
+		})
+	}
+}
+
+func TestPageSizeLimit(t *testing.T) {
+	// List and search requests accept the same page sizes
+	for _, size := range []int32{5, 100} {
+		require.NoError(t, binding.Validator.ValidateStruct(listRequest{PageSize: size}), size)
+		require.NoError(t, binding.Validator.ValidateStruct(searchRequest{Query: "q", PageSize: size}), size)
+	}
+	for _, size := range []int32{4, 101} {
+		require.Error(t, binding.Validator.ValidateStruct(listRequest{PageSize: size}), size)
+		require.Error(t, binding.Validator.ValidateStruct(searchRequest{Query: "q", PageSize: size}), size)
+	}
+}
+
+// listStubs expects the list and search queries of one resource with the given arguments, the
+// shared list cases set the results of the returned calls
+type listStubs struct {
+	list        func(store *mockdb.MockStore, offset, limit int32) *gomock.Call
+	listAfter   func(store *mockdb.MockStore, cursor pageCursor, limit int32) *gomock.Call
+	count       func(store *mockdb.MockStore) *gomock.Call
+	filter      func(store *mockdb.MockStore, arg db.FilterParams) *gomock.Call
+	countFilter func(store *mockdb.MockStore, filter db.ListFilter) *gomock.Call
+	search      func(store *mockdb.MockStore, query string, offset, limit int32) *gomock.Call
+	searchAfter func(store *mockdb.MockStore, query string, cursor pageCursor, limit int32) *gomock.Call
+	countSearch func(store *mockdb.MockStore, query string) *gomock.Call
+}
+
+// listResource describes the list and search endpoints of one resource for the shared list cases
+type listResource[T any] struct {
+	// path is the GET list endpoint, the POST list and search endpoints are path/all and path/search
+	path string
+	// nameField is the request field filtering by name, which is also the name sort column
+	nameField string
+	items     []T
+	stubs     listStubs
+}
+
+// listTestCase is a request to a list or search endpoint, body is sent by POST and query by GET
+type listTestCase struct {
+	name          string
+	body          gin.H
+	query         url.Values
+	setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+	buildStubs    func(store *mockdb.MockStore)
+	checkResponse func(recorder *httptest.ResponseRecorder)
+}
+
+// testListPostAPI runs the shared cases of the POST path/all endpoint of resource, then extra
+func testListPostAPI[T any](t *testing.T, resource listResource[T], extra ...listTestCase) {
+	user, _ := randomUser(t)
+	n := len(resource.items)
+	cursor := randomCursor()
+	auth := userAuth(user.Username)
+
+	testCases := []listTestCase{
+		{
+			name:      "OK",
+			body:      gin.H{"page_id": 1, "page_size": n},
+			setupAuth: auth,
+			buildStubs: func(store *mockdb.MockStore) {
+				resource.stubs.list(store, 0, int32(n)+1).Times(1).Return(resource.items, nil)
+				resource.stubs.count(store).Times(1).Return(int64(n), nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.NotEmpty(t, recorder.Header().Get(deprecationHeader))
+				requireBodyMatchList(t, recorder.Body, resource.items)
+			},
+		},
+		{
+			name:          "NoAuthorization",
+			body:          gin.H{"page_id": 1, "page_size": n},
+			setupAuth:     func(t *testing.T, request *http.Request, tokenMaker token.Maker) {},
+			buildStubs:    func(store *mockdb.MockStore) {},
+			checkResponse: requireStatus(t, http.StatusUnauthorized),
+		},
+		{
+			name:      "InternalError",
+			body:      gin.H{"page_id": 1, "page_size": n},
+			setupAuth: auth,
+			buildStubs: func(store *mockdb.MockStore) {
+				resource.stubs.list(store, 0, int32(n)+1).Times(1).Return([]T{}, sql.ErrConnDone)
+			},
+			checkResponse: requireStatus(t, http.StatusInternalServerError),
+		},
+		{
+			name:          "InvalidPageID",
+			body:          gin.H{"page_id": -1, "page_size": n},
+			setupAuth:     auth,
+			buildStubs:    func(store *mockdb.MockStore) {},
+			checkResponse: requireStatus(t, http.StatusBadRequest),
+		},
+		{
+			name:      "NextPage",
+			body:      gin.H{"page_size": n, "cursor": cursor.encode()},
+			setupAuth: auth,
+			buildStubs: func(store *mockdb.MockStore) {
+				resource.stubs.listAfter(store, cursor, int32(n)+1).Times(1).Return(resource.items, nil)
+				resource.stubs.count(store).Times(1).Return(int64(n), nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchList(t, recorder.Body, resource.items)
+			},
+		},
+		{
+			name:          "InvalidCursor",
+			body:          gin.H{"page_size": n, "cursor": "invalid"},
+			setupAuth:     auth,
+			buildStubs:    func(store *mockdb.MockStore) {},
+			checkResponse: requireStatus(t, http.StatusBadRequest),
+		},
+		{
+			name: "Filtered",
+			body: gin.H{
+				"page_size":        n,
+				"min_amount":       10,
+				resource.nameField: "john",
+				"sort":             "-amount",
+			},
+			setupAuth: auth,
+			buildStubs: func(store *mockdb.MockStore) {
+				minAmount := float32(10)
+				filter := db.ListFilter{
+					MinAmount: &minAmount,
+					Name:      "john",
+					Sort:      db.Sort{Column: "amount", Desc: true},
+				}
+				arg := db.FilterParams{ListFilter: filter, Limit: int32(n) + 1}
+				resource.stubs.filter(store, arg).Times(1).Return(resource.items, nil)
+				resource.stubs.countFilter(store, filter).Times(1).Return(int64(n), nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchList(t, recorder.Body, resource.items)
+			},
+		},
+		{
+			name:          "InvalidSort",
+			body:          gin.H{"page_size": n, "sort": "id"},
+			setupAuth:     auth,
+			buildStubs:    func(store *mockdb.MockStore) {},
+			checkResponse: requireStatus(t, http.StatusBadRequest),
+		},
+		{
+			name:          "InvalidAmountRange",
+			body:          gin.H{"page_size": n, "min_amount": 100, "max_amount": 10},
+			setupAuth:     auth,
+			buildStubs:    func(store *mockdb.MockStore) {},
+			checkResponse: requireStatus(t, http.StatusBadRequest),
+		},
+		{
+			name:          "CursorOfOtherSort",
+			body:          gin.H{"page_size": n, "sort": "-amount", "cursor": cursor.encode()},
+			setupAuth:     auth,
+			buildStubs:    func(store *mockdb.MockStore) {},
+			checkResponse: requireStatus(t, http.StatusBadRequest),
+		},
+		{
+			name:          "InvalidPageSize",
+			body:          gin.H{"page_id": 1, "page_size": 1000},
+			setupAuth:     auth,
+			buildStubs:    func(store *mockdb.MockStore) {},
+			checkResponse: requireStatus(t, http.StatusBadRequest),
+		},
+	}
+
+	runListTestCases(t, http.MethodPost, resource.path+"/all", append(testCases, extra...))
+}
+
+// testListGetAPI runs the shared cases of the GET path endpoint of resource, then extra
+func testListGetAPI[T any](t *testing.T, resource listResource[T], extra ...listTestCase) {
+	user, _ := randomUser(t)
+	n := len(resource.items)
+	pageSize := fmt.Sprint(n)
+	createdFrom := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	auth := userAuth(user.Username)
+
+	testCases := []listTestCase{
+		{
+			name:      "OK",
+			query:     url.Values{"page_size": {pageSize}},
+			setupAuth: auth,
+			buildStubs: func(store *mockdb.MockStore) {
+				resource.stubs.list(store, 0, int32(n)+1).Times(1).Return(resource.items, nil)
+				resource.stubs.count(store).Times(1).Return(int64(n), nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Empty(t, recorder.Header().Get(deprecationHeader))
+				requireBodyMatchList(t, recorder.Body, resource.items)
+			},
+		},
+		{
+			name:      "Search",
+			query:     url.Values{"page_size": {pageSize}, "q": {"john"}, "count": {"false"}},
+			setupAuth: auth,
+			buildStubs: func(store *mockdb.MockStore) {
+				resource.stubs.search(store, "john", 0, int32(n)+1).Times(1).Return(resource.items, nil)
+			},
+			checkResponse: requireStatus(t, http.StatusOK),
+		},
+		{
+			name: "Filtered",
+			query: url.Values{
+				"page_size":        {pageSize},
+				"max_amount":       {"500"},
+				"created_from":     {createdFrom.Format(time.RFC3339)},
+				resource.nameField: {"john"},
+				"sort":             {resource.nameField},
+			},
+			setupAuth: auth,
+			buildStubs: func(store *mockdb.MockStore) {
+				maxAmount := float32(500)
+				filter := db.ListFilter{
+					MaxAmount:   &maxAmount,
+					CreatedFrom: &createdFrom,
+					Name:        "john",
+					Sort:        db.Sort{Column: resource.nameField},
+				}
+				arg := db.FilterParams{ListFilter: filter, Limit: int32(n) + 1}
+				resource.stubs.filter(store, arg).Times(1).Return(resource.items, nil)
+				resource.stubs.countFilter(store, filter).Times(1).Return(int64(n), nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchList(t, recorder.Body, resource.items)
+			},
+		},
+		{
+			name:          "SearchWithFilter",
+			query:         url.Values{"page_size": {pageSize}, "q": {"john"}, "sort": {"-amount"}},
+			setupAuth:     auth,
+			buildStubs:    func(store *mockdb.MockStore) {},
+			checkResponse: requireStatus(t, http.StatusBadRequest),
+		},
+		{
+			name:          "InvalidCreatedFrom",
+			query:         url.Values{"page_size": {pageSize}, "created_from": {"yesterday"}},
+			setupAuth:     auth,
+			buildStubs:    func(store *mockdb.MockStore) {},
+			checkResponse: requireStatus(t, http.StatusBadRequest),
+		},
+		{
+			name:          "MissingPageSize",
+			query:         url.Values{},
+			setupAuth:     auth,
+			buildStubs:    func(store *mockdb.MockStore) {},
+			checkResponse: requireStatus(t, http.StatusBadRequest),
+		},
+		{
+			name:          "NoAuthorization",
+			query:         url.Values{"page_size": {pageSize}},
+			setupAuth:     func(t *testing.T, request *http.Request, tokenMaker token.Maker) {},
+			buildStubs:    func(store *mockdb.MockStore) {},
+			checkResponse: requireStatus(t, http.StatusUnauthorized),
+		},
+	}
+
+	runListTestCases(t, http.MethodGet, resource.path, append(testCases, extra...))
+}
+
+// testSearchPostAPI runs the shared cases of the POST path/search endpoint of resource, then extra
+func testSearchPostAPI[T any](t *testing.T, resource listResource[T], extra ...listTestCase) {
+	user, _ := randomUser(t)
+	n := len(resource.items)
+	cursor := randomCursor()
+	auth := userAuth(user.Username)
+	query := "john"
+
+	testCases := []listTestCase{
+		{
+			name:      "OK",
+			body:      gin.H{"query": query, "page_id": 1, "page_size": n},
+			setupAuth: auth,
+			buildStubs: func(store *mockdb.MockStore) {
+				resource.stubs.search(store, query, 0, int32(n)+1).Times(1).Return(resource.items, nil)
+				resource.stubs.countSearch(store, query).Times(1).Return(int64(n), nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.NotEmpty(t, recorder.Header().Get(deprecationHeader))
+				requireBodyMatchList(t, recorder.Body, resource.items)
+			},
+		},
+		{
+			name:          "NoAuthorization",
+			body:          gin.H{"query": query, "page_id": 1, "page_size": n},
+			setupAuth:     func(t *testing.T, request *http.Request, tokenMaker token.Maker) {},
+			buildStubs:    func(store *mockdb.MockStore) {},
+			checkResponse: requireStatus(t, http.StatusUnauthorized),
+		},
+		{
+			name:      "InternalError",
+			body:      gin.H{"query": query, "page_id": 1, "page_size": n},
+			setupAuth: auth,
+			buildStubs: func(store *mockdb.MockStore) {
+				resource.stubs.search(store, query, 0, int32(n)+1).Times(1).Return([]T{}, sql.ErrConnDone)
+			},
+			checkResponse: requireStatus(t, http.StatusInternalServerError),
+		},
+		{
+			name:          "InvalidPageID",
+			body:          gin.H{"query": query, "page_id": -1, "page_size": n},
+			setupAuth:     auth,
+			buildStubs:    func(store *mockdb.MockStore) {},
+			checkResponse: requireStatus(t, http.StatusBadRequest),
+		},
+		{
+			name:      "NextPage",
+			body:      gin.H{"query": query, "page_size": n, "cursor": cursor.encode()},
+			setupAuth: auth,
+			buildStubs: func(store *mockdb.MockStore) {
+				resource.stubs.searchAfter(store, query, cursor, int32(n)+1).Times(1).Return(resource.items, nil)
+				resource.stubs.countSearch(store, query).Times(1).Return(int64(n), nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchList(t, recorder.Body, resource.items)
+			},
+		},
+		{
+			name:          "InvalidCursor",
+			body:          gin.H{"query": query, "page_size": n, "cursor": "invalid"},
+			setupAuth:     auth,
+			buildStubs:    func(store *mockdb.MockStore) {},
+			checkResponse: requireStatus(t, http.StatusBadRequest),
+		},
+		{
+			name:          "InvalidPageSize",
+			body:          gin.H{"query": query, "page_id": 1, "page_size": 1000},
+			setupAuth:     auth,
+			buildStubs:    func(store *mockdb.MockStore) {},
+			checkResponse: requireStatus(t, http.StatusBadRequest),
+		},
+	}
+
+	runListTestCases(t, http.MethodPost, resource.path+"/search", append(testCases, extra...))
+}
+
+// runListTestCases sends the request of each case to path, the mock store fails the cases
+// calling a query they do not expect
+func runListTestCases(t *testing.T, method, path string, testCases []listTestCase) {
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			var request *http.Request
+			var err error
+			if method == http.MethodGet {
+				request, err = http.NewRequest(method, path+"?"+tc.query.Encode(), nil)
+				require.NoError(t, err)
+			} else {
+				data, err := json.Marshal(tc.body)
+				require.NoError(t, err)
+
+				request, err = http.NewRequest(method, path, bytes.NewReader(data))
+				require.NoError(t, err)
+				request.Header.Set("Content-Type", "application/json")
+			}
+
+			tc.setupAuth(t, request, server.tokenMaker)
+
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+// userAuth authorizes requests as username with the user role
+func userAuth(username string) func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+	return func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+		addAuthorization(t, request, tokenMaker, authorizationTypeBearer, username, util.RoleUser, time.Minute)
+	}
+}
+
+// requireStatus checks that the response has status code
+func requireStatus(t *testing.T, code int) func(recorder *httptest.ResponseRecorder) {
+	return func(recorder *httptest.ResponseRecorder) {
+		require.Equal(t, code, recorder.Code)
+	}
+}
+
+// requireBodyMatchList checks that the body is the only page of a list holding items
+func requireBodyMatchList[T any](t *testing.T, body *bytes.Buffer, items []T) {
+	data, err := io.ReadAll(body)
+	require.NoError(t, err)
+
+	var got listResponse[T]
+	err = json.Unmarshal(data, &got)
+	require.NoError(t, err)
+	require.Equal(t, items, got.Items)
+	require.Equal(t, int32(len(items)), got.PageSize)
+	require.NotNil(t, got.Total)
+	require.False(t, got.HasMore)
+}
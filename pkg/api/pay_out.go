@@ -50,7 +50,7 @@ type createPayOutRequest struct {
 func (server *Server) createPayOut(ctx *gin.Context) {
 	var req createPayOutRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errResponse(err))
+		writeProblem(ctx, http.StatusBadRequest, err)
 		return
 	}
 
@@ -62,52 +62,177 @@ func (server *Server) createPayOut(ctx *gin.Context) {
 
 	payOut, err := server.store.CreatePayOut(ctx, arg)
 	if err != nil {
-		if errors.Is(err, db.ErrUniqueViolation) {
-			ctx.JSON(http.StatusForbidden, errResponse(err))
-			return
-		}
-
-		ctx.JSON(http.StatusInternalServerError, errResponse(err))
+		writeError(ctx, err)
 		return
 	}
+	recordCreated(ctx, resourcePayOut, req.Amount)
 
 	ctx.JSON(http.StatusOK, payOut)
 }
 
-// listPayOuts lists all pay outs.
+// listPayOutsRequest is the request to list pay outs, optionally filtered and sorted.
 //
-//	@Summary		List all pay outs
-//	@Description	List all pay outs.
+//	@swagger:model
+type listPayOutsRequest struct {
+	listRequest
+	listFilter
+
+	// Owner keeps the pay outs whose owner contains Owner, ignoring case.
+	// example: john
+	// in: body
+	Owner string `json:"owner" form:"owner" binding:"omitempty,max=64"`
+
+	// Query searches the pay outs like the search endpoint, it cannot be combined with filters or sort.
+	// example: john
+	// in: query
+	Query string `json:"q" form:"q" binding:"omitempty,max=128"`
+}
+
+// listPayOuts lists pay outs, searching them when q is set.
+//
+//	@Summary		List pay outs
+//	@Description	List pay outs, optionally filtered by amount, creation time and owner, and sorted by sort, or search them with q.
 //	@Tags			pay_outs
-//	@Accept			json
 //	@Produce		json
-//	@Param			request	body		listRequest		true	"List Request"
-//	@Success		200		{object}	[]db.PayOut		"List of pay outs"
-//	@Failure		400		{object}	errorResponse	"Bad Request"
-//	@Failure		401		{object}	errorResponse	"Unauthorized"
-//	@Failure		403		{object}	errorResponse	"Forbidden"
-//	@Failure		500		{object}	errorResponse	"Internal Server Error"
-//	@Router			/pay_outs/all [post]
+//	@Param			request	query		listPayOutsRequest		true	"List Request"
+//	@Param			count	query		bool					false	"Set to false to omit the total count"
+//	@Success		200		{object}	listResponse[db.PayOut]	"List of pay outs"
+//	@Failure		400		{object}	errorResponse			"Bad Request"
+//	@Failure		401		{object}	errorResponse			"Unauthorized"
+//	@Failure		403		{object}	errorResponse			"Forbidden"
+//	@Failure		500		{object}	errorResponse			"Internal Server Error"
+//	@Router			/pay_outs [get]
 //	@security		ApiKeyAuth
 func (server *Server) listPayOuts(ctx *gin.Context) {
-	var req listRequest
+	var req listPayOutsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		writeProblem(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	server.findPayOuts(ctx, req)
+}
+
+// listPayOutsPost lists pay outs like listPayOuts, with the request in the body.
+//
+//	@Summary		List pay outs
+//	@Description	List pay outs like GET /pay_outs. Deprecated, use GET /pay_outs.
+//	@Tags			pay_outs
+//	@Accept			json
+//	@Produce		json
+//	@Param			count	query		bool					false	"Set to false to omit the total count"
+//	@Param			request	body		listPayOutsRequest		true	"List Request"
+//	@Success		200		{object}	listResponse[db.PayOut]	"List of pay outs"
+//	@Failure		400		{object}	errorResponse			"Bad Request"
+//	@Failure		401		{object}	errorResponse			"Unauthorized"
+//	@Failure		403		{object}	errorResponse			"Forbidden"
+//	@Failure		500		{object}	errorResponse			"Internal Server Error"
+//	@Router			/pay_outs/all [post]
+//	@Deprecated
+//	@security	ApiKeyAuth
+func (server *Server) listPayOutsPost(ctx *gin.Context) {
+	var req listPayOutsRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errResponse(err))
+		writeProblem(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	arg := db.ListPayOutsParams{
-		Offset: (req.PageID - 1) * req.PageSize,
-		Limit:  req.PageSize,
+	server.findPayOuts(ctx, req)
+}
+
+// findPayOuts responds with the page of pay outs selected by req
+func (server *Server) findPayOuts(ctx *gin.Context, req listPayOutsRequest) {
+	filter, err := req.dbFilter(db.PayOutSortColumns)
+	if err != nil {
+		writeProblem(ctx, http.StatusBadRequest, err)
+		return
+	}
+	filter.Name = req.Owner
+
+	if req.Query != "" && !filter.IsZero() {
+		writeProblem(ctx, http.StatusBadRequest, errSearchWithFilter)
+		return
 	}
 
-	payOuts, err := server.store.ListPayOuts(ctx, arg)
+	p, err := requestPage(ctx, req.PageID, req.PageSize, req.Cursor)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errResponse(err))
+		writeProblem(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, payOuts)
+	response, err := fetchPage(p, server.payOutQueries(ctx, req.Query, filter))
+	if err != nil {
+		if errors.Is(err, errInvalidCursor) {
+			writeProblem(ctx, http.StatusBadRequest, err)
+			return
+		}
+		writeError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// payOutQueries returns the queries searching pay outs for query when set, filtering them otherwise
+func (server *Server) payOutQueries(ctx *gin.Context, query string, filter db.ListFilter) pageQueries[db.PayOut] {
+	switch {
+	case query != "":
+		return pageQueries[db.PayOut]{
+			offset: func(offset, limit int32) ([]db.PayOut, error) {
+				return server.store.SearchPayOuts(ctx, db.SearchPayOutsParams{Query: query, Offset: offset, Limit: limit})
+			},
+			after: func(cursor pageCursor, limit int32) ([]db.PayOut, error) {
+				return server.store.SearchPayOutsAfter(ctx, db.SearchPayOutsAfterParams{
+					Query:     query,
+					CreatedAt: cursor.CreatedAt,
+					ID:        cursor.ID,
+					Limit:     limit,
+				})
+			},
+			before: func(cursor pageCursor, limit int32) ([]db.PayOut, error) {
+				return server.store.SearchPayOutsBefore(ctx, db.SearchPayOutsBeforeParams{
+					Query:     query,
+					CreatedAt: cursor.CreatedAt,
+					ID:        cursor.ID,
+					Limit:     limit,
+				})
+			},
+			count: func() (int64, error) {
+				return server.store.CountSearchPayOuts(ctx, query)
+			},
+			key: payOutCursor,
+		}
+	case !filter.IsZero():
+		return filterPageQueries(filter,
+			func(arg db.FilterParams) ([]db.PayOut, error) {
+				return server.store.FilterPayOuts(ctx, arg)
+			},
+			func(filter db.ListFilter) (int64, error) {
+				return server.store.CountFilterPayOuts(ctx, filter)
+			},
+			payOutCursor, db.PayOut.SortValue)
+	}
+
+	return pageQueries[db.PayOut]{
+		offset: func(offset, limit int32) ([]db.PayOut, error) {
+			return server.store.ListPayOuts(ctx, db.ListPayOutsParams{Offset: offset, Limit: limit})
+		},
+		after: func(cursor pageCursor, limit int32) ([]db.PayOut, error) {
+			return server.store.ListPayOutsAfter(ctx, db.ListPayOutsAfterParams{CreatedAt: cursor.CreatedAt, ID: cursor.ID, Limit: limit})
+		},
+		before: func(cursor pageCursor, limit int32) ([]db.PayOut, error) {
+			return server.store.ListPayOutsBefore(ctx, db.ListPayOutsBeforeParams{CreatedAt: cursor.CreatedAt, ID: cursor.ID, Limit: limit})
+		},
+		count: func() (int64, error) {
+			return server.store.CountPayOuts(ctx)
+		},
+		key: payOutCursor,
+	}
+}
+
+// payOutCursor returns the position of payOut in list and search results
+func payOutCursor(payOut db.PayOut) pageCursor {
+	return pageCursor{CreatedAt: payOut.CreatedAt, ID: payOut.ID}
 }
 
 // getPayOut gets a pay out by ID.
@@ -129,64 +254,45 @@ func (server *Server) listPayOuts(ctx *gin.Context) {
 func (server *Server) getPayOut(ctx *gin.Context) {
 	var req getRequest
 	if err := ctx.ShouldBindUri(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errResponse(err))
+		writeProblem(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	payOut, err := server.store.GetPayOut(ctx, uuid.MustParse(req.ID))
 	if err != nil {
-		if errors.Is(err, db.ErrRecordNotFound) {
-			ctx.JSON(http.StatusNotFound, errResponse(err))
-			return
-		}
-
-		ctx.JSON(http.StatusInternalServerError, errResponse(err))
+		writeError(ctx, err)
 		return
 	}
 
 	ctx.JSON(http.StatusOK, payOut)
 }
 
-// searchPayOuts searches pay outs by owner.
+// searchPayOuts searches pay outs like listPayOuts with q set.
 //
 //	@Summary		Search pay outs
-//	@Description	Search pay outs by owner.
+//	@Description	Search pay outs like GET /pay_outs?q=. Deprecated, use GET /pay_outs?q=.
 //	@Tags			pay_outs
 //	@Accept			json
 //	@Produce		json
-//	@Param			request	body		searchRequest	true	"Search Request"
-//	@Success		200		{object}	[]db.PayOut		"Pay Outs found"
-//	@Failure		400		{object}	errorResponse	"Bad Request"
-//	@Failure		401		{object}	errorResponse	"Unauthorized"
-//	@Failure		403		{object}	errorResponse	"Forbidden"
-//	@Failure		404		{object}	errorResponse	"Not Found"
-//	@Failure		500		{object}	errorResponse	"Internal Server Error"
+//	@Param			count	query		bool					false	"Set to false to omit the total count"
+//	@Param			request	body		searchRequest			true	"Search Request"
+//	@Success		200		{object}	listResponse[db.PayOut]	"Pay Outs found"
+//	@Failure		400		{object}	errorResponse			"Bad Request"
+//	@Failure		401		{object}	errorResponse			"Unauthorized"
+//	@Failure		403		{object}	errorResponse			"Forbidden"
+//	@Failure		404		{object}	errorResponse			"Not Found"
+//	@Failure		500		{object}	errorResponse			"Internal Server Error"
 //	@Router			/pay_outs/search [post]
-//	@security		ApiKeyAuth
+//	@Deprecated
+//	@security	ApiKeyAuth
 func (server *Server) searchPayOuts(ctx *gin.Context) {
 	var req searchRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errResponse(err))
-		return
-	}
-
-	arg := db.SearchPayOutsParams{
-		Owner:  req.Query,
-		Offset: (req.PageID - 1) * req.PageSize,
-		Limit:  req.PageSize,
-	}
-
-	payOuts, err := server.store.SearchPayOuts(ctx, arg)
-	if err != nil {
-		if errors.Is(err, db.ErrRecordNotFound) {
-			ctx.JSON(http.StatusNotFound, errResponse(err))
-			return
-		}
-		ctx.JSON(http.StatusInternalServerError, errResponse(err))
+		writeProblem(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, payOuts)
+	server.findPayOuts(ctx, listPayOutsRequest{listRequest: req.pagination(), Query: req.Query})
 }
 
 // deletePayOut deletes a pay out by ID.
@@ -208,19 +314,16 @@ func (server *Server) searchPayOuts(ctx *gin.Context) {
 func (server *Server) deletePayOut(ctx *gin.Context) {
 	var req getRequest
 	if err := ctx.ShouldBindUri(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errResponse(err))
+		writeProblem(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	payOut, err := server.store.DeletePayOut(ctx, uuid.MustParse(req.ID))
 	if err != nil {
-		if errors.Is(err, db.ErrRecordNotFound) {
-			ctx.JSON(http.StatusNotFound, errResponse(err))
-			return
-		}
-		ctx.JSON(http.StatusInternalServerError, errResponse(err))
+		writeError(ctx, err)
 		return
 	}
+	recordDeleted(ctx, resourcePayOut)
 
 	ctx.JSON(http.StatusOK, payOut)
 }
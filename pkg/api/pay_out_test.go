@@ -253,266 +253,15 @@ func TestGetPayOutAPI(t *testing.T) {
 }
 
 func TestListPayOutAPI(t *testing.T) {
-	user, _ := randomUser(t)
-
-	n := 6
-	payOuts := make([]db.PayOut, n)
-	for i := 0; i < n; i++ {
-		payOuts[i] = randomPayOut(t)
-	}
-
-	testCases := []struct {
-		name          string
-		body          gin.H
-		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
-		buildStubs    func(store *mockdb.MockStore)
-		checkResponse func(recorder *httptest.ResponseRecorder)
-	}{
-		{
-			name: "OK",
-			body: gin.H{
-				"page_id":   1,
-				"page_size": n,
-			},
-			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, util.RoleUser, time.Minute)
-			},
-			buildStubs: func(store *mockdb.MockStore) {
-				arg := db.ListPayOutsParams{
-					Offset: 0,
-					Limit:  int32(n),
-				}
-				store.EXPECT().ListPayOuts(gomock.Any(), gomock.Eq(arg)).Times(1).Return(payOuts, nil)
-			},
-			checkResponse: func(recorder *httptest.ResponseRecorder) {
-				require.Equal(t, http.StatusOK, recorder.Code)
-				requireBodyMatchPayOuts(t, recorder.Body, payOuts)
-			},
-		},
-		{
-			name: "NoAuthorization",
-			body: gin.H{
-				"page_id":   1,
-				"page_size": n,
-			},
-			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {},
-			buildStubs: func(store *mockdb.MockStore) {
-				store.EXPECT().ListPayOuts(gomock.Any(), gomock.Any()).Times(0)
-			},
-			checkResponse: func(recorder *httptest.ResponseRecorder) {
-				require.Equal(t, http.StatusUnauthorized, recorder.Code)
-			},
-		},
-		{
-			name: "InternalError",
-			body: gin.H{
-				"page_id":   1,
-				"page_size": n,
-			},
-			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, util.RoleUser, time.Minute)
-			},
-			buildStubs: func(store *mockdb.MockStore) {
-				store.EXPECT().ListPayOuts(gomock.Any(), gomock.Any()).Times(1).Return([]db.PayOut{}, sql.ErrConnDone)
-			},
-			checkResponse: func(recorder *httptest.ResponseRecorder) {
-				require.Equal(t, http.StatusInternalServerError, recorder.Code)
-			},
-		},
-		{
-			name: "InvalidPageID",
-			body: gin.H{
-				"page_id":   -1,
-				"page_size": n,
-			},
-			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, util.RoleUser, time.Minute)
-			},
-			buildStubs: func(store *mockdb.MockStore) {
-				store.EXPECT().ListPayOuts(gomock.Any(), gomock.Any()).Times(0)
-			},
-			checkResponse: func(recorder *httptest.ResponseRecorder) {
-				require.Equal(t, http.StatusBadRequest, recorder.Code)
-			},
-		},
-		{
-			name: "InvalidPageSize",
-			body: gin.H{
-				"page_id":   1,
-				"page_size": 1000,
-			},
-			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, util.RoleUser, time.Minute)
-			},
-			buildStubs: func(store *mockdb.MockStore) {
-				store.EXPECT().ListPayOuts(gomock.Any(), gomock.Any()).Times(0)
-			},
-			checkResponse: func(recorder *httptest.ResponseRecorder) {
-				require.Equal(t, http.StatusBadRequest, recorder.Code)
-			},
-		},
-	}
-
-	for _, tc := range testCases {
-		t.Run(tc.name, func(t *testing.T) {
-			ctrl := gomock.NewController(t)
-			defer ctrl.Finish()
-
-			store := mockdb.NewMockStore(ctrl)
-			tc.buildStubs(store)
-
-			server := newTestServer(t, store)
-			recorder := httptest.NewRecorder()
-
-			// Marshal body data to JSON
-			data, err := json.Marshal(tc.body)
-			require.NoError(t, err)
-
-			url := "/v1/pay_outs/all"
-			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
-			require.NoError(t, err)
-
-			request.Header.Set("Content-Type", "application/json")
-			tc.setupAuth(t, request, server.tokenMaker)
+	testListPostAPI(t, payOutListResource(t))
+}
 
-			server.router.ServeHTTP(recorder, request)
-			tc.checkResponse(recorder)
-		})
-	}
+func TestListPayOutsGetAPI(t *testing.T) {
+	testListGetAPI(t, payOutListResource(t))
 }
 
 func TestSearchPayOutAPI(t *testing.T) {
-	user, _ := randomUser(t)
-
-	n := 6
-	payOuts := make([]db.PayOut, n)
-	for i := 0; i < n; i++ {
-		payOuts[i] = randomPayOut(t)
-	}
-	testCases := []struct {
-		name          string
-		body          gin.H
-		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
-		buildStubs    func(store *mockdb.MockStore)
-		checkResponse func(recorder *httptest.ResponseRecorder)
-	}{
-		{
-			name: "OK",
-			body: gin.H{
-				"query":     payOuts[0].Owner,
-				"page_id":   1,
-				"page_size": n,
-			},
-			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, util.RoleUser, time.Minute)
-			},
-			buildStubs: func(store *mockdb.MockStore) {
-				arg := db.SearchPayOutsParams{
-					Owner:  payOuts[0].Owner,
-					Offset: 0,
-					Limit:  int32(n),
-				}
-				store.EXPECT().SearchPayOuts(gomock.Any(), gomock.Eq(arg)).Times(1).Return(payOuts, nil)
-			},
-			checkResponse: func(recorder *httptest.ResponseRecorder) {
-				require.Equal(t, http.StatusOK, recorder.Code)
-				requireBodyMatchPayOuts(t, recorder.Body, payOuts)
-			},
-		},
-		{
-			name: "NoAuthorization",
-			body: gin.H{
-				"query":     payOuts[0].Owner,
-				"page_id":   1,
-				"page_size": n,
-			},
-			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {},
-			buildStubs: func(store *mockdb.MockStore) {
-				store.EXPECT().SearchPayOuts(gomock.Any(), gomock.Any()).Times(0)
-			},
-			checkResponse: func(recorder *httptest.ResponseRecorder) {
-				require.Equal(t, http.StatusUnauthorized, recorder.Code)
-			},
-		},
-		{
-			name: "InternalError",
-			body: gin.H{
-				"query":     payOuts[0].Owner,
-				"page_id":   1,
-				"page_size": n,
-			},
-			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, util.RoleUser, time.Minute)
-			},
-			buildStubs: func(store *mockdb.MockStore) {
-				store.EXPECT().SearchPayOuts(gomock.Any(), gomock.Any()).Times(1).Return([]db.PayOut{}, sql.ErrConnDone)
-			},
-			checkResponse: func(recorder *httptest.ResponseRecorder) {
-				require.Equal(t, http.StatusInternalServerError, recorder.Code)
-			},
-		},
-		{
-			name: "InvalidPageID",
-			body: gin.H{
-				"query":     payOuts[0].Owner,
-				"page_id":   -1,
-				"page_size": n,
-			},
-			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, util.RoleUser, time.Minute)
-			},
-			buildStubs: func(store *mockdb.MockStore) {
-				store.EXPECT().SearchPayOuts(gomock.Any(), gomock.Any()).Times(0)
-			},
-			checkResponse: func(recorder *httptest.ResponseRecorder) {
-				require.Equal(t, http.StatusBadRequest, recorder.Code)
-			},
-		},
-		{
-			name: "InvalidPageSize",
-			body: gin.H{
-				"query":     payOuts[0].Owner,
-				"page_id":   1,
-				"page_size": 1000,
-			},
-			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, util.RoleUser, time.Minute)
-			},
-			buildStubs: func(store *mockdb.MockStore) {
-				store.EXPECT().SearchPayOuts(gomock.Any(), gomock.Any()).Times(0)
-			},
-			checkResponse: func(recorder *httptest.ResponseRecorder) {
-				require.Equal(t, http.StatusBadRequest, recorder.Code)
-			},
-		},
-	}
-
-	for _, tc := range testCases {
-		t.Run(tc.name, func(t *testing.T) {
-			ctrl := gomock.NewController(t)
-			defer ctrl.Finish()
-
-			store := mockdb.NewMockStore(ctrl)
-			tc.buildStubs(store)
-
-			server := newTestServer(t, store)
-			recorder := httptest.NewRecorder()
-
-			// Marshal body data to JSON
-			data, err := json.Marshal(tc.body)
-			require.NoError(t, err)
-
-			url := "/v1/pay_outs/search"
-			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
-			require.NoError(t, err)
-
-			request.Header.Set("Content-Type", "application/json")
-			tc.setupAuth(t, request, server.tokenMaker)
-
-			server.router.ServeHTTP(recorder, request)
-			tc.checkResponse(recorder)
-		})
-	}
+	testSearchPostAPI(t, payOutListResource(t))
 }
 
 func TestDeletePayOutAPI(t *testing.T) {
@@ -651,12 +400,45 @@ func requireBodyMatchPayOut(t *testing.T, body *bytes.Buffer, project db.PayOut)
 	require.Equal(t, project, gotPayOut)
 }
 
-func requireBodyMatchPayOuts(t *testing.T, body *bytes.Buffer, projects []db.PayOut) {
-	data, err := io.ReadAll(body)
-	require.NoError(t, err)
+// payOutListResource returns the pay out list endpoints with random pay outs as results
+func payOutListResource(t *testing.T) listResource[db.PayOut] {
+	items := make([]db.PayOut, 6)
+	for i := range items {
+		items[i] = randomPayOut(t)
+	}
 
-	var gotPayOuts []db.PayOut
-	err = json.Unmarshal(data, &gotPayOuts)
-	require.NoError(t, err)
-	require.Equal(t, projects, gotPayOuts)
+	return listResource[db.PayOut]{
+		path:      "/v1/pay_outs",
+		nameField: "owner",
+		items:     items,
+		stubs: listStubs{
+			list: func(store *mockdb.MockStore, offset, limit int32) *gomock.Call {
+				return store.EXPECT().ListPayOuts(gomock.Any(), gomock.Eq(db.ListPayOutsParams{Offset: offset, Limit: limit}))
+			},
+			listAfter: func(store *mockdb.MockStore, cursor pageCursor, limit int32) *gomock.Call {
+				arg := db.ListPayOutsAfterParams{CreatedAt: cursor.CreatedAt, ID: cursor.ID, Limit: limit}
+				return store.EXPECT().ListPayOutsAfter(gomock.Any(), gomock.Eq(arg))
+			},
+			count: func(store *mockdb.MockStore) *gomock.Call {
+				return store.EXPECT().CountPayOuts(gomock.Any())
+			},
+			filter: func(store *mockdb.MockStore, arg db.FilterParams) *gomock.Call {
+				return store.EXPECT().FilterPayOuts(gomock.Any(), gomock.Eq(arg))
+			},
+			countFilter: func(store *mockdb.MockStore, filter db.ListFilter) *gomock.Call {
+				return store.EXPECT().CountFilterPayOuts(gomock.Any(), gomock.Eq(filter))
+			},
+			search: func(store *mockdb.MockStore, query string, offset, limit int32) *gomock.Call {
+				arg := db.SearchPayOutsParams{Query: query, Offset: offset, Limit: limit}
+				return store.EXPECT().SearchPayOuts(gomock.Any(), gomock.Eq(arg))
+			},
+			searchAfter: func(store *mockdb.MockStore, query string, cursor pageCursor, limit int32) *gomock.Call {
+				arg := db.SearchPayOutsAfterParams{Query: query, CreatedAt: cursor.CreatedAt, ID: cursor.ID, Limit: limit}
+				return store.EXPECT().SearchPayOutsAfter(gomock.Any(), gomock.Eq(arg))
+			},
+			countSearch: func(store *mockdb.MockStore, query string) *gomock.Call {
+				return store.EXPECT().CountSearchPayOuts(gomock.Any(), gomock.Eq(query))
+			},
+		},
+	}
 }
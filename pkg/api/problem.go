@@ -0,0 +1,269 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	"github.com/lushenle/plam/pkg/db"
+	"github.com/lushenle/plam/pkg/util"
+)
+
+// problemContentType is the media type of error responses, defined by RFC 7807
+const problemContentType = "application/problem+json"
+
+// Stable error codes, clients match on them rather than on the human readable detail
+const (
+	codeBadRequest       = "bad_request"
+	codeValidationFailed = "validation_failed"
+	codePasswordPolicy   = "password_policy_violation"
+	codeUnauthorized     = "unauthorized"
+	codeForbidden        = "forbidden"
+	codeNotFound         = "not_found"
+	codeConflict         = "conflict"
+	codePayloadTooLarge  = "payload_too_large"
+	codeRateLimited      = "rate_limited"
+	codeInternal         = "internal"
+	codeUnavailable      = "unavailable"
+)
+
+// errorResponse is an RFC 7807 problem details object, with the code, request ID and
+// validation errors as extension members.
+//
+//	@swagger:model
+type errorResponse struct {
+	// Type is always about:blank, the code tells problems apart.
+	Type string `json:"type" example:"about:blank"`
+
+	// Title is the HTTP status text.
+	Title string `json:"title" example:"Not Found"`
+
+	// Status is the HTTP status code.
+	Status int `json:"status" example:"404"`
+
+	// Detail explains this occurrence of the problem.
+	Detail string `json:"detail,omitempty" example:"resource not found"`
+
+	// Instance is the path of the request.
+	Instance string `json:"instance,omitempty" example:"/v1/projects/123e4567-e89b-12d3-a456-426614174000"`
+
+	// Code identifies the problem and never changes.
+	Code string `json:"code" example:"not_found"`
+
+	// RequestID identifies the request in the server logs.
+	RequestID string `json:"request_id,omitempty"`
+
+	// Errors lists the invalid fields of a validation_failed problem.
+	Errors []fieldError `json:"errors,omitempty"`
+
+	// Violations lists the rules broken by the password of a password_policy_violation problem.
+	Violations []passwordViolation `json:"violations,omitempty"`
+}
+
+// passwordViolation describes a rule of the password policy broken by a password.
+//
+//	@swagger:model
+type passwordViolation struct {
+	// Rule identifies the broken rule.
+	Rule string `json:"rule" example:"min_length"`
+
+	// Param is the parameter of the rule.
+	Param string `json:"param,omitempty" example:"8"`
+
+	// Message explains the violation.
+	Message string `json:"message" example:"must be at least 8 characters long"`
+}
+
+// fieldError describes an invalid field of a request.
+//
+//	@swagger:model
+type fieldError struct {
+	// Field is the name of the field in the request.
+	Field string `json:"field" example:"page_size"`
+
+	// Rule is the validation rule the field breaks.
+	Rule string `json:"rule" example:"min"`
+
+	// Param is the parameter of the rule.
+	Param string `json:"param,omitempty" example:"5"`
+
+	// Message explains the error.
+	Message string `json:"message" example:"page_size must be 5 or greater"`
+}
+
+// statusCodes are the codes used for statuses which errors do not refine
+var statusCodes = map[int]string{
+	http.StatusBadRequest:            codeBadRequest,
+	http.StatusUnauthorized:          codeUnauthorized,
+	http.StatusForbidden:             codeForbidden,
+	http.StatusNotFound:              codeNotFound,
+	http.StatusConflict:              codeConflict,
+	http.StatusRequestEntityTooLarge: codePayloadTooLarge,
+	http.StatusTooManyRequests:       codeRateLimited,
+	http.StatusServiceUnavailable:    codeUnavailable,
+}
+
+// errorStatus maps the errors of the store and of request binding to a status
+func errorStatus(err error) int {
+	var validationErrs validator.ValidationErrors
+	switch {
+	case errors.As(err, &validationErrs):
+		return http.StatusBadRequest
+	case errors.Is(err, db.ErrRecordNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, db.ErrInvalidInvitation):
+		return http.StatusForbidden
+	}
+
+	switch db.ErrorCode(err) {
+	case db.UniqueViolation, db.ForeignKeyViolation:
+		return http.StatusConflict
+	}
+	return http.StatusInternalServerError
+}
+
+// writeError responds with the problem matching err, see errorStatus
+func writeError(ctx *gin.Context, err error) {
+	writeProblem(ctx, errorStatus(err), err)
+}
+
+// writeProblem responds with a problem of status describing err, in the locale of the request.
+// Database errors and server errors are not disclosed, they are left to the access log.
+func writeProblem(ctx *gin.Context, status int, err error) {
+	locale := requestLocale(ctx)
+	ctx.Header("Content-Type", problemContentType)
+	ctx.Header(contentLanguageHeader, locale)
+	ctx.JSON(status, newProblem(ctx, status, err, translator(locale)))
+}
+
+// abortWithProblem responds like writeProblem and stops the handler chain
+func abortWithProblem(ctx *gin.Context, status int, err error) {
+	writeProblem(ctx, status, err)
+	ctx.Abort()
+}
+
+func newProblem(ctx *gin.Context, status int, err error, trans ut.Translator) errorResponse {
+	p := errorResponse{
+		Type:      "about:blank",
+		Title:     http.StatusText(status),
+		Status:    status,
+		Instance:  ctx.Request.URL.Path,
+		Code:      statusCodes[status],
+		RequestID: requestID(ctx),
+	}
+	if p.Code == "" {
+		p.Code = codeInternal
+	}
+
+	var (
+		validationErrs validator.ValidationErrors
+		policyErr      *util.PasswordPolicyError
+	)
+	switch {
+	case status >= http.StatusInternalServerError:
+		_ = ctx.Error(err)
+		p.Detail = strings.ToLower(p.Title)
+	case errors.As(err, &validationErrs):
+		p.Code = codeValidationFailed
+		p.Detail = "the request has invalid fields"
+		p.Errors = fieldErrors(validationErrs, trans)
+	case errors.As(err, &policyErr):
+		p.Code = codePasswordPolicy
+		p.Detail = "password does not satisfy policy"
+		p.Violations = passwordViolations(policyErr.Violations, trans)
+	case errors.Is(err, db.ErrRecordNotFound):
+		p.Detail = "resource not found"
+	case db.ErrorCode(err) == db.UniqueViolation:
+		p.Detail = "resource already exists"
+	case db.ErrorCode(err) == db.ForeignKeyViolation:
+		p.Detail = "resource references a missing resource or is still referenced"
+	case err != nil && isDisclosed(err):
+		p.Detail = err.Error()
+	default:
+		// Other errors may carry internal details, such as the messages of libraries
+		p.Detail = strings.ToLower(p.Title)
+	}
+	p.Detail = translate(trans, p.Detail)
+	return p
+}
+
+// isDisclosed reports whether the message of err may be sent to clients: the domain
+// messages, and the errors describing what is wrong with the request
+func isDisclosed(err error) bool {
+	if isDomainMessage(err.Error()) || errors.Is(err, db.ErrInvalidSort) || errors.Is(err, io.EOF) {
+		return true
+	}
+
+	var (
+		syntaxErr   *json.SyntaxError
+		typeErr     *json.UnmarshalTypeError
+		numErr      *strconv.NumError
+		timeErr     *time.ParseError
+		maxBytesErr *http.MaxBytesError
+	)
+	return errors.As(err, &syntaxErr) || errors.As(err, &typeErr) || errors.As(err, &numErr) ||
+		errors.As(err, &timeErr) || errors.As(err, &maxBytesErr)
+}
+
+// passwordViolations describes every rule broken by a password, in the language of trans
+func passwordViolations(violations []util.PasswordViolation, trans ut.Translator) []passwordViolation {
+	described := make([]passwordViolation, len(violations))
+	for i, v := range violations {
+		message, err := trans.T(v.Template(), v.Param)
+		if err != nil {
+			message = v.Message()
+		}
+		described[i] = passwordViolation{
+			Rule:    v.Rule,
+			Param:   v.Param,
+			Message: message,
+		}
+	}
+	return described
+}
+
+// fieldErrors describes every invalid field of a request, in the language of trans
+func fieldErrors(errs validator.ValidationErrors, trans ut.Translator) []fieldError {
+	fields := make([]fieldError, len(errs))
+	for i, fe := range errs {
+		fields[i] = fieldError{
+			Field:   fe.Field(),
+			Rule:    fe.Tag(),
+			Param:   fe.Param(),
+			Message: fe.Translate(trans),
+		}
+	}
+	return fields
+}
+
+var registerFieldNames sync.Once
+
+// useRequestFieldNames makes validation errors name fields as they appear in requests,
+// after their json, form or uri tag
+func useRequestFieldNames() {
+	registerFieldNames.Do(func() {
+		validate, ok := binding.Validator.Engine().(*validator.Validate)
+		if !ok {
+			return
+		}
+		validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+			for _, key := range []string{"json", "form", "uri"} {
+				name, _, _ := strings.Cut(field.Tag.Get(key), ",")
+				if name != "" && name != "-" {
+					return name
+				}
+			}
+			return field.Name
+		})
+	})
+}
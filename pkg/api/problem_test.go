@@ -0,0 +1,184 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/lushenle/plam/pkg/db"
+	"github.com/lushenle/plam/pkg/log"
+	"github.com/lushenle/plam/pkg/util"
+	"github.com/stretchr/testify/require"
+)
+
+// requireProblem checks that the response is a problem of status and code
+func requireProblem(t *testing.T, recorder *httptest.ResponseRecorder, status int, code string) errorResponse {
+	require.Equal(t, status, recorder.Code)
+	require.Equal(t, problemContentType, recorder.Header().Get("Content-Type"))
+
+	var problem errorResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &problem))
+	require.Equal(t, "about:blank", problem.Type)
+	require.Equal(t, http.StatusText(status), problem.Title)
+	require.Equal(t, status, problem.Status)
+	require.Equal(t, code, problem.Code)
+	return problem
+}
+
+func TestWriteError(t *testing.T) {
+	testCases := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantCode   string
+		wantDetail string
+	}{
+		{
+			name:       "NotFound",
+			err:        fmt.Errorf("get project: %w", db.ErrRecordNotFound),
+			wantStatus: http.StatusNotFound,
+			wantCode:   codeNotFound,
+			wantDetail: "resource not found",
+		},
+		{
+			name:       "UniqueViolation",
+			err:        &pgconn.PgError{Code: db.UniqueViolation, Message: "duplicate key value violates unique constraint"},
+			wantStatus: http.StatusConflict,
+			wantCode:   codeConflict,
+			wantDetail: "resource already exists",
+		},
+		{
+			name:       "ForeignKeyViolation",
+			err:        db.ErrForeignKeyViolation,
+			wantStatus: http.StatusConflict,
+			wantCode:   codeConflict,
+			wantDetail: "resource references a missing resource or is still referenced",
+		},
+		{
+			name:       "InvalidInvitation",
+			err:        db.ErrInvalidInvitation,
+			wantStatus: http.StatusForbidden,
+			wantCode:   codeForbidden,
+			wantDetail: db.ErrInvalidInvitation.Error(),
+		},
+		{
+			name:       "Internal",
+			err:        errors.New("connection reset by peer"),
+			wantStatus: http.StatusInternalServerError,
+			wantCode:   codeInternal,
+			wantDetail: "internal server error",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(recorder)
+			request := httptest.NewRequest(http.MethodGet, "/v1/projects/1", nil)
+			ctx.Request = request.WithContext(log.WithRequestID(request.Context(), "req-1"))
+
+			writeError(ctx, tc.err)
+
+			problem := requireProblem(t, recorder, tc.wantStatus, tc.wantCode)
+			require.Equal(t, tc.wantDetail, problem.Detail)
+			require.Equal(t, "/v1/projects/1", problem.Instance)
+			require.Equal(t, "req-1", problem.RequestID)
+		})
+	}
+}
+
+func TestValidationProblem(t *testing.T) {
+	useRequestFieldNames()
+
+	router := gin.New()
+	router.POST("/items", func(ctx *gin.Context) {
+		var req struct {
+			PageSize int32  `json:"page_size" binding:"required,min=5"`
+			Email    string `json:"email" binding:"required,email"`
+			Owner    string `json:"owner"`
+		}
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			writeProblem(ctx, http.StatusBadRequest, err)
+			return
+		}
+		ctx.JSON(http.StatusOK, req)
+	})
+
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodPost, "/items", strings.NewReader(`{"page_size": 1}`))
+	router.ServeHTTP(recorder, request)
+
+	problem := requireProblem(t, recorder, http.StatusBadRequest, codeValidationFailed)
+	require.Equal(t, []fieldError{
+		{Field: "page_size", Rule: "min", Param: "5", Message: "page_size must be 5 or greater"},
+		{Field: "email", Rule: "required", Message: "email is a required field"},
+	}, problem.Errors)
+
+	// Malformed bodies are bad requests without field details
+	recorder = httptest.NewRecorder()
+	request = httptest.NewRequest(http.MethodPost, "/items", strings.NewReader(`"not an object"`))
+	router.ServeHTTP(recorder, request)
+
+	problem = requireProblem(t, recorder, http.StatusBadRequest, codeBadRequest)
+	require.Empty(t, problem.Errors)
+}
+
+func TestPasswordPolicyProblem(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(recorder)
+	ctx.Request = httptest.NewRequest(http.MethodPost, "/v1/users", nil)
+
+	policyErr := &util.PasswordPolicyError{Violations: []util.PasswordViolation{
+		{Rule: util.PasswordRuleMinLength, Param: "8"},
+		{Rule: util.PasswordRuleDigit},
+	}}
+	writeProblem(ctx, http.StatusBadRequest, policyErr)
+
+	problem := requireProblem(t, recorder, http.StatusBadRequest, codePasswordPolicy)
+	require.Equal(t, []passwordViolation{
+		{Rule: util.PasswordRuleMinLength, Param: "8", Message: "must be at least 8 characters long"},
+		{Rule: util.PasswordRuleDigit, Message: "must contain a digit"},
+	}, problem.Violations)
+
+	// The violations are translated like the detail
+	recorder = httptest.NewRecorder()
+	ctx, _ = gin.CreateTestContext(recorder)
+	ctx.Request = httptest.NewRequest(http.MethodPost, "/v1/users", nil)
+	ctx.Request.Header.Set(acceptLanguageHeader, "zh-CN")
+	writeProblem(ctx, http.StatusBadRequest, policyErr)
+
+	problem = requireProblem(t, recorder, http.StatusBadRequest, codePasswordPolicy)
+	require.Equal(t, "密码不符合安全策略", problem.Detail)
+	require.Equal(t, []passwordViolation{
+		{Rule: util.PasswordRuleMinLength, Param: "8", Message: "长度至少为 8 个字符"},
+		{Rule: util.PasswordRuleDigit, Message: "必须包含数字"},
+	}, problem.Violations)
+}
+
+func TestPasswordRulesTranslated(t *testing.T) {
+	rules := []string{
+		util.PasswordRuleMinLength, util.PasswordRuleMaxLength, util.PasswordRuleUpper, util.PasswordRuleLower,
+		util.PasswordRuleDigit, util.PasswordRuleSymbol, util.PasswordRuleUsername, util.PasswordRuleEmail,
+		util.PasswordRuleBreached, util.PasswordRuleHistory,
+	}
+	for _, rule := range rules {
+		template := util.PasswordViolation{Rule: rule}.Template()
+		require.NotEmpty(t, template, rule)
+		require.True(t, isDomainMessage(template), rule)
+	}
+}
+
+func TestNoRouteProblem(t *testing.T) {
+	server := newTestServer(t, nil)
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodGet, "/v1/unknown", nil)
+
+	server.router.ServeHTTP(recorder, request)
+	requireProblem(t, recorder, http.StatusNotFound, codeNotFound)
+}
@@ -50,7 +50,7 @@ type createProjectRequest struct {
 func (server *Server) createProject(ctx *gin.Context) {
 	var req createProjectRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errResponse(err))
+		writeProblem(ctx, http.StatusBadRequest, err)
 		return
 	}
 
@@ -62,11 +62,7 @@ func (server *Server) createProject(ctx *gin.Context) {
 
 	project, err := server.store.CreateProject(ctx, arg)
 	if err != nil {
-		if errors.Is(err, db.ErrUniqueViolation) {
-			ctx.JSON(http.StatusForbidden, errResponse(err))
-			return
-		}
-		ctx.JSON(http.StatusInternalServerError, errResponse(err))
+		writeError(ctx, err)
 		return
 	}
 
@@ -77,55 +73,188 @@ func (server *Server) createProject(ctx *gin.Context) {
 //
 //	@swagger:model
 type listRequest struct {
-	// PageID is the page number.
-	// Required: true
+	// PageID is the page number, it defaults to 1 and is ignored when Cursor is set.
 	// example: 1
 	// in: body
 	// minimum: 1
-	PageID int32 `json:"page_id" binding:"required,min=1"`
+	PageID int32 `json:"page_id" form:"page_id" binding:"omitempty,min=1"`
 
 	// PageSize is the number of projects per page.
 	// Required: true
 	// example: 5
 	// in: body
 	// minimum: 5
-	// maximum: 10
-	PageSize int32 `json:"page_size" binding:"required,min=5,max=100"`
+	// maximum: 100
+	PageSize int32 `json:"page_size" form:"page_size" binding:"required,min=5,max=100"`
+
+	// Cursor is the next_cursor or prev_cursor of a previous response.
+	// in: body
+	Cursor string `json:"cursor" form:"cursor" binding:"omitempty,max=256"`
 }
 
-// listProjects lists all projects.
+// listProjectsRequest is the request to list projects, optionally filtered and sorted.
+//
+//	@swagger:model
+type listProjectsRequest struct {
+	listRequest
+	listFilter
+
+	// Name keeps the projects whose name contains Name, ignoring case.
+	// example: project
+	// in: body
+	Name string `json:"name" form:"name" binding:"omitempty,max=64"`
+
+	// Query searches the projects like the search endpoint, it cannot be combined with filters or sort.
+	// example: john
+	// in: query
+	Query string `json:"q" form:"q" binding:"omitempty,max=128"`
+}
+
+// listProjects lists projects, searching them when q is set.
 //
 //	@Summary		List projects
-//	@Description	List all projects.
+//	@Description	List projects, optionally filtered by amount, creation time and name, and sorted by sort, or search them with q.
 //	@Tags			projects
-//	@Accept			json
 //	@Produce		json
-//	@Param			request	body		listRequest		true	"List Request"
-//	@Success		200		{array}		[]db.Project	"List of projects"
-//	@Failure		400		{object}	errorResponse	"Bad Request"
-//	@Failure		401		{object}	errorResponse	"Unauthorized"
-//	@Failure		403		{object}	errorResponse	"Forbidden"
-//	@Failure		500		{object}	errorResponse	"Internal Server Error"
-//	@Router			/projects/all [post]
+//	@Param			request	query		listProjectsRequest			true	"List Request"
+//	@Param			count	query		bool						false	"Set to false to omit the total count"
+//	@Success		200		{object}	listResponse[db.Project]	"List of projects"
+//	@Failure		400		{object}	errorResponse				"Bad Request"
+//	@Failure		401		{object}	errorResponse				"Unauthorized"
+//	@Failure		403		{object}	errorResponse				"Forbidden"
+//	@Failure		500		{object}	errorResponse				"Internal Server Error"
+//	@Router			/projects [get]
 //	@security		ApiKeyAuth
 func (server *Server) listProjects(ctx *gin.Context) {
-	var req listRequest
+	var req listProjectsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		writeProblem(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	server.findProjects(ctx, req)
+}
+
+// listProjectsPost lists projects like listProjects, with the request in the body.
+//
+//	@Summary		List projects
+//	@Description	List projects like GET /projects. Deprecated, use GET /projects.
+//	@Tags			projects
+//	@Accept			json
+//	@Produce		json
+//	@Param			count	query		bool						false	"Set to false to omit the total count"
+//	@Param			request	body		listProjectsRequest			true	"List Request"
+//	@Success		200		{object}	listResponse[db.Project]	"List of projects"
+//	@Failure		400		{object}	errorResponse				"Bad Request"
+//	@Failure		401		{object}	errorResponse				"Unauthorized"
+//	@Failure		403		{object}	errorResponse				"Forbidden"
+//	@Failure		500		{object}	errorResponse				"Internal Server Error"
+//	@Router			/projects/all [post]
+//	@Deprecated
+//	@security	ApiKeyAuth
+func (server *Server) listProjectsPost(ctx *gin.Context) {
+	var req listProjectsRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errResponse(err))
+		writeProblem(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	server.findProjects(ctx, req)
+}
+
+// findProjects responds with the page of projects selected by req
+func (server *Server) findProjects(ctx *gin.Context, req listProjectsRequest) {
+	filter, err := req.dbFilter(db.ProjectSortColumns)
+	if err != nil {
+		writeProblem(ctx, http.StatusBadRequest, err)
 		return
 	}
+	filter.Name = req.Name
 
-	arg := db.ListProjectsParams{
-		Offset: (req.PageID - 1) * req.PageSize,
-		Limit:  req.PageSize,
+	if req.Query != "" && !filter.IsZero() {
+		writeProblem(ctx, http.StatusBadRequest, errSearchWithFilter)
+		return
 	}
-	projects, err := server.store.ListProjects(ctx, arg)
+
+	p, err := requestPage(ctx, req.PageID, req.PageSize, req.Cursor)
+	if err != nil {
+		writeProblem(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	response, err := fetchPage(p, server.projectQueries(ctx, req.Query, filter))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errResponse(err))
+		if errors.Is(err, errInvalidCursor) {
+			writeProblem(ctx, http.StatusBadRequest, err)
+			return
+		}
+		writeError(ctx, err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, projects)
+	ctx.JSON(http.StatusOK, response)
+}
+
+// projectQueries returns the queries searching projects for query when set, filtering them otherwise
+func (server *Server) projectQueries(ctx *gin.Context, query string, filter db.ListFilter) pageQueries[db.Project] {
+	switch {
+	case query != "":
+		return pageQueries[db.Project]{
+			offset: func(offset, limit int32) ([]db.Project, error) {
+				return server.store.SearchProjects(ctx, db.SearchProjectsParams{Query: query, Offset: offset, Limit: limit})
+			},
+			after: func(cursor pageCursor, limit int32) ([]db.Project, error) {
+				return server.store.SearchProjectsAfter(ctx, db.SearchProjectsAfterParams{
+					Query:     query,
+					CreatedAt: cursor.CreatedAt,
+					ID:        cursor.ID,
+					Limit:     limit,
+				})
+			},
+			before: func(cursor pageCursor, limit int32) ([]db.Project, error) {
+				return server.store.SearchProjectsBefore(ctx, db.SearchProjectsBeforeParams{
+					Query:     query,
+					CreatedAt: cursor.CreatedAt,
+					ID:        cursor.ID,
+					Limit:     limit,
+				})
+			},
+			count: func() (int64, error) {
+				return server.store.CountSearchProjects(ctx, query)
+			},
+			key: projectCursor,
+		}
+	case !filter.IsZero():
+		return filterPageQueries(filter,
+			func(arg db.FilterParams) ([]db.Project, error) {
+				return server.store.FilterProjects(ctx, arg)
+			},
+			func(filter db.ListFilter) (int64, error) {
+				return server.store.CountFilterProjects(ctx, filter)
+			},
+			projectCursor, db.Project.SortValue)
+	}
+
+	return pageQueries[db.Project]{
+		offset: func(offset, limit int32) ([]db.Project, error) {
+			return server.store.ListProjects(ctx, db.ListProjectsParams{Offset: offset, Limit: limit})
+		},
+		after: func(cursor pageCursor, limit int32) ([]db.Project, error) {
+			return server.store.ListProjectsAfter(ctx, db.ListProjectsAfterParams{CreatedAt: cursor.CreatedAt, ID: cursor.ID, Limit: limit})
+		},
+		before: func(cursor pageCursor, limit int32) ([]db.Project, error) {
+			return server.store.ListProjectsBefore(ctx, db.ListProjectsBeforeParams{CreatedAt: cursor.CreatedAt, ID: cursor.ID, Limit: limit})
+		},
+		count: func() (int64, error) {
+			return server.store.CountProjects(ctx)
+		},
+		key: projectCursor,
+	}
+}
+
+// projectCursor returns the position of project in list and search results
+func projectCursor(project db.Project) pageCursor {
+	return pageCursor{CreatedAt: project.CreatedAt, ID: project.ID}
 }
 
 // getRequest is a struct that represents the request to get a project.
@@ -159,17 +288,13 @@ type getRequest struct {
 func (server *Server) getProject(ctx *gin.Context) {
 	var req getRequest
 	if err := ctx.ShouldBindUri(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errResponse(err))
+		writeProblem(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	project, err := server.store.GetProject(ctx, uuid.MustParse(req.ID))
 	if err != nil {
-		if errors.Is(err, db.ErrRecordNotFound) {
-			ctx.JSON(http.StatusNotFound, errResponse(err))
-			return
-		}
-		ctx.JSON(http.StatusInternalServerError, errResponse(err))
+		writeError(ctx, err)
 		return
 	}
 
@@ -180,63 +305,62 @@ func (server *Server) getProject(ctx *gin.Context) {
 //
 //	@swagger:model
 type searchRequest struct {
-	// Query is the search query.
+	// Query is matched by full-text search against names, descriptions and subjects
+	// and by trigram similarity against names, so partial and misspelled names match.
 	// Required: true
 	// example: project1
 	// in: body
 	Query string `json:"query" binding:"required"`
 
-	// PageID is the page number.
-	// Required: true
+	// PageID is the page number, it defaults to 1 and is ignored when Cursor is set.
 	// example: 1
 	// in: body
 	// minimum: 1
-	PageID int32 `json:"page_id" binding:"required,min=1"`
+	PageID int32 `json:"page_id" binding:"omitempty,min=1"`
 
 	// PageSize is the number of projects per page.
 	// Required: true
 	// example: 5
 	// in: body
 	// minimum: 5
-	// maximum: 10
-	PageSize int32 `json:"page_size" binding:"required,min=5,max=10"`
+	// maximum: 100
+	PageSize int32 `json:"page_size" binding:"required,min=5,max=100"`
+
+	// Cursor is the next_cursor or prev_cursor of a previous response.
+	// in: body
+	Cursor string `json:"cursor" binding:"omitempty,max=256"`
+}
+
+// pagination returns the pagination fields of req
+func (req searchRequest) pagination() listRequest {
+	return listRequest{PageID: req.PageID, PageSize: req.PageSize, Cursor: req.Cursor}
 }
 
-// searchProjects searches projects.
+// searchProjects searches projects like listProjects with q set.
 //
 //	@Summary		Search projects
-//	@Description	Search projects.
+//	@Description	Search projects like GET /projects?q=. Deprecated, use GET /projects?q=.
 //	@Tags			projects
 //	@Accept			json
 //	@Produce		json
-//	@Param			request	body		listRequest		true	"Search Request"
-//	@Success		200		{array}		[]db.Project	"List of projects"
-//	@Failure		400		{object}	string			"Bad Request"
-//	@Failure		401		{object}	string			"Unauthorized"
-//	@Failure		403		{object}	string			"Forbidden"
-//	@Failure		500		{object}	string			"Internal Server Error"
+//	@Param			count	query		bool						false	"Set to false to omit the total count"
+//	@Param			request	body		searchRequest				true	"Search Request"
+//	@Success		200		{object}	listResponse[db.Project]	"List of projects"
+//	@Failure		400		{object}	errorResponse				"Bad Request"
+//	@Failure		401		{object}	errorResponse				"Unauthorized"
+//	@Failure		403		{object}	errorResponse				"Forbidden"
+//	@Failure		500		{object}	errorResponse				"Internal Server Error"
 //	@Router			/projects/search [post]
-//	@security		ApiKeyAuth
+//	@Deprecated
+//	@security	ApiKeyAuth
 func (server *Server) searchProjects(ctx *gin.Context) {
 	var req searchRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errResponse(err))
+		writeProblem(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	arg := db.SearchProjectsParams{
-		Name:   req.Query,
-		Offset: (req.PageID - 1) * req.PageSize,
-		Limit:  req.PageSize,
-	}
-
-	projects, err := server.store.SearchProjects(ctx, arg)
-	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errResponse(err))
-		return
-	}
-
-	ctx.JSON(http.StatusOK, projects)
+	server.findProjects(ctx, listProjectsRequest{listRequest: req.pagination(), Query: req.Query})
 }
 
 // deleteProject deletes a project by ID.
@@ -258,17 +382,13 @@ func (server *Server) searchProjects(ctx *gin.Context) {
 func (server *Server) deleteProject(ctx *gin.Context) {
 	var req getRequest
 	if err := ctx.ShouldBindUri(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errResponse(err))
+		writeProblem(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	project, err := server.store.DeleteProject(ctx, uuid.MustParse(req.ID))
 	if err != nil {
-		if errors.Is(err, db.ErrRecordNotFound) {
-			ctx.JSON(http.StatusNotFound, errResponse(err))
-			return
-		}
-		ctx.JSON(http.StatusInternalServerError, errResponse(err))
+		writeError(ctx, err)
 		return
 	}
 
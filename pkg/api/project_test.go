@@ -253,266 +253,15 @@ func TestGetProjectAPI(t *testing.T) {
 }
 
 func TestListProjectsAPI(t *testing.T) {
-	user, _ := randomUser(t)
-
-	n := 6
-	projects := make([]db.Project, n)
-	for i := 0; i < n; i++ {
-		projects[i] = randomProject(t)
-	}
-
-	testCases := []struct {
-		name          string
-		body          gin.H
-		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
-		buildStubs    func(store *mockdb.MockStore)
-		checkResponse func(recorder *httptest.ResponseRecorder)
-	}{
-		{
-			name: "OK",
-			body: gin.H{
-				"page_id":   1,
-				"page_size": n,
-			},
-			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, util.RoleUser, time.Minute)
-			},
-			buildStubs: func(store *mockdb.MockStore) {
-				arg := db.ListProjectsParams{
-					Offset: 0,
-					Limit:  int32(n),
-				}
-				store.EXPECT().ListProjects(gomock.Any(), gomock.Eq(arg)).Times(1).Return(projects, nil)
-			},
-			checkResponse: func(recorder *httptest.ResponseRecorder) {
-				require.Equal(t, http.StatusOK, recorder.Code)
-				requireBodyMatchProjects(t, recorder.Body, projects)
-			},
-		},
-		{
-			name: "NoAuthorization",
-			body: gin.H{
-				"page_id":   1,
-				"page_size": n,
-			},
-			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {},
-			buildStubs: func(store *mockdb.MockStore) {
-				store.EXPECT().ListProjects(gomock.Any(), gomock.Any()).Times(0)
-			},
-			checkResponse: func(recorder *httptest.ResponseRecorder) {
-				require.Equal(t, http.StatusUnauthorized, recorder.Code)
-			},
-		},
-		{
-			name: "InternalError",
-			body: gin.H{
-				"page_id":   1,
-				"page_size": n,
-			},
-			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, util.RoleUser, time.Minute)
-			},
-			buildStubs: func(store *mockdb.MockStore) {
-				store.EXPECT().ListProjects(gomock.Any(), gomock.Any()).Times(1).Return([]db.Project{}, sql.ErrConnDone)
-			},
-			checkResponse: func(recorder *httptest.ResponseRecorder) {
-				require.Equal(t, http.StatusInternalServerError, recorder.Code)
-			},
-		},
-		{
-			name: "InvalidPageID",
-			body: gin.H{
-				"page_id":   -1,
-				"page_size": n,
-			},
-			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, util.RoleUser, time.Minute)
-			},
-			buildStubs: func(store *mockdb.MockStore) {
-				store.EXPECT().ListProjects(gomock.Any(), gomock.Any()).Times(0)
-			},
-			checkResponse: func(recorder *httptest.ResponseRecorder) {
-				require.Equal(t, http.StatusBadRequest, recorder.Code)
-			},
-		},
-		{
-			name: "InvalidPageSize",
-			body: gin.H{
-				"page_id":   1,
-				"page_size": 1000,
-			},
-			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, util.RoleUser, time.Minute)
-			},
-			buildStubs: func(store *mockdb.MockStore) {
-				store.EXPECT().ListProjects(gomock.Any(), gomock.Any()).Times(0)
-			},
-			checkResponse: func(recorder *httptest.ResponseRecorder) {
-				require.Equal(t, http.StatusBadRequest, recorder.Code)
-			},
-		},
-	}
-
-	for _, tc := range testCases {
-		t.Run(tc.name, func(t *testing.T) {
-			ctrl := gomock.NewController(t)
-			defer ctrl.Finish()
-
-			store := mockdb.NewMockStore(ctrl)
-			tc.buildStubs(store)
-
-			server := newTestServer(t, store)
-			recorder := httptest.NewRecorder()
-
-			// Marshal body data to JSON
-			data, err := json.Marshal(tc.body)
-			require.NoError(t, err)
-
-			url := "/v1/projects/all"
-			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
-			require.NoError(t, err)
-
-			request.Header.Set("Content-Type", "application/json")
-			tc.setupAuth(t, request, server.tokenMaker)
+	testListPostAPI(t, projectListResource(t))
+}
 
-			server.router.ServeHTTP(recorder, request)
-			tc.checkResponse(recorder)
-		})
-	}
+func TestListProjectsGetAPI(t *testing.T) {
+	testListGetAPI(t, projectListResource(t))
 }
 
 func TestSearchProjectAPI(t *testing.T) {
-	user, _ := randomUser(t)
-
-	n := 6
-	projects := make([]db.Project, n)
-	for i := 0; i < n; i++ {
-		projects[i] = randomProject(t)
-	}
-	testCases := []struct {
-		name          string
-		body          gin.H
-		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
-		buildStubs    func(store *mockdb.MockStore)
-		checkResponse func(recorder *httptest.ResponseRecorder)
-	}{
-		{
-			name: "OK",
-			body: gin.H{
-				"query":     projects[0].Name,
-				"page_id":   1,
-				"page_size": n,
-			},
-			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, util.RoleUser, time.Minute)
-			},
-			buildStubs: func(store *mockdb.MockStore) {
-				arg := db.SearchProjectsParams{
-					Name:   projects[0].Name,
-					Offset: 0,
-					Limit:  int32(n),
-				}
-				store.EXPECT().SearchProjects(gomock.Any(), gomock.Eq(arg)).Times(1).Return(projects, nil)
-			},
-			checkResponse: func(recorder *httptest.ResponseRecorder) {
-				require.Equal(t, http.StatusOK, recorder.Code)
-				requireBodyMatchProjects(t, recorder.Body, projects)
-			},
-		},
-		{
-			name: "NoAuthorization",
-			body: gin.H{
-				"query":     projects[0].Name,
-				"page_id":   1,
-				"page_size": n,
-			},
-			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {},
-			buildStubs: func(store *mockdb.MockStore) {
-				store.EXPECT().SearchProjects(gomock.Any(), gomock.Any()).Times(0)
-			},
-			checkResponse: func(recorder *httptest.ResponseRecorder) {
-				require.Equal(t, http.StatusUnauthorized, recorder.Code)
-			},
-		},
-		{
-			name: "InternalError",
-			body: gin.H{
-				"query":     projects[0].Name,
-				"page_id":   1,
-				"page_size": n,
-			},
-			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, util.RoleUser, time.Minute)
-			},
-			buildStubs: func(store *mockdb.MockStore) {
-				store.EXPECT().SearchProjects(gomock.Any(), gomock.Any()).Times(1).Return([]db.Project{}, sql.ErrConnDone)
-			},
-			checkResponse: func(recorder *httptest.ResponseRecorder) {
-				require.Equal(t, http.StatusInternalServerError, recorder.Code)
-			},
-		},
-		{
-			name: "InvalidPageID",
-			body: gin.H{
-				"query":     projects[0].Name,
-				"page_id":   -1,
-				"page_size": n,
-			},
-			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, util.RoleUser, time.Minute)
-			},
-			buildStubs: func(store *mockdb.MockStore) {
-				store.EXPECT().SearchProjects(gomock.Any(), gomock.Any()).Times(0)
-			},
-			checkResponse: func(recorder *httptest.ResponseRecorder) {
-				require.Equal(t, http.StatusBadRequest, recorder.Code)
-			},
-		},
-		{
-			name: "InvalidPageSize",
-			body: gin.H{
-				"query":     projects[0].Name,
-				"page_id":   1,
-				"page_size": 1000,
-			},
-			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, util.RoleUser, time.Minute)
-			},
-			buildStubs: func(store *mockdb.MockStore) {
-				store.EXPECT().SearchProjects(gomock.Any(), gomock.Any()).Times(0)
-			},
-			checkResponse: func(recorder *httptest.ResponseRecorder) {
-				require.Equal(t, http.StatusBadRequest, recorder.Code)
-			},
-		},
-	}
-
-	for _, tc := range testCases {
-		t.Run(tc.name, func(t *testing.T) {
-			ctrl := gomock.NewController(t)
-			defer ctrl.Finish()
-
-			store := mockdb.NewMockStore(ctrl)
-			tc.buildStubs(store)
-
-			server := newTestServer(t, store)
-			recorder := httptest.NewRecorder()
-
-			// Marshal body data to JSON
-			data, err := json.Marshal(tc.body)
-			require.NoError(t, err)
-
-			url := "/v1/projects/search"
-			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
-			require.NoError(t, err)
-
-			request.Header.Set("Content-Type", "application/json")
-			tc.setupAuth(t, request, server.tokenMaker)
-
-			server.router.ServeHTTP(recorder, request)
-			tc.checkResponse(recorder)
-		})
-	}
+	testSearchPostAPI(t, projectListResource(t))
 }
 
 func TestDeleteProjectAPI(t *testing.T) {
@@ -651,12 +400,45 @@ func requireBodyMatchProject(t *testing.T, body *bytes.Buffer, project db.Projec
 	require.Equal(t, project, gotProject)
 }
 
-func requireBodyMatchProjects(t *testing.T, body *bytes.Buffer, projects []db.Project) {
-	data, err := io.ReadAll(body)
-	require.NoError(t, err)
+// projectListResource returns the project list endpoints with random projects as results
+func projectListResource(t *testing.T) listResource[db.Project] {
+	items := make([]db.Project, 6)
+	for i := range items {
+		items[i] = randomProject(t)
+	}
 
-	var gotProjects []db.Project
-	err = json.Unmarshal(data, &gotProjects)
-	require.NoError(t, err)
-	require.Equal(t, projects, gotProjects)
+	return listResource[db.Project]{
+		path:      "/v1/projects",
+		nameField: "name",
+		items:     items,
+		stubs: listStubs{
+			list: func(store *mockdb.MockStore, offset, limit int32) *gomock.Call {
+				return store.EXPECT().ListProjects(gomock.Any(), gomock.Eq(db.ListProjectsParams{Offset: offset, Limit: limit}))
+			},
+			listAfter: func(store *mockdb.MockStore, cursor pageCursor, limit int32) *gomock.Call {
+				arg := db.ListProjectsAfterParams{CreatedAt: cursor.CreatedAt, ID: cursor.ID, Limit: limit}
+				return store.EXPECT().ListProjectsAfter(gomock.Any(), gomock.Eq(arg))
+			},
+			count: func(store *mockdb.MockStore) *gomock.Call {
+				return store.EXPECT().CountProjects(gomock.Any())
+			},
+			filter: func(store *mockdb.MockStore, arg db.FilterParams) *gomock.Call {
+				return store.EXPECT().FilterProjects(gomock.Any(), gomock.Eq(arg))
+			},
+			countFilter: func(store *mockdb.MockStore, filter db.ListFilter) *gomock.Call {
+				return store.EXPECT().CountFilterProjects(gomock.Any(), gomock.Eq(filter))
+			},
+			search: func(store *mockdb.MockStore, query string, offset, limit int32) *gomock.Call {
+				arg := db.SearchProjectsParams{Query: query, Offset: offset, Limit: limit}
+				return store.EXPECT().SearchProjects(gomock.Any(), gomock.Eq(arg))
+			},
+			searchAfter: func(store *mockdb.MockStore, query string, cursor pageCursor, limit int32) *gomock.Call {
+				arg := db.SearchProjectsAfterParams{Query: query, CreatedAt: cursor.CreatedAt, ID: cursor.ID, Limit: limit}
+				return store.EXPECT().SearchProjectsAfter(gomock.Any(), gomock.Eq(arg))
+			},
+			countSearch: func(store *mockdb.MockStore, query string) *gomock.Call {
+				return store.EXPECT().CountSearchProjects(gomock.Any(), gomock.Eq(query))
+			},
+		},
+	}
 }
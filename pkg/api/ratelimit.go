@@ -0,0 +1,96 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lushenle/plam/pkg/util"
+	"golang.org/x/time/rate"
+)
+
+const (
+	// rateLimiterIdleTimeout is how long the limiter of an inactive client is kept
+	rateLimiterIdleTimeout = 3 * time.Minute
+	// rateLimiterSweepInterval is how often idle limiters are removed
+	rateLimiterSweepInterval = time.Minute
+)
+
+// ipRateLimiter keeps a token bucket for every client IP, the limits can be changed at runtime
+type ipRateLimiter struct {
+	mu        sync.Mutex
+	limit     rate.Limit
+	burst     int
+	clients   map[string]*clientLimiter
+	lastSweep time.Time
+}
+
+type clientLimiter struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+func newIPRateLimiter(config util.RateLimit) *ipRateLimiter {
+	limiter := &ipRateLimiter{
+		clients:   make(map[string]*clientLimiter),
+		lastSweep: time.Now(),
+	}
+	limiter.SetLimit(config)
+	return limiter
+}
+
+// SetLimit applies new limits to every client, rate limiting is disabled when RequestsPerSecond is 0
+func (l *ipRateLimiter) SetLimit(config util.RateLimit) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.limit = rate.Limit(config.RequestsPerSecond)
+	l.burst = config.Burst
+	for _, client := range l.clients {
+		client.limiter.SetLimit(l.limit)
+		client.limiter.SetBurst(l.burst)
+	}
+}
+
+// Allow reports whether a request from ip may proceed
+func (l *ipRateLimiter) Allow(ip string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.limit <= 0 {
+		return true
+	}
+
+	now := time.Now()
+	if now.Sub(l.lastSweep) > rateLimiterSweepInterval {
+		for key, client := range l.clients {
+			if now.Sub(client.lastSeen) > rateLimiterIdleTimeout {
+				delete(l.clients, key)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	client, ok := l.clients[ip]
+	if !ok {
+		client = &clientLimiter{limiter: rate.NewLimiter(l.limit, l.burst)}
+		l.clients[ip] = client
+	}
+	client.lastSeen = now
+
+	return client.limiter.AllowN(now, 1)
+}
+
+func rateLimitMiddleware(limiter *ipRateLimiter) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if !limiter.Allow(ctx.ClientIP()) {
+			ctx.Header("Retry-After", "1")
+			abortWithProblem(ctx, http.StatusTooManyRequests, errors.New("too many requests"))
+			return
+		}
+
+		ctx.Next()
+	}
+}
@@ -0,0 +1,107 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/lushenle/plam/pkg/db/mock"
+	"github.com/lushenle/plam/pkg/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIPRateLimiter(t *testing.T) {
+	limiter := newIPRateLimiter(util.RateLimit{})
+	for i := 0; i < 100; i++ {
+		require.True(t, limiter.Allow("10.0.0.1"))
+	}
+
+	limiter.SetLimit(util.RateLimit{RequestsPerSecond: 0.001, Burst: 2})
+	require.True(t, limiter.Allow("10.0.0.1"))
+	require.True(t, limiter.Allow("10.0.0.1"))
+	require.False(t, limiter.Allow("10.0.0.1"))
+
+	// Every client has its own bucket
+	require.True(t, limiter.Allow("10.0.0.2"))
+
+	// New limits apply to existing clients
+	limiter.SetLimit(util.RateLimit{})
+	require.True(t, limiter.Allow("10.0.0.1"))
+}
+
+func TestRateLimitMiddleware(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	server := newTestServer(t, store)
+
+	config := server.config
+	config.Server.RateLimit = util.RateLimit{RequestsPerSecond: 0.001, Burst: 1}
+	server.Reload(config)
+
+	codes := make([]int, 0, 2)
+	for i := 0; i < 2; i++ {
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodPost, "/v1/users/login", nil)
+		require.NoError(t, err)
+
+		server.router.ServeHTTP(recorder, request)
+		codes = append(codes, recorder.Code)
+	}
+	require.Equal(t, []int{http.StatusBadRequest, http.StatusTooManyRequests}, codes)
+
+	// Probes are not rate limited
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodGet, "/v1/livez", nil)
+	require.NoError(t, err)
+
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+}
+
+func TestRateLimitForwardedFor(t *testing.T) {
+	testCases := []struct {
+		name           string
+		trustedProxies []string
+		wantCodes      []int
+	}{
+		{
+			// A client cannot escape the limit by rotating X-Forwarded-For
+			name:      "UntrustedPeer",
+			wantCodes: []int{http.StatusBadRequest, http.StatusTooManyRequests},
+		},
+		{
+			name:           "TrustedProxy",
+			trustedProxies: []string{"10.0.0.0/8"},
+			wantCodes:      []int{http.StatusBadRequest, http.StatusBadRequest},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			server := newTestServerWithConfig(t, mockdb.NewMockStore(ctrl), func(config *util.Config) {
+				config.Server.TrustedProxies = tc.trustedProxies
+				config.Server.RateLimit = util.RateLimit{RequestsPerSecond: 0.001, Burst: 1}
+			})
+
+			codes := make([]int, 0, 2)
+			for i := 0; i < 2; i++ {
+				recorder := httptest.NewRecorder()
+				request, err := http.NewRequest(http.MethodPost, "/v1/users/login", nil)
+				require.NoError(t, err)
+				request.RemoteAddr = "10.0.0.1:12345"
+				request.Header.Set("X-Forwarded-For", fmt.Sprintf("203.0.113.%d", i+1))
+
+				server.router.ServeHTTP(recorder, request)
+				codes = append(codes, recorder.Code)
+			}
+			require.Equal(t, tc.wantCodes, codes)
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lushenle/plam/pkg/db"
+)
+
+// defaultSearchLimit is the number of hits returned when the request has no limit
+const defaultSearchLimit = 20
+
+// searchAllRequest is the request to search every kind of record.
+//
+//	@swagger:model
+type searchAllRequest struct {
+	// Query is matched by full-text search against names, descriptions and subjects
+	// and by trigram similarity against names.
+	// Required: true
+	// example: john
+	// in: query
+	Query string `form:"q" binding:"required,max=128"`
+
+	// Limit is the maximum number of hits, it defaults to 20.
+	// example: 20
+	// in: query
+	// minimum: 1
+	// maximum: 100
+	Limit int32 `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+// searchAllResponse holds the hits of a search, the best matches first.
+//
+//	@swagger:model
+type searchAllResponse struct {
+	// Hits are projects, incomes, loans and pay outs. Their snippet is HTML: the matched text,
+	// escaped, with the matching words wrapped in <mark></mark>.
+	Hits []db.SearchAllRow `json:"hits"`
+}
+
+// searchAll searches projects, incomes, loans and pay outs at once.
+//
+//	@Summary		Search all records
+//	@Description	Search projects, incomes, loans and pay outs, ranked by relevance.
+//	@Tags			search
+//	@Produce		json
+//	@Param			q		query		string				true	"Search query"
+//	@Param			limit	query		int					false	"Maximum number of hits"	minimum(1)	maximum(100)	default(20)
+//	@Success		200		{object}	searchAllResponse	"Ranked hits"
+//	@Failure		400		{object}	errorResponse		"Bad Request"
+//	@Failure		401		{object}	errorResponse		"Unauthorized"
+//	@Failure		500		{object}	errorResponse		"Internal Server Error"
+//	@Router			/search [get]
+//	@security		ApiKeyAuth
+func (server *Server) searchAll(ctx *gin.Context) {
+	var req searchAllRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		writeProblem(ctx, http.StatusBadRequest, err)
+		return
+	}
+	if req.Limit == 0 {
+		req.Limit = defaultSearchLimit
+	}
+
+	hits, err := server.store.SearchAll(ctx, db.SearchAllParams{Query: req.Query, Limit: req.Limit})
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, searchAllResponse{Hits: hits})
+}
@@ -0,0 +1,189 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/lushenle/plam/pkg/db"
+	mockdb "github.com/lushenle/plam/pkg/db/mock"
+	"github.com/lushenle/plam/pkg/token"
+	"github.com/lushenle/plam/pkg/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchAllAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	project := randomProject(t)
+	income := randomIncome(t, project)
+
+	hits := []db.SearchAllRow{
+		{
+			Resource:  "project",
+			ID:        project.ID,
+			Title:     project.Name,
+			Snippet:   "<mark>" + project.Name + "</mark> " + project.Description,
+			Amount:    project.Amount,
+			CreatedAt: project.CreatedAt,
+			Rank:      1.5,
+		},
+		{
+			Resource:  "income",
+			ID:        income.ID,
+			Title:     income.Payee,
+			Snippet:   income.Payee,
+			Amount:    income.Amount,
+			CreatedAt: income.CreatedAt,
+			Rank:      0.7,
+		},
+	}
+
+	testCases := []struct {
+		name          string
+		query         url.Values
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "OK",
+			query: url.Values{"q": {project.Name}},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, util.RoleUser, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.SearchAllParams{Query: project.Name, Limit: defaultSearchLimit}
+				store.EXPECT().SearchAll(gomock.Any(), gomock.Eq(arg)).Times(1).Return(hits, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchHits(t, recorder.Body, hits)
+			},
+		},
+		{
+			name:  "Limit",
+			query: url.Values{"q": {project.Name}, "limit": {"1"}},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, util.RoleUser, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.SearchAllParams{Query: project.Name, Limit: 1}
+				store.EXPECT().SearchAll(gomock.Any(), gomock.Eq(arg)).Times(1).Return(hits[:1], nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchHits(t, recorder.Body, hits[:1])
+			},
+		},
+		{
+			name:  "NoHits",
+			query: url.Values{"q": {"nothing"}},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, util.RoleUser, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().SearchAll(gomock.Any(), gomock.Any()).Times(1).Return([]db.SearchAllRow{}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.JSONEq(t, `{"hits": []}`, recorder.Body.String())
+			},
+		},
+		{
+			name:      "NoAuthorization",
+			query:     url.Values{"q": {project.Name}},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().SearchAll(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:  "MissingQuery",
+			query: url.Values{},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, util.RoleUser, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().SearchAll(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "InvalidLimit",
+			query: url.Values{"q": {project.Name}, "limit": {"1000"}},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, util.RoleUser, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().SearchAll(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "InternalError",
+			query: url.Values{"q": {project.Name}},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, util.RoleUser, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().SearchAll(gomock.Any(), gomock.Any()).Times(1).Return(nil, sql.ErrConnDone)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/v1/search?"+tc.query.Encode(), nil)
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func requireBodyMatchHits(t *testing.T, body *bytes.Buffer, hits []db.SearchAllRow) {
+	data, err := io.ReadAll(body)
+	require.NoError(t, err)
+
+	var got searchAllResponse
+	err = json.Unmarshal(data, &got)
+	require.NoError(t, err)
+	require.Len(t, got.Hits, len(hits))
+	for i, hit := range hits {
+		require.Equal(t, hit.Resource, got.Hits[i].Resource)
+		require.Equal(t, hit.ID, got.Hits[i].ID)
+		require.Equal(t, hit.Title, got.Hits[i].Title)
+		require.Equal(t, hit.Snippet, got.Hits[i].Snippet)
+		require.Equal(t, hit.Rank, got.Hits[i].Rank)
+		require.WithinDuration(t, hit.CreatedAt, got.Hits[i].CreatedAt, time.Second)
+	}
+}
@@ -1,7 +1,13 @@
 package api
 
 import (
+	"context"
+	"errors"
+	"net"
 	"net/http"
+	"net/url"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gin-contrib/gzip"
@@ -9,14 +15,21 @@ import (
 	"github.com/gin-gonic/gin"
 	_ "github.com/lushenle/plam/docs"
 	"github.com/lushenle/plam/pkg/db"
+	"github.com/lushenle/plam/pkg/log"
+	"github.com/lushenle/plam/pkg/sso"
 	"github.com/lushenle/plam/pkg/token"
 	"github.com/lushenle/plam/pkg/util"
 	"github.com/penglongli/gin-metrics/ginmetrics"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 )
 
+// metricsPath serves the Prometheus metrics, its requests are not traced
+const metricsPath = "/metrics"
+
 // Server serves HTTP requests for Paramount Construction Machinery System
 type Server struct {
 	router     *gin.Engine
@@ -24,24 +37,59 @@ type Server struct {
 	store      db.Store
 	tokenMaker token.Maker
 	logger     *zap.Logger
+
+	passwordChecker *util.PasswordChecker
+	oidcProvider    *sso.Provider
+	healthChecks    []namedHealthCheck
+
+	// Settings which can be changed at runtime by Reload
+	accessTokenDuration atomic.Int64
+	corsOrigins         atomic.Pointer[[]string]
+	rateLimiter         *ipRateLimiter
+
+	// mu guards httpServer and redirectServer, shuttingDown is set as soon as Shutdown is called
+	mu             sync.Mutex
+	httpServer     *http.Server
+	redirectServer *http.Server
+	shuttingDown   atomic.Bool
 }
 
 type ServerOption func(server *Server)
 
 func NewServer(config util.Config, opts ...ServerOption) *Server {
 	server := &Server{
-		config: config,
+		config:      config,
+		rateLimiter: newIPRateLimiter(config.Server.RateLimit),
 	}
+	server.healthChecks = server.defaultHealthChecks()
+	server.Reload(config)
 
 	for _, opt := range opts {
 		opt(server)
 	}
 
+	if server.passwordChecker == nil {
+		server.passwordChecker = util.NewPasswordChecker(config.Password, nil)
+	}
+
 	server.setupRouter()
 
 	return server
 }
 
+// Reload applies the settings of config which can change at runtime, see util.ReloadableKeys
+func (server *Server) Reload(config util.Config) {
+	server.accessTokenDuration.Store(int64(config.Server.AccessTokenDuration))
+	origins := config.Server.CORS.AllowedOrigins
+	server.corsOrigins.Store(&origins)
+	server.rateLimiter.SetLimit(config.Server.RateLimit)
+}
+
+// tokenDuration returns the duration of newly created access tokens
+func (server *Server) tokenDuration() time.Duration {
+	return time.Duration(server.accessTokenDuration.Load())
+}
+
 func WithStore(store db.Store) ServerOption {
 	return func(server *Server) {
 		server.store = store
@@ -60,26 +108,71 @@ func WithLogger(logger *zap.Logger) ServerOption {
 	}
 }
 
+func WithPasswordChecker(checker *util.PasswordChecker) ServerOption {
+	return func(server *Server) {
+		server.passwordChecker = checker
+	}
+}
+
+func WithOIDCProvider(provider *sso.Provider) ServerOption {
+	return func(server *Server) {
+		server.oidcProvider = provider
+	}
+}
+
 func (server *Server) setupRouter() {
 	router := gin.New()
+	// Use the request context, which carries the handler timeout, when a handler
+	// passes the gin context to the store
+	router.ContextWithFallback = true
+	// Only trusted proxies may set the client IP, which keys the rate limit, with X-Forwarded-For
+	if err := router.SetTrustedProxies(server.config.Server.TrustedProxies); err != nil {
+		server.logger.Error("ignoring invalid trusted proxies", zap.String("server", err.Error()))
+		_ = router.SetTrustedProxies(nil)
+	}
+	router.NoRoute(func(ctx *gin.Context) {
+		writeProblem(ctx, http.StatusNotFound, errors.New("route not found"))
+	})
+	useRequestFieldNames()
+	useTranslations()
+
+	// Start a server span for every request, the spans of the database queries are its children
+	router.Use(otelgin.Middleware(server.config.Tracing.ServiceName, otelgin.WithFilter(func(request *http.Request) bool {
+		return request.URL.Path != metricsPath
+	})))
+
+	router.Use(requestIDMiddleware(server.logger))
+
 	// Add a ginzap middleware, which:
 	//   - Logs all requests, like a combined access and error log
 	//   - Logs to stdout
 	//   - RFC3339 with UTC time format
-	router.Use(ginzap.Ginzap(server.logger, time.RFC3339, true))
-
-	// Logs all panic to error log
-	//   - stack means whether output the stack info
-	router.Use(ginzap.RecoveryWithZap(server.logger, true))
+	//   - Adds the request ID and the trace ID, which the log lines of handlers carry as well
+	router.Use(ginzap.GinzapWithConfig(server.logger, &ginzap.Config{
+		TimeFormat: time.RFC3339,
+		UTC:        true,
+		Context: func(ctx *gin.Context) []zapcore.Field {
+			return log.Fields(ctx.Request.Context())
+		},
+	}))
+
+	// Log the panics of handlers with their stack and the request ID, and respond with a problem
+	router.Use(recoveryMiddleware())
 
 	router.Use(gzip.Gzip(gzip.BestSpeed))
 
+	router.Use(server.corsMiddleware())
+
+	router.Use(bodyLimitMiddleware(server.config.Server.MaxBodyBytes))
+	router.Use(timeoutMiddleware(server.config.Server.HandlerTimeout))
+	router.Use(readConsistencyMiddleware())
+
 	// Create a Prometheus instance
 	// get global Monitor object
 	m := ginmetrics.GetMonitor()
 
 	// +optional set metric path, default /debug/metrics
-	m.SetMetricPath("/metrics")
+	m.SetMetricPath(metricsPath)
 	// +optional set slow time, default 5s
 	m.SetSlowTime(10)
 	// +optional set request duration, default {0.1, 0.3, 1.2, 5, 10}
@@ -99,38 +192,66 @@ func (server *Server) setupRouter() {
 
 	{
 		apiV1.GET("/healthz", server.healthz)
+		apiV1.GET("/livez", server.livez)
+		apiV1.GET("/readyz", server.readyz)
+	}
+
+	// Probes are registered above so that they are not rate limited
+	apiV1.Use(rateLimitMiddleware(server.rateLimiter))
+
+	{
 		apiV1.POST("/users/signup", server.signupUser)
 		apiV1.POST("/users/login", server.loginUser)
 	}
 
+	if server.oidcProvider != nil {
+		apiV1.GET("/auth/oidc/login", server.oidcLogin)
+		apiV1.GET("/auth/oidc/callback", server.oidcCallback)
+	}
+
 	authRoutes := apiV1.Group("/").Use(authMiddleware(server.tokenMaker))
 
+	// users router
+	{
+		authRoutes.POST("/users/password", server.changePassword)
+		authRoutes.POST("/users/locale", server.updateLocale)
+	}
+
 	// projects router
 	{
-		authRoutes.POST("/projects/all", server.listProjects)
+		authRoutes.GET("/projects", server.listProjects)
 		authRoutes.GET("/projects/:id", server.getProject)
-		authRoutes.POST("/projects/search", server.searchProjects)
+		authRoutes.POST("/projects/all", deprecationMiddleware(listRoutesDeprecatedAt, "/v1/projects"), server.listProjectsPost)
+		authRoutes.POST("/projects/search", deprecationMiddleware(listRoutesDeprecatedAt, "/v1/projects"), server.searchProjects)
 	}
 
 	// incomes router
 	{
-		authRoutes.POST("/incomes/all", server.listIncomes)
+		authRoutes.GET("/incomes", server.listIncomes)
 		authRoutes.GET("/incomes/:id", server.getIncome)
-		authRoutes.POST("/incomes/search", server.searchIncomes)
+		authRoutes.POST("/incomes/all", deprecationMiddleware(listRoutesDeprecatedAt, "/v1/incomes"), server.listIncomesPost)
+		authRoutes.POST("/incomes/search", deprecationMiddleware(listRoutesDeprecatedAt, "/v1/incomes"), server.searchIncomes)
 	}
 
 	// loans router
 	{
-		authRoutes.POST("/loans/all", server.listLoans)
+		authRoutes.GET("/loans", server.listLoans)
 		authRoutes.GET("/loans/:id", server.getLoan)
-		authRoutes.POST("/loans/search", server.searchLoans)
+		authRoutes.POST("/loans/all", deprecationMiddleware(listRoutesDeprecatedAt, "/v1/loans"), server.listLoansPost)
+		authRoutes.POST("/loans/search", deprecationMiddleware(listRoutesDeprecatedAt, "/v1/loans"), server.searchLoans)
 	}
 
 	// pay_outs router
 	{
-		authRoutes.POST("/pay_outs/all", server.listPayOuts)
+		authRoutes.GET("/pay_outs", server.listPayOuts)
 		authRoutes.GET("/pay_outs/:id", server.getPayOut)
-		authRoutes.POST("/pay_outs/search", server.searchPayOuts)
+		authRoutes.POST("/pay_outs/all", deprecationMiddleware(listRoutesDeprecatedAt, "/v1/pay_outs"), server.listPayOutsPost)
+		authRoutes.POST("/pay_outs/search", deprecationMiddleware(listRoutesDeprecatedAt, "/v1/pay_outs"), server.searchPayOuts)
+	}
+
+	// search router
+	{
+		authRoutes.GET("/search", server.searchAll)
 	}
 
 	authRoutes.Use(rbacMiddleware())
@@ -147,37 +268,141 @@ func (server *Server) setupRouter() {
 
 		authRoutes.POST("/pay_outs", server.createPayOut)
 		authRoutes.DELETE("/pay_outs/:id", server.deletePayOut)
+
+		authRoutes.POST("/invitations", server.createInvitation)
 	}
 
 	server.router = router
 }
 
-// Start runs the HTTP server in a specific address
+// Start runs the HTTP server in a specific address, it returns nil once Shutdown is called
 func (server *Server) Start(addr string) error {
-	// Set the connection timeout
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return server.Serve(listener)
+}
+
+// Serve accepts connections on listener, serving HTTPS when TLS is enabled,
+// it returns nil once Shutdown is called
+func (server *Server) Serve(listener net.Listener) error {
+	// Set the connection timeouts and limits
 	srv := &http.Server{
-		Addr:         addr,
-		Handler:      server.router,
-		ReadTimeout:  360 * time.Second, // Maximum duration for reading the entire request
-		WriteTimeout: 360 * time.Second, // Maximum duration before timing out writes of the response
-		IdleTimeout:  720 * time.Second, // Maximum amount of time to wait for the next request when keep-alives are enabled
+		Handler:           server.router,
+		ReadTimeout:       server.config.Server.ReadTimeout,       // Maximum duration for reading the entire request
+		ReadHeaderTimeout: server.config.Server.ReadHeaderTimeout, // Maximum duration for reading the request headers
+		WriteTimeout:      server.config.Server.WriteTimeout,      // Maximum duration before timing out writes of the response
+		IdleTimeout:       server.config.Server.IdleTimeout,       // Maximum amount of time to wait for the next request when keep-alives are enabled
+		MaxHeaderBytes:    server.config.Server.MaxHeaderBytes,
+	}
+
+	var redirect *http.Server
+	var redirectListener net.Listener
+	tlsConfig := server.config.Server.TLS
+	if tlsConfig.Enabled {
+		var err error
+		if srv.TLSConfig, err = util.NewTLSConfig(tlsConfig); err != nil {
+			listener.Close()
+			return err
+		}
+
+		if tlsConfig.RedirectAddress != "" {
+			if redirectListener, err = net.Listen("tcp", tlsConfig.RedirectAddress); err != nil {
+				listener.Close()
+				return err
+			}
+			redirect = &http.Server{
+				Handler:           httpsRedirectHandler(listener.Addr().String()),
+				ReadHeaderTimeout: 10 * time.Second,
+			}
+		}
+	}
+
+	server.mu.Lock()
+	if server.shuttingDown.Load() {
+		server.mu.Unlock()
+		listener.Close()
+		if redirectListener != nil {
+			redirectListener.Close()
+		}
+		return nil
+	}
+	server.httpServer = srv
+	server.redirectServer = redirect
+	server.mu.Unlock()
+
+	if redirect != nil {
+		go func() {
+			if err := redirect.Serve(redirectListener); !errors.Is(err, http.ErrServerClosed) {
+				server.logger.Error("https redirect listener failed", zap.String("server", err.Error()))
+			}
+		}()
 	}
 
 	// Run the server
-	return srv.ListenAndServe()
+	var err error
+	if srv.TLSConfig != nil {
+		// The certificate is provided by TLSConfig.GetCertificate
+		err = srv.ServeTLS(listener, "", "")
+	} else {
+		err = srv.Serve(listener)
+	}
+	if !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
 }
 
-func (server *Server) healthz(ctx *gin.Context) {
-	ctx.String(http.StatusOK, "ok")
+// Shutdown marks the server as not ready, keeps serving for the shutdown delay so that
+// readiness probes see it, then stops accepting new connections and waits for in-flight
+// requests to finish until ctx is done
+func (server *Server) Shutdown(ctx context.Context) error {
+	alreadyShuttingDown := server.shuttingDown.Swap(true)
+
+	server.mu.Lock()
+	srv, redirect := server.httpServer, server.redirectServer
+	server.mu.Unlock()
+
+	if delay := server.config.Server.ShutdownDelay; srv != nil && delay > 0 && !alreadyShuttingDown {
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+		}
+	}
+
+	if redirect != nil {
+		if err := redirect.Shutdown(ctx); err != nil {
+			return err
+		}
+	}
+	if srv == nil {
+		return nil
+	}
+	return srv.Shutdown(ctx)
 }
 
-// errorResponse is a struct that represents an error response.
-//
-// @swagger:model
-type errorResponse struct {
-	Error string `json:"error"`
+// httpsRedirectHandler redirects plain HTTP requests to the HTTPS listener at tlsAddr
+func httpsRedirectHandler(tlsAddr string) http.Handler {
+	_, port, _ := net.SplitHostPort(tlsAddr)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := r.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		if port != "" && port != "443" {
+			host = net.JoinHostPort(host, port)
+		}
+
+		target := url.URL{Scheme: "https", Host: host, Path: r.URL.Path, RawQuery: r.URL.RawQuery}
+		// 308 keeps the method and body of the request
+		http.Redirect(w, r, target.String(), http.StatusPermanentRedirect)
+	})
 }
 
-func errResponse(err error) gin.H {
-	return gin.H{"error": err.Error()}
+func (server *Server) healthz(ctx *gin.Context) {
+	ctx.String(http.StatusOK, "ok")
 }
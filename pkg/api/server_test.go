@@ -0,0 +1,274 @@
+package api
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/lushenle/plam/pkg/db"
+	mockdb "github.com/lushenle/plam/pkg/db/mock"
+	"github.com/lushenle/plam/pkg/util"
+	"github.com/lushenle/plam/pkg/util/tlstest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStartShutdown(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	server := newTestServer(t, mockdb.NewMockStore(ctrl))
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- server.Start("127.0.0.1:0")
+	}()
+
+	// Wait until Start has registered the http server
+	require.Eventually(t, func() bool {
+		server.mu.Lock()
+		defer server.mu.Unlock()
+		return server.httpServer != nil
+	}, time.Second, 10*time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	require.NoError(t, server.Shutdown(ctx))
+
+	select {
+	case err := <-errCh:
+		require.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("Start did not return after Shutdown")
+	}
+}
+
+func TestShutdownDelay(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	const delay = 300 * time.Millisecond
+	server := newTestServerWithConfig(t, mockdb.NewMockStore(ctrl), func(config *util.Config) {
+		config.Server.ShutdownDelay = delay
+	})
+	addr := serveTestServer(t, server)
+
+	start := time.Now()
+	shutdownCh := make(chan error, 1)
+	go func() {
+		shutdownCh <- server.Shutdown(context.Background())
+	}()
+
+	// Readiness probes see the failing /readyz while the listener is still open
+	require.Eventually(t, func() bool {
+		rsp, err := http.Get("http://" + addr + "/v1/readyz")
+		if err != nil {
+			return false
+		}
+		defer rsp.Body.Close()
+		return rsp.StatusCode == http.StatusServiceUnavailable
+	}, delay, 10*time.Millisecond)
+
+	require.NoError(t, <-shutdownCh)
+	require.GreaterOrEqual(t, time.Since(start), delay)
+
+	_, err := http.Get("http://" + addr + "/v1/readyz")
+	require.Error(t, err)
+}
+
+func TestShutdownDelayCanceled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	server := newTestServerWithConfig(t, mockdb.NewMockStore(ctrl), func(config *util.Config) {
+		config.Server.ShutdownDelay = time.Minute
+	})
+	serveTestServer(t, server)
+
+	// The delay ends with the shutdown context, and is not repeated by later calls
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	require.NoError(t, server.Shutdown(ctx))
+	require.NoError(t, server.Shutdown(context.Background()))
+	require.Less(t, time.Since(start), time.Second)
+}
+
+func TestShutdownBeforeStart(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	server := newTestServer(t, mockdb.NewMockStore(ctrl))
+
+	require.NoError(t, server.Shutdown(context.Background()))
+	require.NoError(t, server.Start("127.0.0.1:0"))
+}
+
+func TestReloadTokenDuration(t *testing.T) {
+	server := newTestServer(t, nil)
+	require.Equal(t, server.config.Server.AccessTokenDuration, server.tokenDuration())
+
+	config := server.config
+	config.Server.AccessTokenDuration = time.Hour
+	server.Reload(config)
+	require.Equal(t, time.Hour, server.tokenDuration())
+}
+
+func newTLSTestServer(t *testing.T, tlsConfig util.TLS) *Server {
+	return newTestServerWithConfig(t, nil, func(config *util.Config) {
+		config.Server.TLS = tlsConfig
+	})
+}
+
+func serveTestServer(t *testing.T, server *Server) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- server.Serve(listener)
+	}()
+	t.Cleanup(func() {
+		require.NoError(t, server.Shutdown(context.Background()))
+		require.NoError(t, <-errCh)
+	})
+
+	// Wait until Serve has registered the http server, so that Shutdown stops it
+	require.Eventually(t, func() bool {
+		server.mu.Lock()
+		defer server.mu.Unlock()
+		return server.httpServer != nil
+	}, time.Second, 10*time.Millisecond)
+
+	return listener.Addr().String()
+}
+
+func TestServeTLS(t *testing.T) {
+	dir := t.TempDir()
+	ca := tlstest.NewCA(t)
+	certFile, keyFile := ca.IssueFiles(t, dir, "localhost")
+
+	server := newTLSTestServer(t, util.TLS{Enabled: true, CertFile: certFile, KeyFile: keyFile, MinVersion: "1.3"})
+	addr := serveTestServer(t, server)
+
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: ca.Pool}}}
+	rsp, err := client.Get("https://" + addr + "/v1/livez")
+	require.NoError(t, err)
+	defer rsp.Body.Close()
+	require.Equal(t, http.StatusOK, rsp.StatusCode)
+	require.Equal(t, uint16(tls.VersionTLS13), rsp.TLS.Version)
+
+	// Clients limited to TLS 1.2 are rejected
+	client = &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: ca.Pool, MaxVersion: tls.VersionTLS12}}}
+	_, err = client.Get("https://" + addr + "/v1/livez")
+	require.Error(t, err)
+}
+
+func TestServeMutualTLS(t *testing.T) {
+	dir := t.TempDir()
+	ca := tlstest.NewCA(t)
+	certFile, keyFile := ca.IssueFiles(t, dir, "localhost")
+
+	server := newTLSTestServer(t, util.TLS{
+		Enabled:      true,
+		CertFile:     certFile,
+		KeyFile:      keyFile,
+		ClientCAFile: ca.WriteCAFile(t, dir),
+	})
+	addr := serveTestServer(t, server)
+
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{
+		RootCAs:      ca.Pool,
+		Certificates: []tls.Certificate{ca.ClientCertificate(t, "internal")},
+	}}}
+	rsp, err := client.Get("https://" + addr + "/v1/livez")
+	require.NoError(t, err)
+	defer rsp.Body.Close()
+	require.Equal(t, http.StatusOK, rsp.StatusCode)
+
+	// Clients without a certificate, or with one from another CA, are rejected
+	client = &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: ca.Pool}}}
+	_, err = client.Get("https://" + addr + "/v1/livez")
+	require.Error(t, err)
+
+	client = &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{
+		RootCAs:      ca.Pool,
+		Certificates: []tls.Certificate{tlstest.NewCA(t).ClientCertificate(t, "internal")},
+	}}}
+	_, err = client.Get("https://" + addr + "/v1/livez")
+	require.Error(t, err)
+}
+
+func TestServeInvalidTLS(t *testing.T) {
+	server := newTLSTestServer(t, util.TLS{Enabled: true, CertFile: "missing.pem", KeyFile: "missing.pem"})
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	require.Error(t, server.Serve(listener))
+}
+
+func TestHTTPSRedirectHandler(t *testing.T) {
+	testCases := []struct {
+		name    string
+		tlsAddr string
+		target  string
+		want    string
+	}{
+		{
+			name:    "DefaultPort",
+			tlsAddr: "[::]:443",
+			target:  "http://plam.example.com/v1/projects/1?a=b",
+			want:    "https://plam.example.com/v1/projects/1?a=b",
+		},
+		{
+			name:    "CustomPort",
+			tlsAddr: ":8443",
+			target:  "http://plam.example.com:8080/v1/livez",
+			want:    "https://plam.example.com:8443/v1/livez",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodPost, tc.target, nil)
+
+			httpsRedirectHandler(tc.tlsAddr).ServeHTTP(recorder, request)
+			require.Equal(t, http.StatusPermanentRedirect, recorder.Code)
+			require.Equal(t, tc.want, recorder.Header().Get("Location"))
+		})
+	}
+}
+
+func TestHandlerTimeoutReachesStore(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	project := randomProject(t)
+	user, _ := randomUser(t)
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetProject(gomock.Any(), gomock.Eq(project.ID)).Times(1).
+		DoAndReturn(func(ctx context.Context, _ any) (db.Project, error) {
+			deadline, ok := ctx.Deadline()
+			require.True(t, ok)
+			require.WithinDuration(t, time.Now().Add(time.Minute), deadline, time.Second)
+			return project, nil
+		})
+
+	server := newTestServerWithConfig(t, store, func(config *util.Config) {
+		config.Server.HandlerTimeout = time.Minute
+	})
+
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodGet, "/v1/projects/"+project.ID.String(), nil)
+	require.NoError(t, err)
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, util.RoleUser, time.Minute)
+
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+}
@@ -8,9 +8,15 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/lushenle/plam/pkg/db"
+	"github.com/lushenle/plam/pkg/token"
 	"github.com/lushenle/plam/pkg/util"
 )
 
+var (
+	errInvalidCredentials = errors.New("invalid username or password")
+	errIncorrectPassword  = errors.New("password is incorrect")
+)
+
 // createUserRequest is a struct that represents the request to create a user.
 //
 //	@swagger:model
@@ -23,13 +29,11 @@ type createUserRequest struct {
 	// maxLength: 255
 	Username string `json:"username" binding:"required,alphanum"`
 
-	// Password of the user.
+	// Password of the user, checked against the configured password policy.
 	// Required: true
 	// example: password123
 	// in: body
-	// minLength: 6
-	// maxLength: 32
-	Password string `json:"password" binding:"required,min=6,max=32"`
+	Password string `json:"password" binding:"required"`
 
 	// Full name of the user.
 	// Required: true
@@ -45,6 +49,11 @@ type createUserRequest struct {
 	// in: body
 	// format: email
 	Email string `json:"email" binding:"required,email"`
+
+	// Invitation code, required when signup is invite-only.
+	// example: 0e2gSXGEnh1hUj1V9vbqPMlwmW9oSU0f
+	// in: body
+	InvitationCode string `json:"invitation_code"`
 }
 
 // userResponse represents the response structure for user data.
@@ -74,6 +83,10 @@ type userResponse struct {
 	// UpdatedAt represents the timestamp when the user was last updated.
 	// swagger:strfmt date-time
 	UpdatedAt time.Time `json:"updated_at"`
+
+	// Locale is the preferred locale of the user, empty to follow the Accept-Language header.
+	// example: zh
+	Locale string `json:"locale"`
 }
 
 // signupUser creates a new user.
@@ -92,30 +105,53 @@ type userResponse struct {
 func (server *Server) signupUser(ctx *gin.Context) {
 	var req createUserRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errResponse(err))
+		writeProblem(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	switch server.config.Signup.Mode {
+	case util.SignupModeDisabled:
+		writeProblem(ctx, http.StatusForbidden, errors.New("signup is disabled"))
+		return
+	case util.SignupModeInvite:
+		if req.InvitationCode == "" {
+			writeProblem(ctx, http.StatusForbidden, errors.New("signup requires an invitation code"))
+			return
+		}
+	}
+
+	if err := server.passwordChecker.Check(req.Password, req.Username, req.Email); err != nil {
+		writeProblem(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	hashedPassword, err := util.HashPassword(req.Password)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errResponse(err))
+		writeError(ctx, err)
 		return
 	}
 
 	arg := db.CreateUserParams{
 		Username:       req.Username,
+		Role:           util.RoleUser,
 		HashedPassword: hashedPassword,
 		FullName:       req.FullName,
 		Email:          req.Email,
 	}
 
-	user, err := server.store.CreateUser(ctx, arg)
+	var user db.User
+	if req.InvitationCode != "" {
+		var result db.CreateUserWithInvitationTxResult
+		result, err = server.store.CreateUserWithInvitationTx(ctx, db.CreateUserWithInvitationTxParams{
+			CreateUserParams: arg,
+			InvitationCode:   req.InvitationCode,
+		})
+		user = result.User
+	} else {
+		user, err = server.store.CreateUser(ctx, arg)
+	}
 	if err != nil {
-		if errors.Is(err, db.ErrUniqueViolation) {
-			ctx.JSON(http.StatusForbidden, errResponse(err))
-			return
-		}
-		ctx.JSON(http.StatusInternalServerError, errResponse(err))
+		writeError(ctx, err)
 		return
 	}
 
@@ -130,6 +166,7 @@ func newUserResponse(user db.User) userResponse {
 		Email:             user.Email,
 		PasswordChangedAt: user.PasswordChangedAt,
 		CreatedAt:         user.CreatedAt,
+		Locale:            user.Locale,
 	}
 }
 
@@ -147,9 +184,8 @@ type loginUserRequest struct {
 	// Required: true
 	// example: password123
 	// in: body
-	// minLength: 6
-	// maxLength: 32
-	Password string `json:"password" binding:"required,min=6,max=32"`
+	// maxLength: 72
+	Password string `json:"password" binding:"required,max=72"`
 }
 
 // loginUserResponse represents the response structure for user login.
@@ -184,35 +220,43 @@ type loginUserResponse struct {
 //	@Success		200		{object}	loginUserResponse	"User login response"
 //	@Failure		400		{object}	errorResponse		"Bad request"
 //	@Failure		401		{object}	errorResponse		"Unauthorized"
+//	@Failure		403		{object}	errorResponse		"Forbidden"
 //	@Failure		404		{object}	errorResponse		"Not found"
 //	@Failure		500		{object}	errorResponse		"Internal server error"
 //	@Router			/users/login [post]
 func (server *Server) loginUser(ctx *gin.Context) {
+	if server.config.OIDC.DisablePasswordLogin {
+		writeProblem(ctx, http.StatusForbidden, errors.New("password login is disabled, use single sign-on"))
+		return
+	}
+
 	var req loginUserRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errResponse(err))
+		writeProblem(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	user, err := server.store.GetUser(ctx, req.Username)
+	// A user who just signed up may not be on the read replica yet
+	user, err := server.store.GetUser(db.WithPrimary(ctx), req.Username)
 	if err != nil {
 		if errors.Is(err, db.ErrRecordNotFound) {
-			ctx.JSON(http.StatusNotFound, errResponse(err))
-			return
+			recordLogin(loginMethodPassword, false)
 		}
-		ctx.JSON(http.StatusInternalServerError, errResponse(err))
+		writeError(ctx, err)
 		return
 	}
 
 	err = util.CheckPassword(req.Password, user.HashedPassword)
 	if err != nil {
-		ctx.JSON(http.StatusUnauthorized, errResponse(err))
+		recordLogin(loginMethodPassword, false)
+		writeProblem(ctx, http.StatusUnauthorized, errInvalidCredentials)
 		return
 	}
 
-	accessToken, accessPayload, err := server.tokenMaker.CreateToken(user.Username, user.Role, server.config.Server.AccessTokenDuration)
+	accessToken, accessPayload, err := server.tokenMaker.CreateToken(user.Username, user.Role, server.tokenDuration(),
+		token.WithLocale(user.Locale))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errResponse(err))
+		writeError(ctx, err)
 		return
 	}
 
@@ -223,5 +267,163 @@ func (server *Server) loginUser(ctx *gin.Context) {
 		User:                 newUserResponse(user),
 	}
 
+	recordLogin(loginMethodPassword, true)
 	ctx.JSON(http.StatusOK, rsp)
 }
+
+// changePasswordRequest represents the request structure for changing the password.
+//
+//	@swagger:model
+type changePasswordRequest struct {
+	// Current password of the user.
+	// Required: true
+	// example: password123
+	// in: body
+	OldPassword string `json:"old_password" binding:"required,max=72"`
+
+	// New password of the user, checked against the configured password policy.
+	// Required: true
+	// example: password456
+	// in: body
+	NewPassword string `json:"new_password" binding:"required"`
+}
+
+// changePassword changes the password of the logged-in user.
+//
+//	@Summary		Changes the password of the logged-in user.
+//	@Description	Changes the password of the logged-in user. The new password must satisfy the password policy and must not match any recently used password.
+//	@Tags			users
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		changePasswordRequest	true	"Change password request"
+//	@Success		200		{object}	userResponse			"User response"
+//	@Failure		400		{object}	errorResponse			"Bad request"
+//	@Failure		401		{object}	errorResponse			"Unauthorized"
+//	@Failure		404		{object}	errorResponse			"Not found"
+//	@Failure		500		{object}	errorResponse			"Internal server error"
+//	@Router			/users/password [post]
+//	@security		ApiKeyAuth
+func (server *Server) changePassword(ctx *gin.Context) {
+	var req changePasswordRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		writeProblem(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	// The password must be checked against the latest hash
+	user, err := server.store.GetUser(db.WithPrimary(ctx), authPayload.Username)
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	err = util.CheckPassword(req.OldPassword, user.HashedPassword)
+	if err != nil {
+		writeProblem(ctx, http.StatusUnauthorized, errIncorrectPassword)
+		return
+	}
+
+	if err = server.passwordChecker.Check(req.NewPassword, user.Username, user.Email); err != nil {
+		writeProblem(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	if historySize := server.passwordChecker.HistorySize(); historySize > 0 {
+		// The current password counts as the most recent one
+		history, err := server.store.ListPasswordHistory(db.WithPrimary(ctx), db.ListPasswordHistoryParams{
+			Username: user.Username,
+			Limit:    int32(historySize - 1),
+		})
+		if err != nil {
+			writeError(ctx, err)
+			return
+		}
+
+		hashedPasswords := []string{user.HashedPassword}
+		for _, entry := range history {
+			hashedPasswords = append(hashedPasswords, entry.HashedPassword)
+		}
+		if err = server.passwordChecker.CheckHistory(req.NewPassword, hashedPasswords); err != nil {
+			writeProblem(ctx, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	hashedPassword, err := util.HashPassword(req.NewPassword)
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	result, err := server.store.ChangePasswordTx(ctx, db.ChangePasswordTxParams{
+		Username:          user.Username,
+		OldHashedPassword: user.HashedPassword,
+		NewHashedPassword: hashedPassword,
+	})
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newUserResponse(result.User))
+}
+
+// updateLocaleRequest represents the request structure for setting the preferred locale.
+//
+//	@swagger:model
+type updateLocaleRequest struct {
+	// Locale is the preferred locale of error messages, empty to follow the Accept-Language header.
+	// example: zh
+	// in: body
+	Locale string `json:"locale" binding:"omitempty,oneof=en zh"`
+}
+
+// updateLocale sets the preferred locale of the logged-in user.
+//
+//	@Summary		Sets the preferred locale of the logged-in user.
+//	@Description	Sets the locale of the error messages sent to the logged-in user, it takes precedence over the Accept-Language header.
+//	@Description	The locale is carried by the access tokens, the response holds a new access token, expiring with the current one, which carries it.
+//	@Tags			users
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		updateLocaleRequest	true	"Update locale request"
+//	@Success		200		{object}	loginUserResponse	"User and access token response"
+//	@Failure		400		{object}	errorResponse		"Bad request"
+//	@Failure		401		{object}	errorResponse		"Unauthorized"
+//	@Failure		404		{object}	errorResponse		"Not found"
+//	@Failure		500		{object}	errorResponse		"Internal server error"
+//	@Router			/users/locale [post]
+//	@security		ApiKeyAuth
+func (server *Server) updateLocale(ctx *gin.Context) {
+	var req updateLocaleRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		writeProblem(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	user, err := server.store.UpdateUserLocale(ctx, db.UpdateUserLocaleParams{
+		Username: authPayload.Username,
+		Locale:   req.Locale,
+	})
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	// The current token keeps the previous locale, the new one does not outlive it
+	accessToken, accessPayload, err := server.tokenMaker.CreateToken(user.Username, user.Role, time.Until(authPayload.ExpiredAt),
+		token.WithLocale(user.Locale))
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, loginUserResponse{
+		AccessTokenID:        accessPayload.ID,
+		AccessToken:          accessToken,
+		AccessTokenExpiresAt: accessPayload.ExpiredAt,
+		User:                 newUserResponse(user),
+	})
+}
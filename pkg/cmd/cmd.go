@@ -0,0 +1,212 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/lushenle/plam/pkg/db"
+	"github.com/lushenle/plam/pkg/util"
+)
+
+// ErrUsage is returned when a command is called with invalid arguments
+var ErrUsage = errors.New("invalid usage")
+
+// command is a subcommand of the plam binary
+type command struct {
+	name    string
+	summary string
+	run     func(ctx context.Context, args []string) error
+}
+
+func commands() []command {
+	return []command{
+		{name: "serve", summary: "Run the HTTP server (default)", run: runServe},
+		{name: "migrate", summary: "Manage database schema migrations (up, down, goto, force, status, version)", run: runMigrate},
+		{name: "user", summary: "Manage users", run: runUser},
+		{name: "config", summary: "Check the configuration", run: runConfig},
+	}
+}
+
+// Execute runs the subcommand named by the first argument, defaulting to serve.
+// Long-running commands stop when ctx is cancelled.
+func Execute(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return runServe(ctx, nil)
+	}
+
+	switch args[0] {
+	case "-h", "-help", "--help", "help":
+		printUsage(os.Stdout)
+		return nil
+	}
+
+	for _, c := range commands() {
+		if c.name == args[0] {
+			return c.run(ctx, args[1:])
+		}
+	}
+
+	printUsage(os.Stderr)
+	return fmt.Errorf("%w: unknown command %q", ErrUsage, args[0])
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: plam <command> [flags]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, c := range commands() {
+		fmt.Fprintf(w, "  %-10s %s\n", c.name, c.summary)
+	}
+}
+
+// newFlagSet creates a flag set which returns parse errors instead of exiting
+func newFlagSet(name string) *flag.FlagSet {
+	flags := flag.NewFlagSet("plam "+name, flag.ContinueOnError)
+	flags.SetOutput(os.Stderr)
+	return flags
+}
+
+// parseInterspersed parses flags placed anywhere among the arguments, e.g. "down 1 --dry-run",
+// and returns the positional arguments. Everything after "--" is positional.
+func parseInterspersed(flags *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := flags.Parse(args); err != nil {
+			return nil, err
+		}
+		if consumed := len(args) - flags.NArg(); consumed > 0 && args[consumed-1] == "--" {
+			return append(positional, flags.Args()...), nil
+		}
+		if flags.NArg() == 0 {
+			return positional, nil
+		}
+		positional = append(positional, flags.Arg(0))
+		args = flags.Args()[1:]
+	}
+}
+
+// configFlags holds the flags shared by every command which loads the config
+type configFlags struct {
+	flags *flag.FlagSet
+	file  string
+}
+
+// configFlagKeys maps the override flags to the config keys they set
+var configFlagKeys = map[string]string{
+	"loglevel": "loglevel",
+	"logfile":  "logfile",
+	"address":  "server.serverAddress",
+	"dsn":      "database.dataSourceName",
+}
+
+// addConfigFlags registers --config and the config override flags on flags
+func addConfigFlags(flags *flag.FlagSet) *configFlags {
+	cf := &configFlags{flags: flags}
+	flags.StringVar(&cf.file, "config", os.Getenv("PLAM_CONFIG"), "path of the config file, defaults to ./config.yaml (env PLAM_CONFIG)")
+	flags.String("loglevel", "", "log level, overrides loglevel")
+	flags.String("logfile", "", "log file, overrides logfile")
+	flags.String("address", "", "listen address, overrides server.serverAddress")
+	flags.String("dsn", "", "database connection string, overrides database.dataSourceName")
+	return cf
+}
+
+// overrides returns the config keys of the override flags given on the command line
+func (cf *configFlags) overrides() map[string]any {
+	overrides := make(map[string]any)
+	cf.flags.Visit(func(f *flag.Flag) {
+		if key, ok := configFlagKeys[f.Name]; ok {
+			overrides[key] = f.Value.String()
+		}
+	})
+	return overrides
+}
+
+// loadConfig loads and validates the config
+func loadConfig(cf *configFlags) (util.Config, error) {
+	config, err := loadConfigUnchecked(cf)
+	if err != nil {
+		return config, err
+	}
+	if err = config.Validate(); err != nil {
+		return config, err
+	}
+	return config, nil
+}
+
+// options returns where the config is read from
+func (cf *configFlags) options() util.ConfigOptions {
+	return util.ConfigOptions{
+		Path:      ".",
+		File:      cf.file,
+		Overrides: cf.overrides(),
+	}
+}
+
+func loadConfigUnchecked(cf *configFlags) (util.Config, error) {
+	config, err := util.LoadConfigWithOptions(cf.options())
+	if err != nil {
+		return config, fmt.Errorf("cannot load config: %w", err)
+	}
+	return config, nil
+}
+
+// openStore connects to the database and returns the store and the underlying pool,
+// the pool must be closed by the caller
+func openStore(ctx context.Context, config util.Config) (db.Store, *pgxpool.Pool, error) {
+	conn, err := openPool(ctx, config.Database.DataSourceName, config.Database.Pool)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return db.NewStore(conn), conn, nil
+}
+
+// openPool creates a connection pool to dsn with the configured pool settings
+func openPool(ctx context.Context, dsn string, pool util.Pool) (*pgxpool.Pool, error) {
+	poolConfig, err := newPoolConfig(dsn, pool)
+	if err != nil {
+		return nil, fmt.Errorf("invalid database config: %w", err)
+	}
+
+	conn, err := pgxpool.NewWithConfig(ctx, poolConfig)
+	if err != nil {
+		return nil, fmt.Errorf("db connection failed: %w", err)
+	}
+	return conn, nil
+}
+
+// newPoolConfig parses dsn and applies the non-zero pool settings
+func newPoolConfig(dsn string, pool util.Pool) (*pgxpool.Config, error) {
+	poolConfig, err := pgxpool.ParseConfig(dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	if pool.MaxConns > 0 {
+		poolConfig.MaxConns = pool.MaxConns
+	}
+	if pool.MinConns > 0 {
+		poolConfig.MinConns = pool.MinConns
+	}
+	if pool.MaxConnLifetime > 0 {
+		poolConfig.MaxConnLifetime = pool.MaxConnLifetime
+	}
+	if pool.MaxConnIdleTime > 0 {
+		poolConfig.MaxConnIdleTime = pool.MaxConnIdleTime
+	}
+	if pool.HealthCheckPeriod > 0 {
+		poolConfig.HealthCheckPeriod = pool.HealthCheckPeriod
+	}
+	if pool.StatementTimeout > 0 {
+		poolConfig.ConnConfig.RuntimeParams["statement_timeout"] = strconv.FormatInt(pool.StatementTimeout.Milliseconds(), 10)
+	}
+	poolConfig.ConnConfig.Tracer = db.NewQueryTracer()
+
+	return poolConfig, nil
+}
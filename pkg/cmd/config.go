@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/lushenle/plam/pkg/util"
+	"gopkg.in/yaml.v3"
+)
+
+func runConfig(_ context.Context, args []string) error {
+	usage := fmt.Errorf("%w: expected 'plam config check'", ErrUsage)
+	if len(args) == 0 || args[0] != "check" {
+		return usage
+	}
+
+	flags := newFlagSet("config check")
+	cf := addConfigFlags(flags)
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	config, err := loadConfigUnchecked(cf)
+	if err != nil {
+		return err
+	}
+
+	return checkConfig(os.Stdout, os.Stderr, config)
+}
+
+// checkConfig prints the effective config with secrets redacted, followed by every validation problem
+func checkConfig(stdout, stderr io.Writer, config util.Config) error {
+	node, err := configNode(reflect.ValueOf(config.Redacted()))
+	if err != nil {
+		return err
+	}
+	out, err := yaml.Marshal(node)
+	if err != nil {
+		return err
+	}
+	if _, err = stdout.Write(out); err != nil {
+		return err
+	}
+
+	err = config.Validate()
+	var configErr *util.ConfigError
+	if errors.As(err, &configErr) {
+		for _, problem := range configErr.Problems {
+			fmt.Fprintln(stderr, "invalid:", problem)
+		}
+		return fmt.Errorf("config has %d problem(s)", len(configErr.Problems))
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(stderr, "config is valid")
+	return nil
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// configNode renders a config value as YAML which can be pasted back into config.yaml,
+// durations are written the way config.yaml writes them, e.g. 15m rather than 15m0s
+func configNode(v reflect.Value) (*yaml.Node, error) {
+	if v.Type() == durationType {
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: formatDuration(time.Duration(v.Int()))}, nil
+	}
+	if v.Kind() != reflect.Struct {
+		node := &yaml.Node{}
+		return node, node.Encode(v.Interface())
+	}
+
+	node := &yaml.Node{Kind: yaml.MappingNode}
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		if !field.IsExported() || name == "-" {
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+
+		value, err := configNode(v.Field(i))
+		if err != nil {
+			return nil, err
+		}
+		node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: name}, value)
+	}
+	return node, nil
+}
+
+// formatDuration drops the zero minutes and seconds of time.Duration.String, e.g. 1h0m0s is 1h
+func formatDuration(d time.Duration) string {
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/lushenle/plam/pkg/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckConfig(t *testing.T) {
+	config := validTestConfig()
+
+	var stdout, stderr bytes.Buffer
+	require.NoError(t, checkConfig(&stdout, &stderr, config))
+	require.Contains(t, stdout.String(), "tokenSymmetricKey: REDACTED")
+	require.Contains(t, stdout.String(), "accessTokenDuration: 1h\n")
+	require.NotContains(t, stdout.String(), "mypass")
+	require.NotContains(t, stdout.String(), config.Server.TokenSymmetricKey)
+	require.Contains(t, stderr.String(), "config is valid")
+
+	config.Loglevel = "verbose"
+	config.Server.AccessTokenDuration = 0
+
+	stdout.Reset()
+	stderr.Reset()
+	require.Error(t, checkConfig(&stdout, &stderr, config))
+	require.Contains(t, stderr.String(), "loglevel")
+	require.Contains(t, stderr.String(), "server.accessTokenDuration")
+}
+
+func TestCheckConfigDurations(t *testing.T) {
+	config := validTestConfig()
+	config.Server.AccessTokenDuration = 15 * time.Minute
+	config.Server.ShutdownTimeout = 90 * time.Second
+	config.Server.HandlerTimeout = 1500 * time.Millisecond
+	config.Database.Pool.MaxConnLifetime = time.Hour
+	config.Signup.InvitationDuration = 7 * 24 * time.Hour
+
+	var stdout, stderr bytes.Buffer
+	require.NoError(t, checkConfig(&stdout, &stderr, config))
+	require.Contains(t, stdout.String(), "accessTokenDuration: 15m\n")
+	require.Contains(t, stdout.String(), "shutdownTimeout: 1m30s\n")
+	require.Contains(t, stdout.String(), "handlerTimeout: 1.5s\n")
+	require.Contains(t, stdout.String(), "maxConnLifetime: 1h\n")
+	require.Contains(t, stdout.String(), "invitationDuration: 168h\n")
+
+	// The printed config loads back to the same durations
+	file := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(file, stdout.Bytes(), 0o600))
+	loaded, err := util.LoadConfigWithOptions(util.ConfigOptions{File: file})
+	require.NoError(t, err)
+	require.Equal(t, config.Server.AccessTokenDuration, loaded.Server.AccessTokenDuration)
+	require.Equal(t, config.Server.ShutdownTimeout, loaded.Server.ShutdownTimeout)
+	require.Equal(t, config.Server.HandlerTimeout, loaded.Server.HandlerTimeout)
+	require.Equal(t, config.Database.Pool.MaxConnLifetime, loaded.Database.Pool.MaxConnLifetime)
+	require.Equal(t, config.Signup.InvitationDuration, loaded.Signup.InvitationDuration)
+}
+
+func TestFormatDuration(t *testing.T) {
+	for d, want := range map[time.Duration]string{
+		0:                               "0s",
+		500 * time.Millisecond:          "500ms",
+		time.Minute:                     "1m",
+		15*time.Minute + 30*time.Second: "15m30s",
+		2 * time.Hour:                   "2h",
+		2*time.Hour + time.Minute:       "2h1m",
+		2*time.Hour + time.Second:       "2h0m1s",
+	} {
+		require.Equal(t, want, formatDuration(d))
+	}
+}
@@ -0,0 +1,206 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/lushenle/plam/pkg/util"
+)
+
+const migrateUsage = "expected 'plam migrate [--dry-run] up [N] | down N | goto V | force V | status | version'"
+
+// migrator is the part of util.Migrator used by the migrate command
+type migrator interface {
+	Files() []util.MigrationFile
+	Version() (uint, bool, error)
+	Up(limit int) error
+	Down(n int) error
+	Goto(version uint) error
+	Force(version int) error
+	PlanUp(limit int) ([]util.MigrationStep, error)
+	PlanDown(n int) ([]util.MigrationStep, error)
+	PlanGoto(version uint) ([]util.MigrationStep, error)
+}
+
+func runMigrate(_ context.Context, args []string) error {
+	var dryRun bool
+
+	flags := newFlagSet("migrate")
+	cf := addConfigFlags(flags)
+	flags.BoolVar(&dryRun, "dry-run", false, "print the migration files which would be applied without running them")
+	args, err := parseInterspersed(flags, args)
+	if err != nil {
+		return err
+	}
+
+	action := "up"
+	if len(args) > 0 {
+		action, args = args[0], args[1:]
+	}
+
+	switch action {
+	case "up", "down", "goto", "force", "status", "version":
+	default:
+		return fmt.Errorf("%w: unknown migrate action %q, %s", ErrUsage, action, migrateUsage)
+	}
+
+	config, err := loadConfig(cf)
+	if err != nil {
+		return err
+	}
+
+	m, err := util.NewMigrator(config.Database.MigrationURL, config.Database.DataSourceName)
+	if err != nil {
+		return fmt.Errorf("cannot open migrations: %w", err)
+	}
+	defer m.Close()
+
+	return migrateAction(os.Stdout, m, action, args, dryRun)
+}
+
+func migrateAction(w io.Writer, m migrator, action string, args []string, dryRun bool) error {
+	switch action {
+	case "up":
+		limit := 0
+		if len(args) > 0 {
+			n, err := positiveArg(args)
+			if err != nil {
+				return err
+			}
+			limit = n
+		}
+		if dryRun {
+			steps, err := m.PlanUp(limit)
+			if err != nil {
+				return err
+			}
+			printPlan(w, steps)
+			return nil
+		}
+		if err := m.Up(limit); err != nil {
+			return fmt.Errorf("migrate up failed: %w", err)
+		}
+	case "down":
+		n, err := positiveArg(args)
+		if err != nil {
+			return err
+		}
+		if dryRun {
+			steps, err := m.PlanDown(n)
+			if err != nil {
+				return err
+			}
+			printPlan(w, steps)
+			return nil
+		}
+		if err = m.Down(n); err != nil {
+			return fmt.Errorf("migrate down failed: %w", err)
+		}
+	case "goto":
+		version, err := versionArg(args)
+		if err != nil {
+			return err
+		}
+		if dryRun {
+			steps, err := m.PlanGoto(uint(version))
+			if err != nil {
+				return err
+			}
+			printPlan(w, steps)
+			return nil
+		}
+		if err = m.Goto(uint(version)); err != nil {
+			return fmt.Errorf("migrate goto failed: %w", err)
+		}
+	case "force":
+		version, err := versionArg(args)
+		if err != nil {
+			return err
+		}
+		if dryRun {
+			fmt.Fprintf(w, "would force version %d\n", version)
+			return nil
+		}
+		if err = m.Force(version); err != nil {
+			return fmt.Errorf("migrate force failed: %w", err)
+		}
+	case "status":
+		return printStatus(w, m)
+	case "version":
+		return printVersion(w, m)
+	default:
+		return fmt.Errorf("%w: unknown migrate action %q, %s", ErrUsage, action, migrateUsage)
+	}
+
+	return printVersion(w, m)
+}
+
+func printPlan(w io.Writer, steps []util.MigrationStep) {
+	if len(steps) == 0 {
+		fmt.Fprintln(w, "no change")
+		return
+	}
+
+	for _, step := range steps {
+		fmt.Fprintf(w, "would apply %s\n", step.Name())
+	}
+}
+
+func printVersion(w io.Writer, m migrator) error {
+	version, dirty, err := m.Version()
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "version: %d, dirty: %t\n", version, dirty)
+	return nil
+}
+
+func printStatus(w io.Writer, m migrator) error {
+	version, dirty, err := m.Version()
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "version: %d, dirty: %t\n", version, dirty)
+	for _, file := range m.Files() {
+		status := "pending"
+		switch {
+		case file.Version == version && dirty:
+			status = "dirty"
+		case file.Version <= version:
+			status = "applied"
+		}
+
+		step := util.MigrationStep{MigrationFile: file, Direction: util.MigrationUp}
+		fmt.Fprintf(w, "%-8s %s\n", status, step.Name())
+	}
+	return nil
+}
+
+func positiveArg(args []string) (int, error) {
+	if len(args) != 1 {
+		return 0, fmt.Errorf("%w: %s", ErrUsage, migrateUsage)
+	}
+
+	n, err := strconv.Atoi(args[0])
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("%w: %q is not a positive number", ErrUsage, args[0])
+	}
+	return n, nil
+}
+
+func versionArg(args []string) (int, error) {
+	if len(args) != 1 {
+		return 0, fmt.Errorf("%w: %s", ErrUsage, migrateUsage)
+	}
+
+	version, err := strconv.Atoi(args[0])
+	if err != nil || version < 0 {
+		return 0, fmt.Errorf("%w: %q is not a valid version", ErrUsage, args[0])
+	}
+	return version, nil
+}
@@ -0,0 +1,184 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/lushenle/plam/pkg/util"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeMigrator records the calls made by the migrate command
+type fakeMigrator struct {
+	files   []util.MigrationFile
+	version uint
+	dirty   bool
+	calls   []string
+}
+
+func (m *fakeMigrator) Files() []util.MigrationFile { return m.files }
+
+func (m *fakeMigrator) Version() (uint, bool, error) { return m.version, m.dirty, nil }
+
+func (m *fakeMigrator) Up(limit int) error {
+	m.calls = append(m.calls, "up")
+	return nil
+}
+
+func (m *fakeMigrator) Down(n int) error {
+	m.calls = append(m.calls, "down")
+	return nil
+}
+
+func (m *fakeMigrator) Goto(version uint) error {
+	m.calls = append(m.calls, "goto")
+	return nil
+}
+
+func (m *fakeMigrator) Force(version int) error {
+	m.calls = append(m.calls, "force")
+	return nil
+}
+
+func (m *fakeMigrator) PlanUp(limit int) ([]util.MigrationStep, error) {
+	return []util.MigrationStep{{MigrationFile: m.files[1], Direction: util.MigrationUp}}, nil
+}
+
+func (m *fakeMigrator) PlanDown(n int) ([]util.MigrationStep, error) {
+	return []util.MigrationStep{{MigrationFile: m.files[0], Direction: util.MigrationDown}}, nil
+}
+
+func (m *fakeMigrator) PlanGoto(version uint) ([]util.MigrationStep, error) {
+	return nil, nil
+}
+
+func newFakeMigrator() *fakeMigrator {
+	return &fakeMigrator{
+		files: []util.MigrationFile{
+			{Version: 1, Identifier: "init_schema"},
+			{Version: 2, Identifier: "add_password_history"},
+		},
+		version: 1,
+	}
+}
+
+func TestMigrateAction(t *testing.T) {
+	testCases := []struct {
+		name          string
+		action        string
+		args          []string
+		dryRun        bool
+		checkResponse func(t *testing.T, m *fakeMigrator, out string, err error)
+	}{
+		{
+			name:   "Up",
+			action: "up",
+			checkResponse: func(t *testing.T, m *fakeMigrator, out string, err error) {
+				require.NoError(t, err)
+				require.Equal(t, []string{"up"}, m.calls)
+				require.Equal(t, "version: 1, dirty: false\n", out)
+			},
+		},
+		{
+			name:   "UpDryRun",
+			action: "up",
+			dryRun: true,
+			checkResponse: func(t *testing.T, m *fakeMigrator, out string, err error) {
+				require.NoError(t, err)
+				require.Empty(t, m.calls)
+				require.Equal(t, "would apply 000002_add_password_history.up.sql\n", out)
+			},
+		},
+		{
+			name:   "Down",
+			action: "down",
+			args:   []string{"1"},
+			checkResponse: func(t *testing.T, m *fakeMigrator, out string, err error) {
+				require.NoError(t, err)
+				require.Equal(t, []string{"down"}, m.calls)
+			},
+		},
+		{
+			name:   "DownDryRun",
+			action: "down",
+			args:   []string{"1"},
+			dryRun: true,
+			checkResponse: func(t *testing.T, m *fakeMigrator, out string, err error) {
+				require.NoError(t, err)
+				require.Empty(t, m.calls)
+				require.Equal(t, "would apply 000001_init_schema.down.sql\n", out)
+			},
+		},
+		{
+			name:   "DownMissingCount",
+			action: "down",
+			checkResponse: func(t *testing.T, m *fakeMigrator, out string, err error) {
+				require.ErrorIs(t, err, ErrUsage)
+				require.Empty(t, m.calls)
+			},
+		},
+		{
+			name:   "DownInvalidCount",
+			action: "down",
+			args:   []string{"0"},
+			checkResponse: func(t *testing.T, m *fakeMigrator, out string, err error) {
+				require.ErrorIs(t, err, ErrUsage)
+				require.Empty(t, m.calls)
+			},
+		},
+		{
+			name:   "GotoDryRunNoChange",
+			action: "goto",
+			args:   []string{"1"},
+			dryRun: true,
+			checkResponse: func(t *testing.T, m *fakeMigrator, out string, err error) {
+				require.NoError(t, err)
+				require.Equal(t, "no change\n", out)
+			},
+		},
+		{
+			name:   "Force",
+			action: "force",
+			args:   []string{"2"},
+			checkResponse: func(t *testing.T, m *fakeMigrator, out string, err error) {
+				require.NoError(t, err)
+				require.Equal(t, []string{"force"}, m.calls)
+			},
+		},
+		{
+			name:   "ForceInvalidVersion",
+			action: "force",
+			args:   []string{"abc"},
+			checkResponse: func(t *testing.T, m *fakeMigrator, out string, err error) {
+				require.ErrorIs(t, err, ErrUsage)
+			},
+		},
+		{
+			name:   "Status",
+			action: "status",
+			checkResponse: func(t *testing.T, m *fakeMigrator, out string, err error) {
+				require.NoError(t, err)
+				require.Equal(t, "version: 1, dirty: false\n"+
+					"applied  000001_init_schema.up.sql\n"+
+					"pending  000002_add_password_history.up.sql\n", out)
+			},
+		},
+		{
+			name:   "UnknownAction",
+			action: "sideways",
+			checkResponse: func(t *testing.T, m *fakeMigrator, out string, err error) {
+				require.ErrorIs(t, err, ErrUsage)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			m := newFakeMigrator()
+			var out bytes.Buffer
+
+			err := migrateAction(&out, m, tc.action, tc.args, tc.dryRun)
+			tc.checkResponse(t, m, out.String(), err)
+		})
+	}
+}
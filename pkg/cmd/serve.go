@@ -0,0 +1,218 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/lushenle/plam/pkg/api"
+	"github.com/lushenle/plam/pkg/db"
+	"github.com/lushenle/plam/pkg/log"
+	"github.com/lushenle/plam/pkg/sso"
+	"github.com/lushenle/plam/pkg/token"
+	"github.com/lushenle/plam/pkg/tracing"
+	"github.com/lushenle/plam/pkg/util"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func runServe(ctx context.Context, args []string) error {
+	flags := newFlagSet("serve")
+	cf := addConfigFlags(flags)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	// Setup config
+	config, err := loadConfig(cf)
+	if err != nil {
+		return err
+	}
+
+	// The level has been validated by loadConfig, it can be changed at runtime
+	logLevel, err := zapcore.ParseLevel(config.Loglevel)
+	if err != nil {
+		return err
+	}
+	level := zap.NewAtomicLevelAt(logLevel)
+
+	// Deferred calls run in reverse order on shutdown and on startup errors, which are
+	// returned rather than exiting: the pool is closed first, then the spans are flushed,
+	// the logger is flushed and finally the log file is closed
+	plugin, closer := log.NewFilePlugin(config.Logfile, level)
+	defer closer.Close()
+	logger := log.NewLogger(plugin)
+	defer logger.Sync()
+	logger.Info("service starting...")
+
+	// Tracing is set up before the pool, so that queries are traced from the start,
+	// and shut down after the server, so that the spans of the last requests are flushed
+	shutdownTracing, err := tracing.Setup(ctx, config.Tracing)
+	if err != nil {
+		logger.Error("cannot set up tracing", zap.String("tracing", err.Error()))
+		return fmt.Errorf("cannot set up tracing: %w", err)
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), config.Server.ShutdownTimeout)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			logger.Error("tracing shutdown did not complete", zap.String("tracing", err.Error()))
+		}
+	}()
+
+	// Tokenmaker
+	tokenMaker, err := token.NewPasetoMaker(config.Server.TokenSymmetricKey)
+	if err != nil {
+		logger.Error("cannot create token maker", zap.String("tokenMaker", err.Error()))
+		return fmt.Errorf("cannot create token maker: %w", err)
+	}
+
+	// Set database
+	conn, err := openPool(ctx, config.Database.DataSourceName, config.Database.Pool)
+	if err != nil {
+		logger.Error("db connection failed", zap.String("db", err.Error()))
+		return err
+	}
+	defer conn.Close()
+
+	// Export the pool statistics next to the request metrics on /metrics
+	if err = prometheus.Register(db.NewPoolCollector(conn, "primary")); err != nil {
+		logger.Warn("cannot register pool metrics", zap.String("metrics", err.Error()))
+	}
+
+	// Optional read replica, the pool connects lazily so an unreachable replica does not stop the server
+	var replica *pgxpool.Pool
+	if config.Database.ReplicaDataSourceName != "" {
+		replica, err = openPool(ctx, config.Database.ReplicaDataSourceName, config.Database.Pool)
+		if err != nil {
+			logger.Error("db replica connection failed", zap.String("db", err.Error()))
+			return err
+		}
+		defer replica.Close()
+
+		if err = prometheus.Register(db.NewPoolCollector(replica, "replica")); err != nil {
+			logger.Warn("cannot register replica pool metrics", zap.String("metrics", err.Error()))
+		}
+	}
+
+	// Database schema migration
+	if config.Database.AutoMigrate {
+		if err = util.DBMigration(config.Database.MigrationURL, config.Database.DataSourceName); err != nil {
+			logger.Error("db migration failed", zap.String("db", err.Error()))
+			return fmt.Errorf("db migration failed: %w", err)
+		}
+		logger.Info("db migrated successfully")
+	}
+	store := db.NewStore(conn)
+	if replica != nil {
+		store = db.NewReplicaStore(conn, replica)
+		logger.Info("routing reads to the db replica")
+	}
+
+	// Password policy
+	passwordChecker, err := newPasswordChecker(config)
+	if err != nil {
+		logger.Error("cannot load breached password list", zap.String("password", err.Error()))
+		return fmt.Errorf("cannot load breached password list: %w", err)
+	}
+
+	opts := []api.ServerOption{
+		api.WithStore(store),
+		api.WithLogger(logger),
+		api.WithTokenMaker(tokenMaker),
+		api.WithPasswordChecker(passwordChecker),
+	}
+
+	// Single sign-on
+	if config.OIDC.Enabled {
+		oidcProvider, err := sso.NewProvider(ctx, config.OIDC)
+		if err != nil {
+			logger.Error("cannot create oidc provider", zap.String("oidc", err.Error()))
+			return fmt.Errorf("cannot create oidc provider: %w", err)
+		}
+		opts = append(opts, api.WithOIDCProvider(oidcProvider))
+	}
+
+	srv := api.NewServer(config, opts...)
+
+	// Apply changes of the config file without a restart when possible
+	reloader := &configReloader{current: config, level: level, server: srv, logger: logger}
+	if err = util.WatchConfig(cf.options(), reloader.apply); err != nil {
+		logger.Warn("config reload disabled", zap.String("config", err.Error()))
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- srv.Start(config.Server.ServerAddress)
+	}()
+
+	select {
+	case err = <-errCh:
+		if err != nil {
+			logger.Error("failed to run server", zap.String("server", err.Error()))
+			return err
+		}
+	case <-ctx.Done():
+		logger.Info("shutting down server", zap.Duration("delay", config.Server.ShutdownDelay),
+			zap.Duration("timeout", config.Server.ShutdownTimeout))
+
+		// The timeout applies to the requests in flight once the delay is over
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), config.Server.ShutdownDelay+config.Server.ShutdownTimeout)
+		defer cancel()
+
+		if err = srv.Shutdown(shutdownCtx); err != nil {
+			logger.Error("server shutdown did not complete", zap.String("server", err.Error()))
+		}
+		if startErr := <-errCh; startErr != nil {
+			logger.Error("failed to run server", zap.String("server", startErr.Error()))
+		}
+	}
+
+	logger.Info("server stopped")
+	return err
+}
+
+// configReloader applies the reloadable settings of a changed config and logs the
+// changed keys which require a restart
+type configReloader struct {
+	mu      sync.Mutex
+	current util.Config
+	level   zap.AtomicLevel
+	server  interface{ Reload(config util.Config) }
+	logger  *zap.Logger
+}
+
+func (r *configReloader) apply(config util.Config, err error) {
+	if err == nil {
+		err = config.Validate()
+	}
+	if err != nil {
+		r.logger.Error("ignoring config change", zap.String("config", err.Error()))
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// current only takes the reloaded keys, so that the keys waiting for a restart are
+	// reported until they are restarted or reverted
+	running := util.WithReloadable(r.current, config)
+	if reloaded := util.ChangedKeys(r.current, running); len(reloaded) > 0 {
+		logLevel, err := zapcore.ParseLevel(running.Loglevel)
+		if err != nil {
+			r.logger.Error("ignoring config change", zap.String("config", err.Error()))
+			return
+		}
+		r.level.SetLevel(logLevel)
+		r.server.Reload(running)
+		r.logger.Info("config reloaded", zap.Strings("keys", reloaded))
+	}
+
+	if restart := util.RestartRequiredKeys(running, config); len(restart) > 0 {
+		r.logger.Warn("config changes require a restart", zap.Strings("keys", restart))
+	}
+
+	r.current = running
+}
@@ -0,0 +1,240 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/mail"
+	"os"
+	"strings"
+	"unicode"
+
+	"github.com/lushenle/plam/pkg/db"
+	"github.com/lushenle/plam/pkg/util"
+)
+
+// stdin is read for passwords which are not given on the command line
+var stdin io.Reader = os.Stdin
+
+func runUser(ctx context.Context, args []string) error {
+	usage := fmt.Errorf("%w: expected 'plam user create' or 'plam user set-password'", ErrUsage)
+	if len(args) == 0 {
+		return usage
+	}
+
+	switch args[0] {
+	case "create":
+		return runUserCreate(ctx, args[1:])
+	case "set-password":
+		return runUserSetPassword(ctx, args[1:])
+	default:
+		return usage
+	}
+}
+
+// createUserOptions holds the flags of the user create command
+type createUserOptions struct {
+	Username string
+	Password string
+	FullName string
+	Email    string
+	Role     string
+}
+
+func runUserCreate(ctx context.Context, args []string) error {
+	var opts createUserOptions
+
+	flags := newFlagSet("user create")
+	cf := addConfigFlags(flags)
+	flags.StringVar(&opts.Username, "username", "", "username (alphanumeric)")
+	flags.StringVar(&opts.Password, "password", "", "password, read from stdin when empty")
+	flags.StringVar(&opts.FullName, "full-name", "", "full name")
+	flags.StringVar(&opts.Email, "email", "", "email address")
+	flags.StringVar(&opts.Role, "role", util.RoleUser, "role, one of admin or user")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if opts.Password == "" {
+		password, err := readPassword(stdin)
+		if err != nil {
+			return err
+		}
+		opts.Password = password
+	}
+
+	config, err := loadConfig(cf)
+	if err != nil {
+		return err
+	}
+
+	checker, err := newPasswordChecker(config)
+	if err != nil {
+		return err
+	}
+
+	store, conn, err := openStore(ctx, config)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	user, err := createUser(ctx, store, checker, opts)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("user %s created with role %s\n", user.Username, user.Role)
+	return nil
+}
+
+func createUser(ctx context.Context, store db.Store, checker *util.PasswordChecker, opts createUserOptions) (db.User, error) {
+	if opts.Username == "" || strings.IndexFunc(opts.Username, isNotAlphanumeric) >= 0 {
+		return db.User{}, fmt.Errorf("%w: username must be non-empty and alphanumeric", ErrUsage)
+	}
+	if opts.FullName == "" {
+		return db.User{}, fmt.Errorf("%w: full name is required", ErrUsage)
+	}
+	if _, err := mail.ParseAddress(opts.Email); err != nil {
+		return db.User{}, fmt.Errorf("%w: invalid email: %v", ErrUsage, err)
+	}
+	if opts.Role != util.RoleAdmin && opts.Role != util.RoleUser {
+		return db.User{}, fmt.Errorf("%w: role must be %s or %s", ErrUsage, util.RoleAdmin, util.RoleUser)
+	}
+
+	if err := checker.Check(opts.Password, opts.Username, opts.Email); err != nil {
+		return db.User{}, err
+	}
+
+	hashedPassword, err := util.HashPassword(opts.Password)
+	if err != nil {
+		return db.User{}, err
+	}
+
+	user, err := store.CreateUser(ctx, db.CreateUserParams{
+		Username:       opts.Username,
+		Role:           opts.Role,
+		HashedPassword: hashedPassword,
+		FullName:       opts.FullName,
+		Email:          opts.Email,
+	})
+	if err != nil {
+		if db.ErrorCode(err) == db.UniqueViolation {
+			return db.User{}, fmt.Errorf("username or email already exists: %w", err)
+		}
+		return db.User{}, fmt.Errorf("cannot create user: %w", err)
+	}
+
+	return user, nil
+}
+
+func runUserSetPassword(ctx context.Context, args []string) error {
+	var username, password string
+
+	flags := newFlagSet("user set-password")
+	cf := addConfigFlags(flags)
+	flags.StringVar(&username, "username", "", "username")
+	flags.StringVar(&password, "password", "", "new password, read from stdin when empty")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if password == "" {
+		var err error
+		password, err = readPassword(stdin)
+		if err != nil {
+			return err
+		}
+	}
+
+	config, err := loadConfig(cf)
+	if err != nil {
+		return err
+	}
+
+	checker, err := newPasswordChecker(config)
+	if err != nil {
+		return err
+	}
+
+	store, conn, err := openStore(ctx, config)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if err = setPassword(ctx, store, checker, username, password); err != nil {
+		return err
+	}
+
+	fmt.Printf("password of user %s updated\n", username)
+	return nil
+}
+
+func setPassword(ctx context.Context, store db.Store, checker *util.PasswordChecker, username, password string) error {
+	if username == "" {
+		return fmt.Errorf("%w: username is required", ErrUsage)
+	}
+
+	user, err := store.GetUser(ctx, username)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return fmt.Errorf("user %s does not exist", username)
+		}
+		return fmt.Errorf("cannot get user: %w", err)
+	}
+
+	if err = checker.Check(password, user.Username, user.Email); err != nil {
+		return err
+	}
+
+	hashedPassword, err := util.HashPassword(password)
+	if err != nil {
+		return err
+	}
+
+	_, err = store.ChangePasswordTx(ctx, db.ChangePasswordTxParams{
+		Username:          user.Username,
+		OldHashedPassword: user.HashedPassword,
+		NewHashedPassword: hashedPassword,
+	})
+	if err != nil {
+		return fmt.Errorf("cannot update password: %w", err)
+	}
+
+	return nil
+}
+
+func newPasswordChecker(config util.Config) (*util.PasswordChecker, error) {
+	var breached *util.BreachedPasswords
+	if config.Password.BreachedListFile != "" {
+		var err error
+		breached, err = util.LoadBreachedPasswords(config.Password.BreachedListFile)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return util.NewPasswordChecker(config.Password, breached), nil
+}
+
+// readPassword reads the password from the first line of r
+func readPassword(r io.Reader) (string, error) {
+	line, err := bufio.NewReader(r).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", fmt.Errorf("cannot read password: %w", err)
+	}
+
+	password := strings.TrimRight(line, "\r\n")
+	if password == "" {
+		return "", fmt.Errorf("%w: password is required", ErrUsage)
+	}
+
+	return password, nil
+}
+
+func isNotAlphanumeric(r rune) bool {
+	return r >= unicode.MaxASCII || !(unicode.IsLetter(r) || unicode.IsDigit(r))
+}
@@ -0,0 +1,193 @@
+package cmd
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/lushenle/plam/pkg/db"
+	mockdb "github.com/lushenle/plam/pkg/db/mock"
+	"github.com/lushenle/plam/pkg/util"
+	"github.com/stretchr/testify/require"
+)
+
+func randomCreateUserOptions() createUserOptions {
+	return createUserOptions{
+		Username: util.RandomString(6),
+		Password: util.RandomString(8),
+		FullName: util.RandomString(8),
+		Email:    util.RandomEmail(),
+		Role:     util.RoleAdmin,
+	}
+}
+
+func TestCreateUser(t *testing.T) {
+	testCases := []struct {
+		name       string
+		update     func(opts *createUserOptions)
+		buildStubs func(store *mockdb.MockStore, opts createUserOptions)
+		checkError func(t *testing.T, err error)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockStore, opts createUserOptions) {
+				store.EXPECT().CreateUser(gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateUserParams) (db.User, error) {
+						require.Equal(t, opts.Username, arg.Username)
+						require.Equal(t, util.RoleAdmin, arg.Role)
+						require.Equal(t, opts.Email, arg.Email)
+						require.NoError(t, util.CheckPassword(opts.Password, arg.HashedPassword))
+						return db.User{Username: arg.Username, Role: arg.Role}, nil
+					})
+			},
+			checkError: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "InvalidUsername",
+			update: func(opts *createUserOptions) {
+				opts.Username = "invalid-user#1"
+			},
+			buildStubs: func(store *mockdb.MockStore, opts createUserOptions) {
+				store.EXPECT().CreateUser(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkError: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, ErrUsage)
+			},
+		},
+		{
+			name: "InvalidEmail",
+			update: func(opts *createUserOptions) {
+				opts.Email = "invalid-email"
+			},
+			buildStubs: func(store *mockdb.MockStore, opts createUserOptions) {
+				store.EXPECT().CreateUser(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkError: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, ErrUsage)
+			},
+		},
+		{
+			name: "InvalidRole",
+			update: func(opts *createUserOptions) {
+				opts.Role = "root"
+			},
+			buildStubs: func(store *mockdb.MockStore, opts createUserOptions) {
+				store.EXPECT().CreateUser(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkError: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, ErrUsage)
+			},
+		},
+		{
+			name: "WeakPassword",
+			update: func(opts *createUserOptions) {
+				opts.Password = "abc"
+			},
+			buildStubs: func(store *mockdb.MockStore, opts createUserOptions) {
+				store.EXPECT().CreateUser(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkError: func(t *testing.T, err error) {
+				var policyErr *util.PasswordPolicyError
+				require.ErrorAs(t, err, &policyErr)
+			},
+		},
+		{
+			name: "InternalError",
+			buildStubs: func(store *mockdb.MockStore, opts createUserOptions) {
+				store.EXPECT().CreateUser(gomock.Any(), gomock.Any()).Times(1).Return(db.User{}, sql.ErrConnDone)
+			},
+			checkError: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, sql.ErrConnDone)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			opts := randomCreateUserOptions()
+			if tc.update != nil {
+				tc.update(&opts)
+			}
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store, opts)
+
+			checker := util.NewPasswordChecker(util.PasswordPolicy{}, nil)
+			_, err := createUser(context.Background(), store, checker, opts)
+			tc.checkError(t, err)
+		})
+	}
+}
+
+func TestSetPassword(t *testing.T) {
+	hashedPassword, err := util.HashPassword(util.RandomString(8))
+	require.NoError(t, err)
+
+	user := db.User{
+		Username:       util.RandomString(6),
+		Role:           util.RoleAdmin,
+		HashedPassword: hashedPassword,
+		Email:          util.RandomEmail(),
+	}
+	password := util.RandomString(8)
+	checker := util.NewPasswordChecker(util.PasswordPolicy{}, nil)
+
+	t.Run("OK", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		store := mockdb.NewMockStore(ctrl)
+		store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+		store.EXPECT().ChangePasswordTx(gomock.Any(), gomock.Any()).Times(1).
+			DoAndReturn(func(_ context.Context, arg db.ChangePasswordTxParams) (db.ChangePasswordTxResult, error) {
+				require.Equal(t, user.HashedPassword, arg.OldHashedPassword)
+				require.NoError(t, util.CheckPassword(password, arg.NewHashedPassword))
+				return db.ChangePasswordTxResult{User: user}, nil
+			})
+
+		require.NoError(t, setPassword(context.Background(), store, checker, user.Username, password))
+	})
+
+	t.Run("UserNotFound", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		store := mockdb.NewMockStore(ctrl)
+		store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(1).Return(db.User{}, db.ErrRecordNotFound)
+		store.EXPECT().ChangePasswordTx(gomock.Any(), gomock.Any()).Times(0)
+
+		require.Error(t, setPassword(context.Background(), store, checker, user.Username, password))
+	})
+
+	t.Run("WeakPassword", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		store := mockdb.NewMockStore(ctrl)
+		store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(1).Return(user, nil)
+		store.EXPECT().ChangePasswordTx(gomock.Any(), gomock.Any()).Times(0)
+
+		var policyErr *util.PasswordPolicyError
+		require.ErrorAs(t, setPassword(context.Background(), store, checker, user.Username, "abc"), &policyErr)
+	})
+}
+
+func TestReadPassword(t *testing.T) {
+	password, err := readPassword(strings.NewReader("secret123\r\nignored\n"))
+	require.NoError(t, err)
+	require.Equal(t, "secret123", password)
+
+	password, err = readPassword(strings.NewReader("secret123"))
+	require.NoError(t, err)
+	require.Equal(t, "secret123", password)
+
+	_, err = readPassword(strings.NewReader("\n"))
+	require.ErrorIs(t, err, ErrUsage)
+}
@@ -14,6 +14,8 @@ const (
 
 var ErrRecordNotFound = pgx.ErrNoRows
 
+var ErrInvalidInvitation = errors.New("invitation is invalid, expired or already used")
+
 var ErrUniqueViolation = &pgconn.PgError{
 	Code: UniqueViolation,
 }
@@ -0,0 +1,385 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+var (
+	// ErrInvalidSort is returned when a sort refers to a column which cannot be sorted on
+	ErrInvalidSort = errors.New("invalid sort")
+	// ErrInvalidSortValue is returned when a keyset value does not match the type of the sort column
+	ErrInvalidSortValue = errors.New("invalid sort value")
+)
+
+// Sort orders the rows of the Filter* queries by Column, ties are broken by id
+type Sort struct {
+	Column string
+	Desc   bool
+}
+
+// String returns the sort in the format accepted by ParseSort
+func (sort Sort) String() string {
+	if sort.Desc {
+		return "-" + sort.Column
+	}
+	return sort.Column
+}
+
+// ParseSort parses "column" for an ascending or "-column" for a descending order,
+// column must be one of columns
+func ParseSort(s string, columns []string) (Sort, error) {
+	sort := Sort{Column: strings.TrimPrefix(s, "-"), Desc: strings.HasPrefix(s, "-")}
+	for _, column := range columns {
+		if column == sort.Column {
+			return sort, nil
+		}
+	}
+	return Sort{}, fmt.Errorf("%w %q, must be one of %s with an optional - prefix", ErrInvalidSort, s, strings.Join(columns, ", "))
+}
+
+// ListFilter narrows and orders the rows returned by the Filter* queries, zero fields are ignored
+type ListFilter struct {
+	MinAmount *float32
+	MaxAmount *float32
+	// CreatedFrom is inclusive, CreatedTo is exclusive
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	// ProjectID only applies to incomes
+	ProjectID *uuid.UUID
+	// Name is a case-insensitive substring of the name, payee, borrower or owner
+	Name string
+	// Sort defaults to created_at in ascending order
+	Sort Sort
+}
+
+// IsZero reports whether the filter neither filters nor sorts
+func (filter ListFilter) IsZero() bool {
+	return filter == ListFilter{}
+}
+
+// Keyset selects the rows following, or preceding when Backward is set, the row
+// whose sort column is Value and id is ID
+type Keyset struct {
+	// Value is the text representation of the sort column, as returned by SortValue
+	Value    string
+	ID       uuid.UUID
+	Backward bool
+}
+
+// FilterParams are the parameters of the Filter* queries, Offset is ignored when Keyset is set.
+// Rows selected backward are returned in reverse order.
+type FilterParams struct {
+	ListFilter
+	Keyset *Keyset
+	Offset int32
+	Limit  int32
+}
+
+// filterTable describes a table the Filter* queries can read
+type filterTable struct {
+	name string
+	// queryName names the Filter* and CountFilter* queries of the table in the traces and metrics
+	queryName  string
+	columns    string
+	nameColumn string
+	hasProject bool
+	// sortColumns maps the sortable columns to the type cursor values are cast to
+	sortColumns map[string]string
+}
+
+var (
+	ProjectSortColumns = []string{"created_at", "amount", "name"}
+	IncomeSortColumns  = []string{"created_at", "amount", "payee"}
+	LoanSortColumns    = []string{"created_at", "amount", "borrower"}
+	PayOutSortColumns  = []string{"created_at", "amount", "owner"}
+)
+
+var (
+	projectTable = filterTable{
+		name:        "project",
+		queryName:   "Projects",
+		columns:     "id, name, amount, description, created_at, updated_at",
+		nameColumn:  "name",
+		sortColumns: map[string]string{"created_at": "timestamptz", "amount": "real", "name": "varchar"},
+	}
+	incomeTable = filterTable{
+		name:        "income",
+		queryName:   "Incomes",
+		columns:     "id, payee, amount, project_id, created_at, updated_at",
+		nameColumn:  "payee",
+		hasProject:  true,
+		sortColumns: map[string]string{"created_at": "timestamptz", "amount": "real", "payee": "varchar"},
+	}
+	loanTable = filterTable{
+		name:        "loan",
+		queryName:   "Loans",
+		columns:     "id, borrower, amount, subject, created_at, updated_at",
+		nameColumn:  "borrower",
+		sortColumns: map[string]string{"created_at": "timestamptz", "amount": "real", "borrower": "varchar"},
+	}
+	payOutTable = filterTable{
+		name:        "pay_out",
+		queryName:   "PayOuts",
+		columns:     "id, owner, amount, subject, created_at, updated_at",
+		nameColumn:  "owner",
+		sortColumns: map[string]string{"created_at": "timestamptz", "amount": "real", "owner": "varchar"},
+	}
+)
+
+// queryBuilder assembles a statement, values are always passed as arguments
+type queryBuilder struct {
+	sql  strings.Builder
+	args []any
+}
+
+// arg adds value to the arguments and returns its placeholder
+func (b *queryBuilder) arg(value any) string {
+	b.args = append(b.args, value)
+	return "$" + strconv.Itoa(len(b.args))
+}
+
+// where writes the conditions of filter and keyset on table, value is the parsed value of keyset
+func (b *queryBuilder) where(table filterTable, filter ListFilter, keyset *Keyset, value any, sort Sort) {
+	var conditions []string
+	if filter.MinAmount != nil {
+		conditions = append(conditions, "amount >= "+b.arg(*filter.MinAmount))
+	}
+	if filter.MaxAmount != nil {
+		conditions = append(conditions, "amount <= "+b.arg(*filter.MaxAmount))
+	}
+	if filter.CreatedFrom != nil {
+		conditions = append(conditions, "created_at >= "+b.arg(*filter.CreatedFrom))
+	}
+	if filter.CreatedTo != nil {
+		conditions = append(conditions, "created_at < "+b.arg(*filter.CreatedTo))
+	}
+	if filter.ProjectID != nil && table.hasProject {
+		conditions = append(conditions, "project_id = "+b.arg(*filter.ProjectID))
+	}
+	if filter.Name != "" {
+		conditions = append(conditions, table.nameColumn+" ILIKE "+b.arg("%"+escapeLike(filter.Name)+"%"))
+	}
+	if keyset != nil {
+		op := ">"
+		if sort.Desc != keyset.Backward {
+			op = "<"
+		}
+		conditions = append(conditions, fmt.Sprintf("(%s, id) %s (%s::%s, %s)",
+			sort.Column, op, b.arg(value), table.sortColumns[sort.Column], b.arg(keyset.ID)))
+	}
+
+	if len(conditions) > 0 {
+		b.sql.WriteString(" WHERE ")
+		b.sql.WriteString(strings.Join(conditions, " AND "))
+	}
+}
+
+// escapeLike escapes the wildcards of a LIKE pattern
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+// tableSort returns the sort of filter, checking that the column belongs to table
+func tableSort(table filterTable, filter ListFilter) (Sort, error) {
+	sort := filter.Sort
+	if sort.Column == "" {
+		sort.Column = "created_at"
+	}
+	if _, ok := table.sortColumns[sort.Column]; !ok {
+		return sort, fmt.Errorf("%w %q", ErrInvalidSort, sort.Column)
+	}
+	return sort, nil
+}
+
+// buildFilterQuery returns the statement and arguments selecting the rows of table matching arg,
+// the statement is named like the sqlc queries for the QueryTracer
+func buildFilterQuery(table filterTable, arg FilterParams) (string, []any, error) {
+	sort, err := tableSort(table, arg.ListFilter)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var value any
+	if arg.Keyset != nil {
+		if value, err = ParseSortValue(sort.Column, arg.Keyset.Value); err != nil {
+			return "", nil, err
+		}
+	}
+
+	var b queryBuilder
+	b.sql.WriteString(sqlcNamePrefix + "Filter" + table.queryName + " :many\n")
+	b.sql.WriteString("SELECT " + table.columns + " FROM " + table.name)
+	b.where(table, arg.ListFilter, arg.Keyset, value, sort)
+
+	direction := "ASC"
+	if sort.Desc != (arg.Keyset != nil && arg.Keyset.Backward) {
+		direction = "DESC"
+	}
+	fmt.Fprintf(&b.sql, " ORDER BY %s %s, id %s", sort.Column, direction, direction)
+
+	if arg.Keyset == nil {
+		b.sql.WriteString(" OFFSET " + b.arg(arg.Offset))
+	}
+	b.sql.WriteString(" LIMIT " + b.arg(arg.Limit))
+
+	return b.sql.String(), b.args, nil
+}
+
+// buildCountQuery returns the statement and arguments counting the rows of table matching filter
+func buildCountQuery(table filterTable, filter ListFilter) (string, []any) {
+	var b queryBuilder
+	b.sql.WriteString(sqlcNamePrefix + "CountFilter" + table.queryName + " :one\n")
+	b.sql.WriteString("SELECT count(*) FROM " + table.name)
+	b.where(table, filter, nil, nil, Sort{})
+	return b.sql.String(), b.args
+}
+
+// filterRows runs the Filter* query on table and scans the rows with scan
+func filterRows[T any](ctx context.Context, q *Queries, table filterTable, arg FilterParams, scan func(row pgx.Row) (T, error)) ([]T, error) {
+	query, args, err := buildFilterQuery(table, arg)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := q.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []T{}
+	for rows.Next() {
+		item, err := scan(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func countRows(ctx context.Context, q *Queries, table filterTable, filter ListFilter) (int64, error) {
+	query, args := buildCountQuery(table, filter)
+	var count int64
+	err := q.db.QueryRow(ctx, query, args...).Scan(&count)
+	return count, err
+}
+
+// sortValue returns the text representation of the sort column of a row
+func sortValue(column string, createdAt time.Time, amount float32, name string) string {
+	switch column {
+	case "amount":
+		return strconv.FormatFloat(float64(amount), 'g', -1, 32)
+	case "created_at", "":
+		return createdAt.Format(time.RFC3339Nano)
+	default:
+		return name
+	}
+}
+
+// ParseSortValue parses the text representation of the sort column returned by SortValue,
+// so that a malformed keyset is rejected before reaching the database
+func ParseSortValue(column, value string) (any, error) {
+	switch column {
+	case "amount":
+		amount, err := strconv.ParseFloat(value, 32)
+		if err != nil {
+			return nil, fmt.Errorf("%w %q for %s", ErrInvalidSortValue, value, column)
+		}
+		return float32(amount), nil
+	case "created_at", "":
+		createdAt, err := time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			return nil, fmt.Errorf("%w %q for %s", ErrInvalidSortValue, value, column)
+		}
+		return createdAt, nil
+	default:
+		if !utf8.ValidString(value) || strings.ContainsRune(value, 0) {
+			return nil, fmt.Errorf("%w for %s", ErrInvalidSortValue, column)
+		}
+		return value, nil
+	}
+}
+
+// FilterProjects returns the projects matching arg
+func (q *Queries) FilterProjects(ctx context.Context, arg FilterParams) ([]Project, error) {
+	return filterRows(ctx, q, projectTable, arg, func(row pgx.Row) (i Project, err error) {
+		err = row.Scan(&i.ID, &i.Name, &i.Amount, &i.Description, &i.CreatedAt, &i.UpdatedAt)
+		return
+	})
+}
+
+// CountFilterProjects returns the number of projects matching filter
+func (q *Queries) CountFilterProjects(ctx context.Context, filter ListFilter) (int64, error) {
+	return countRows(ctx, q, projectTable, filter)
+}
+
+// SortValue returns the value of column for a Keyset
+func (i Project) SortValue(column string) string {
+	return sortValue(column, i.CreatedAt, i.Amount, i.Name)
+}
+
+// FilterIncomes returns the incomes matching arg
+func (q *Queries) FilterIncomes(ctx context.Context, arg FilterParams) ([]Income, error) {
+	return filterRows(ctx, q, incomeTable, arg, func(row pgx.Row) (i Income, err error) {
+		err = row.Scan(&i.ID, &i.Payee, &i.Amount, &i.ProjectID, &i.CreatedAt, &i.UpdatedAt)
+		return
+	})
+}
+
+// CountFilterIncomes returns the number of incomes matching filter
+func (q *Queries) CountFilterIncomes(ctx context.Context, filter ListFilter) (int64, error) {
+	return countRows(ctx, q, incomeTable, filter)
+}
+
+// SortValue returns the value of column for a Keyset
+func (i Income) SortValue(column string) string {
+	return sortValue(column, i.CreatedAt, i.Amount, i.Payee)
+}
+
+// FilterLoans returns the loans matching arg
+func (q *Queries) FilterLoans(ctx context.Context, arg FilterParams) ([]Loan, error) {
+	return filterRows(ctx, q, loanTable, arg, func(row pgx.Row) (i Loan, err error) {
+		err = row.Scan(&i.ID, &i.Borrower, &i.Amount, &i.Subject, &i.CreatedAt, &i.UpdatedAt)
+		return
+	})
+}
+
+// CountFilterLoans returns the number of loans matching filter
+func (q *Queries) CountFilterLoans(ctx context.Context, filter ListFilter) (int64, error) {
+	return countRows(ctx, q, loanTable, filter)
+}
+
+// SortValue returns the value of column for a Keyset
+func (i Loan) SortValue(column string) string {
+	return sortValue(column, i.CreatedAt, i.Amount, i.Borrower)
+}
+
+// FilterPayOuts returns the pay outs matching arg
+func (q *Queries) FilterPayOuts(ctx context.Context, arg FilterParams) ([]PayOut, error) {
+	return filterRows(ctx, q, payOutTable, arg, func(row pgx.Row) (i PayOut, err error) {
+		err = row.Scan(&i.ID, &i.Owner, &i.Amount, &i.Subject, &i.CreatedAt, &i.UpdatedAt)
+		return
+	})
+}
+
+// CountFilterPayOuts returns the number of pay outs matching filter
+func (q *Queries) CountFilterPayOuts(ctx context.Context, filter ListFilter) (int64, error) {
+	return countRows(ctx, q, payOutTable, filter)
+}
+
+// SortValue returns the value of column for a Keyset
+func (i PayOut) SortValue(column string) string {
+	return sortValue(column, i.CreatedAt, i.Amount, i.Owner)
+}
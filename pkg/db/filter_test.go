@@ -0,0 +1,239 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lushenle/plam/pkg/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSort(t *testing.T) {
+	sort, err := ParseSort("-amount", IncomeSortColumns)
+	require.NoError(t, err)
+	require.Equal(t, Sort{Column: "amount", Desc: true}, sort)
+	require.Equal(t, "-amount", sort.String())
+
+	sort, err = ParseSort("payee", IncomeSortColumns)
+	require.NoError(t, err)
+	require.Equal(t, Sort{Column: "payee"}, sort)
+
+	for _, s := range []string{"", "borrower", "amount; DROP TABLE income", "--amount"} {
+		_, err = ParseSort(s, IncomeSortColumns)
+		require.ErrorIs(t, err, ErrInvalidSort, s)
+	}
+}
+
+func TestBuildFilterQuery(t *testing.T) {
+	minAmount := float32(10)
+	createdTo := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	projectID := uuid.New()
+	id := uuid.New()
+
+	testCases := []struct {
+		name      string
+		table     filterTable
+		arg       FilterParams
+		wantQuery string
+		wantArgs  []any
+		wantErr   error
+	}{
+		{
+			name:  "NoFilter",
+			table: incomeTable,
+			arg:   FilterParams{Offset: 10, Limit: 5},
+			wantQuery: "-- name: FilterIncomes :many\n" +
+				"SELECT id, payee, amount, project_id, created_at, updated_at FROM income ORDER BY created_at ASC, id ASC OFFSET $1 LIMIT $2",
+			wantArgs: []any{int32(10), int32(5)},
+		},
+		{
+			name:  "Filters",
+			table: incomeTable,
+			arg: FilterParams{
+				ListFilter: ListFilter{
+					MinAmount: &minAmount,
+					CreatedTo: &createdTo,
+					ProjectID: &projectID,
+					Name:      "50%_off",
+					Sort:      Sort{Column: "amount", Desc: true},
+				},
+				Limit: 5,
+			},
+			wantQuery: "-- name: FilterIncomes :many\n" +
+				"SELECT id, payee, amount, project_id, created_at, updated_at FROM income" +
+				" WHERE amount >= $1 AND created_at < $2 AND project_id = $3 AND payee ILIKE $4" +
+				" ORDER BY amount DESC, id DESC OFFSET $5 LIMIT $6",
+			wantArgs: []any{minAmount, createdTo, projectID, `%50\%\_off%`, int32(0), int32(5)},
+		},
+		{
+			name:  "ProjectIgnoredOnLoans",
+			table: loanTable,
+			arg: FilterParams{
+				ListFilter: ListFilter{ProjectID: &projectID, Name: "john"},
+				Limit:      5,
+			},
+			wantQuery: "-- name: FilterLoans :many\n" +
+				"SELECT id, borrower, amount, subject, created_at, updated_at FROM loan" +
+				" WHERE borrower ILIKE $1 ORDER BY created_at ASC, id ASC OFFSET $2 LIMIT $3",
+			wantArgs: []any{"%john%", int32(0), int32(5)},
+		},
+		{
+			name:  "KeysetForward",
+			table: projectTable,
+			arg: FilterParams{
+				ListFilter: ListFilter{Sort: Sort{Column: "amount", Desc: true}},
+				Keyset:     &Keyset{Value: "12.5", ID: id},
+				Offset:     10,
+				Limit:      5,
+			},
+			wantQuery: "-- name: FilterProjects :many\n" +
+				"SELECT id, name, amount, description, created_at, updated_at FROM project" +
+				" WHERE (amount, id) < ($1::real, $2) ORDER BY amount DESC, id DESC LIMIT $3",
+			wantArgs: []any{float32(12.5), id, int32(5)},
+		},
+		{
+			name:  "KeysetBackward",
+			table: payOutTable,
+			arg: FilterParams{
+				ListFilter: ListFilter{Sort: Sort{Column: "owner"}},
+				Keyset:     &Keyset{Value: "john", ID: id, Backward: true},
+				Limit:      5,
+			},
+			wantQuery: "-- name: FilterPayOuts :many\n" +
+				"SELECT id, owner, amount, subject, created_at, updated_at FROM pay_out" +
+				" WHERE (owner, id) < ($1::varchar, $2) ORDER BY owner DESC, id DESC LIMIT $3",
+			wantArgs: []any{"john", id, int32(5)},
+		},
+		{
+			name:    "InvalidSort",
+			table:   projectTable,
+			arg:     FilterParams{ListFilter: ListFilter{Sort: Sort{Column: "payee"}}, Limit: 5},
+			wantErr: ErrInvalidSort,
+		},
+		{
+			name:  "InvalidKeysetValue",
+			table: projectTable,
+			arg: FilterParams{
+				ListFilter: ListFilter{Sort: Sort{Column: "amount"}},
+				Keyset:     &Keyset{Value: "abc", ID: id},
+				Limit:      5,
+			},
+			wantErr: ErrInvalidSortValue,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			query, args, err := buildFilterQuery(tc.table, tc.arg)
+			if tc.wantErr != nil {
+				require.ErrorIs(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.wantQuery, query)
+			require.Equal(t, tc.wantArgs, args)
+		})
+	}
+}
+
+func TestBuildCountQuery(t *testing.T) {
+	maxAmount := float32(100)
+	query, args := buildCountQuery(projectTable, ListFilter{
+		MaxAmount: &maxAmount,
+		Sort:      Sort{Column: "amount", Desc: true},
+	})
+	require.Equal(t, "-- name: CountFilterProjects :one\nSELECT count(*) FROM project WHERE amount <= $1", query)
+	require.Equal(t, []any{maxAmount}, args)
+}
+
+func TestSortValue(t *testing.T) {
+	createdAt := time.Date(2024, 1, 2, 3, 4, 5, 6000, time.UTC)
+	income := Income{Payee: "john", Amount: 0.1, CreatedAt: createdAt}
+
+	require.Equal(t, "2024-01-02T03:04:05.000006Z", income.SortValue("created_at"))
+	require.Equal(t, "0.1", income.SortValue("amount"))
+	require.Equal(t, "john", income.SortValue("payee"))
+}
+
+func TestParseSortValue(t *testing.T) {
+	createdAt := time.Date(2024, 1, 2, 3, 4, 5, 6000, time.UTC)
+	income := Income{Payee: "john", Amount: 0.1, CreatedAt: createdAt}
+
+	value, err := ParseSortValue("created_at", income.SortValue("created_at"))
+	require.NoError(t, err)
+	require.Equal(t, createdAt, value)
+
+	value, err = ParseSortValue("amount", income.SortValue("amount"))
+	require.NoError(t, err)
+	require.Equal(t, income.Amount, value)
+
+	value, err = ParseSortValue("payee", income.SortValue("payee"))
+	require.NoError(t, err)
+	require.Equal(t, income.Payee, value)
+
+	testCases := []struct {
+		column string
+		value  string
+	}{
+		{column: "amount", value: "abc"},
+		{column: "amount", value: "1e100"},
+		{column: "created_at", value: "2024-01-02"},
+		{column: "created_at", value: "abc"},
+		{column: "payee", value: "\xff"},
+		{column: "payee", value: "a\x00b"},
+	}
+	for _, tc := range testCases {
+		_, err := ParseSortValue(tc.column, tc.value)
+		require.ErrorIs(t, err, ErrInvalidSortValue, tc.value)
+	}
+}
+
+func TestFilterIncomes(t *testing.T) {
+	project := createRandomProject(t)
+	incomes := make([]Income, 3)
+	for i := range incomes {
+		income, err := testStore.CreateIncome(context.Background(), CreateIncomeParams{
+			Payee:     util.RandomString(10),
+			Amount:    float32(10 * (i + 1)),
+			ProjectID: project.ID,
+		})
+		require.NoError(t, err)
+		incomes[i] = income
+	}
+
+	minAmount := float32(15)
+	filter := ListFilter{
+		MinAmount: &minAmount,
+		ProjectID: &project.ID,
+		Sort:      Sort{Column: "amount", Desc: true},
+	}
+
+	count, err := testStore.CountFilterIncomes(context.Background(), filter)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), count)
+
+	got, err := testStore.FilterIncomes(context.Background(), FilterParams{ListFilter: filter, Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	require.Equal(t, incomes[2].ID, got[0].ID)
+
+	next, err := testStore.FilterIncomes(context.Background(), FilterParams{
+		ListFilter: filter,
+		Keyset:     &Keyset{Value: got[0].SortValue("amount"), ID: got[0].ID},
+		Limit:      5,
+	})
+	require.NoError(t, err)
+	require.Len(t, next, 1)
+	require.Equal(t, incomes[1].ID, next[0].ID)
+
+	prev, err := testStore.FilterIncomes(context.Background(), FilterParams{
+		ListFilter: filter,
+		Keyset:     &Keyset{Value: next[0].SortValue("amount"), ID: next[0].ID, Backward: true},
+		Limit:      5,
+	})
+	require.NoError(t, err)
+	require.Len(t, prev, 1)
+	require.Equal(t, incomes[2].ID, prev[0].ID)
+}
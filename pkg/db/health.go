@@ -0,0 +1,25 @@
+package db
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// getMigrationVersion reads the table maintained by golang-migrate
+const getMigrationVersion = `SELECT version, dirty FROM schema_migrations LIMIT 1`
+
+// Ping checks that a connection to the database can be acquired
+func (store *SQLStore) Ping(ctx context.Context) error {
+	return store.connPool.Ping(ctx)
+}
+
+// MigrationVersion returns the schema version applied to the database, 0 when no migration has run
+func (store *SQLStore) MigrationVersion(ctx context.Context) (version int64, dirty bool, err error) {
+	err = store.connPool.QueryRow(ctx, getMigrationVersion).Scan(&version, &dirty)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, false, nil
+	}
+	return version, dirty, err
+}
@@ -0,0 +1,23 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/lushenle/plam/pkg/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPing(t *testing.T) {
+	require.NoError(t, testStore.Ping(context.Background()))
+}
+
+func TestMigrationVersion(t *testing.T) {
+	latest, err := util.LatestMigrationVersion("")
+	require.NoError(t, err)
+
+	version, dirty, err := testStore.MigrationVersion(context.Background())
+	require.NoError(t, err)
+	require.False(t, dirty)
+	require.Equal(t, int64(latest), version)
+}
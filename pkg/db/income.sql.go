@@ -7,10 +7,34 @@ package db
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 )
 
+const countIncomes = `-- name: CountIncomes :one
+SELECT count(*) FROM income
+`
+
+func (q *Queries) CountIncomes(ctx context.Context) (int64, error) {
+	row := q.db.QueryRow(ctx, countIncomes)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countSearchIncomes = `-- name: CountSearchIncomes :one
+SELECT count(*) FROM income
+WHERE search @@ websearch_to_tsquery('simple', $1) OR $1 <% payee
+`
+
+func (q *Queries) CountSearchIncomes(ctx context.Context, query string) (int64, error) {
+	row := q.db.QueryRow(ctx, countSearchIncomes, query)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createIncome = `-- name: CreateIncome :one
 INSERT INTO income (payee, amount, project_id) VALUES ($1, $2, $3) RETURNING id, payee, amount, project_id, created_at, updated_at
 `
@@ -72,7 +96,7 @@ func (q *Queries) GetIncome(ctx context.Context, id uuid.UUID) (Income, error) {
 }
 
 const listIncomes = `-- name: ListIncomes :many
-SELECT id, payee, amount, project_id, created_at, updated_at FROM income ORDER BY id OFFSET $1 LIMIT $2
+SELECT id, payee, amount, project_id, created_at, updated_at FROM income ORDER BY created_at, id OFFSET $1 LIMIT $2
 `
 
 type ListIncomesParams struct {
@@ -107,18 +131,206 @@ func (q *Queries) ListIncomes(ctx context.Context, arg ListIncomesParams) ([]Inc
 	return items, nil
 }
 
+const listIncomesAfter = `-- name: ListIncomesAfter :many
+SELECT id, payee, amount, project_id, created_at, updated_at FROM income
+WHERE (created_at, id) > ($1::timestamptz, $2::uuid)
+ORDER BY created_at, id
+LIMIT $3
+`
+
+type ListIncomesAfterParams struct {
+	CreatedAt time.Time `json:"created_at"`
+	ID        uuid.UUID `json:"id"`
+	Limit     int32     `json:"limit"`
+}
+
+func (q *Queries) ListIncomesAfter(ctx context.Context, arg ListIncomesAfterParams) ([]Income, error) {
+	rows, err := q.db.Query(ctx, listIncomesAfter, arg.CreatedAt, arg.ID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Income{}
+	for rows.Next() {
+		var i Income
+		if err := rows.Scan(
+			&i.ID,
+			&i.Payee,
+			&i.Amount,
+			&i.ProjectID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listIncomesBefore = `-- name: ListIncomesBefore :many
+SELECT id, payee, amount, project_id, created_at, updated_at FROM income
+WHERE (created_at, id) < ($1::timestamptz, $2::uuid)
+ORDER BY created_at DESC, id DESC
+LIMIT $3
+`
+
+type ListIncomesBeforeParams struct {
+	CreatedAt time.Time `json:"created_at"`
+	ID        uuid.UUID `json:"id"`
+	Limit     int32     `json:"limit"`
+}
+
+func (q *Queries) ListIncomesBefore(ctx context.Context, arg ListIncomesBeforeParams) ([]Income, error) {
+	rows, err := q.db.Query(ctx, listIncomesBefore, arg.CreatedAt, arg.ID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Income{}
+	for rows.Next() {
+		var i Income
+		if err := rows.Scan(
+			&i.ID,
+			&i.Payee,
+			&i.Amount,
+			&i.ProjectID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const searchIncomes = `-- name: SearchIncomes :many
-SELECT id, payee, amount, project_id, created_at, updated_at FROM income WHERE payee ILIKE $1 ORDER BY id OFFSET $2 LIMIT $3
+WITH q AS (
+    SELECT websearch_to_tsquery('simple', $1::text) AS tsq
+), hits AS (
+    SELECT id, payee, amount, project_id, created_at, updated_at,
+        (ts_rank(search, q.tsq) + word_similarity($1, payee))::real AS rank
+    FROM income, q
+    WHERE search @@ q.tsq OR $1 <% payee
+)
+SELECT id, payee, amount, project_id, created_at, updated_at FROM hits
+ORDER BY rank DESC, created_at, id
+OFFSET $2 LIMIT $3
 `
 
 type SearchIncomesParams struct {
-	Payee  string `json:"payee"`
+	Query  string `json:"query"`
 	Offset int32  `json:"offset"`
 	Limit  int32  `json:"limit"`
 }
 
 func (q *Queries) SearchIncomes(ctx context.Context, arg SearchIncomesParams) ([]Income, error) {
-	rows, err := q.db.Query(ctx, searchIncomes, arg.Payee, arg.Offset, arg.Limit)
+	rows, err := q.db.Query(ctx, searchIncomes, arg.Query, arg.Offset, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Income{}
+	for rows.Next() {
+		var i Income
+		if err := rows.Scan(
+			&i.ID,
+			&i.Payee,
+			&i.Amount,
+			&i.ProjectID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchIncomesAfter = `-- name: SearchIncomesAfter :many
+WITH q AS (
+    SELECT websearch_to_tsquery('simple', $1::text) AS tsq
+), hits AS (
+    SELECT id, payee, amount, project_id, created_at, updated_at,
+        (ts_rank(search, q.tsq) + word_similarity($1, payee))::real AS rank
+    FROM income, q
+    WHERE search @@ q.tsq OR $1 <% payee
+)
+SELECT id, payee, amount, project_id, created_at, updated_at FROM hits
+WHERE (-rank, created_at, id) > (SELECT -rank, $2::timestamptz, $3::uuid FROM hits WHERE id = $3::uuid)
+ORDER BY rank DESC, created_at, id
+LIMIT $4
+`
+
+type SearchIncomesAfterParams struct {
+	Query     string    `json:"query"`
+	CreatedAt time.Time `json:"created_at"`
+	ID        uuid.UUID `json:"id"`
+	Limit     int32     `json:"limit"`
+}
+
+func (q *Queries) SearchIncomesAfter(ctx context.Context, arg SearchIncomesAfterParams) ([]Income, error) {
+	rows, err := q.db.Query(ctx, searchIncomesAfter, arg.Query, arg.CreatedAt, arg.ID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Income{}
+	for rows.Next() {
+		var i Income
+		if err := rows.Scan(
+			&i.ID,
+			&i.Payee,
+			&i.Amount,
+			&i.ProjectID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchIncomesBefore = `-- name: SearchIncomesBefore :many
+WITH q AS (
+    SELECT websearch_to_tsquery('simple', $1::text) AS tsq
+), hits AS (
+    SELECT id, payee, amount, project_id, created_at, updated_at,
+        (ts_rank(search, q.tsq) + word_similarity($1, payee))::real AS rank
+    FROM income, q
+    WHERE search @@ q.tsq OR $1 <% payee
+)
+SELECT id, payee, amount, project_id, created_at, updated_at FROM hits
+WHERE (-rank, created_at, id) < (SELECT -rank, $2::timestamptz, $3::uuid FROM hits WHERE id = $3::uuid)
+ORDER BY rank, created_at DESC, id DESC
+LIMIT $4
+`
+
+type SearchIncomesBeforeParams struct {
+	Query     string    `json:"query"`
+	CreatedAt time.Time `json:"created_at"`
+	ID        uuid.UUID `json:"id"`
+	Limit     int32     `json:"limit"`
+}
+
+func (q *Queries) SearchIncomesBefore(ctx context.Context, arg SearchIncomesBeforeParams) ([]Income, error) {
+	rows, err := q.db.Query(ctx, searchIncomesBefore, arg.Query, arg.CreatedAt, arg.ID, arg.Limit)
 	if err != nil {
 		return nil, err
 	}
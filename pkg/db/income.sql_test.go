@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/lushenle/plam/pkg/util"
 	"github.com/stretchr/testify/require"
@@ -87,7 +88,7 @@ func TestSearchIncomes(t *testing.T) {
 	require.NotZero(t, income.CreatedAt)
 
 	arg2 := SearchIncomesParams{
-		Payee:  arg.Payee,
+		Query:  arg.Payee,
 		Offset: 0,
 		Limit:  5,
 	}
@@ -100,6 +101,13 @@ func TestSearchIncomes(t *testing.T) {
 	require.Equal(t, income.Amount, income2[0].Amount)
 	require.Equal(t, income.ProjectID, income2[0].ProjectID)
 	require.WithinDuration(t, income.CreatedAt, income2[0].CreatedAt, 0)
+
+	// A partial payee is matched by trigram similarity
+	arg2.Query = arg.Payee[:12]
+	income2, err = testStore.SearchIncomes(context.Background(), arg2)
+	require.NoError(t, err)
+	require.Len(t, income2, 1)
+	require.Equal(t, income.ID, income2[0].ID)
 }
 
 func TestDeleteIncome(t *testing.T) {
@@ -120,3 +128,71 @@ func TestDeleteIncome(t *testing.T) {
 	require.ErrorIs(t, err, ErrRecordNotFound)
 	require.Empty(t, income3)
 }
+
+func TestListIncomesCursor(t *testing.T) {
+	for i := 0; i < 3; i++ {
+		createRandomIncome(t)
+	}
+
+	incomes, err := testStore.ListIncomes(context.Background(), ListIncomesParams{Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, incomes, 2)
+
+	after, err := testStore.ListIncomesAfter(context.Background(), ListIncomesAfterParams{
+		CreatedAt: incomes[0].CreatedAt,
+		ID:        incomes[0].ID,
+		Limit:     1,
+	})
+	require.NoError(t, err)
+	require.Len(t, after, 1)
+	require.Equal(t, incomes[1].ID, after[0].ID)
+
+	before, err := testStore.ListIncomesBefore(context.Background(), ListIncomesBeforeParams{
+		CreatedAt: incomes[1].CreatedAt,
+		ID:        incomes[1].ID,
+		Limit:     1,
+	})
+	require.NoError(t, err)
+	require.Len(t, before, 1)
+	require.Equal(t, incomes[0].ID, before[0].ID)
+}
+
+func TestSearchIncomesCursor(t *testing.T) {
+	income := createRandomIncome(t)
+
+	after, err := testStore.SearchIncomesAfter(context.Background(), SearchIncomesAfterParams{
+		Query:     income.Payee,
+		CreatedAt: income.CreatedAt,
+		ID:        income.ID,
+		Limit:     5,
+	})
+	require.NoError(t, err)
+	require.Empty(t, after)
+
+	before, err := testStore.SearchIncomesBefore(context.Background(), SearchIncomesBeforeParams{
+		Query:     income.Payee,
+		CreatedAt: income.CreatedAt.Add(time.Second),
+		ID:        income.ID,
+		Limit:     5,
+	})
+	require.NoError(t, err)
+	require.Len(t, before, 1)
+	require.Equal(t, income.ID, before[0].ID)
+}
+
+func TestCountIncomes(t *testing.T) {
+	createRandomIncome(t)
+
+	count, err := testStore.CountIncomes(context.Background())
+	require.NoError(t, err)
+	require.Positive(t, count)
+
+	income := createRandomIncome(t)
+	count2, err := testStore.CountIncomes(context.Background())
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, count2, count+1)
+
+	count, err = testStore.CountSearchIncomes(context.Background(), income.Payee)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), count)
+}
@@ -0,0 +1,82 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lushenle/plam/pkg/util"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomInvitation(t *testing.T, expiresAt time.Time) Invitation {
+	admin := createRandomUser(t)
+
+	arg := CreateInvitationParams{
+		Code:      util.RandomString(32),
+		Email:     util.RandomEmail(),
+		Role:      util.RoleAdmin,
+		CreatedBy: admin.Username,
+		ExpiresAt: expiresAt,
+	}
+
+	invitation, err := testStore.CreateInvitation(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotEmpty(t, invitation)
+
+	require.Equal(t, arg.Code, invitation.Code)
+	require.Equal(t, arg.Email, invitation.Email)
+	require.Equal(t, arg.Role, invitation.Role)
+	require.Equal(t, arg.CreatedBy, invitation.CreatedBy)
+	require.Empty(t, invitation.UsedBy)
+	require.WithinDuration(t, arg.ExpiresAt, invitation.ExpiresAt, time.Second)
+	require.NotZero(t, invitation.CreatedAt)
+
+	return invitation
+}
+
+func TestCreateInvitation(t *testing.T) {
+	createRandomInvitation(t, time.Now().Add(time.Hour))
+}
+
+func TestConsumeInvitation(t *testing.T) {
+	invitation1 := createRandomInvitation(t, time.Now().Add(time.Hour))
+	user := createRandomUser(t)
+
+	arg := ConsumeInvitationParams{
+		Code:   invitation1.Code,
+		Email:  invitation1.Email,
+		UsedBy: user.Username,
+	}
+	invitation2, err := testStore.ConsumeInvitation(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, invitation1.Code, invitation2.Code)
+	require.Equal(t, user.Username, invitation2.UsedBy)
+	require.WithinDuration(t, time.Now(), invitation2.UsedAt, time.Second)
+
+	// Invitations are single-use
+	_, err = testStore.ConsumeInvitation(context.Background(), arg)
+	require.ErrorIs(t, err, ErrRecordNotFound)
+}
+
+func TestConsumeInvitationExpired(t *testing.T) {
+	invitation := createRandomInvitation(t, time.Now().Add(-time.Minute))
+
+	_, err := testStore.ConsumeInvitation(context.Background(), ConsumeInvitationParams{
+		Code:   invitation.Code,
+		Email:  invitation.Email,
+		UsedBy: util.RandomString(6),
+	})
+	require.ErrorIs(t, err, ErrRecordNotFound)
+}
+
+func TestConsumeInvitationWrongEmail(t *testing.T) {
+	invitation := createRandomInvitation(t, time.Now().Add(time.Hour))
+
+	_, err := testStore.ConsumeInvitation(context.Background(), ConsumeInvitationParams{
+		Code:   invitation.Code,
+		Email:  util.RandomEmail(),
+		UsedBy: util.RandomString(6),
+	})
+	require.ErrorIs(t, err, ErrRecordNotFound)
+}
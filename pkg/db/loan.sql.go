@@ -7,10 +7,34 @@ package db
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 )
 
+const countLoans = `-- name: CountLoans :one
+SELECT count(*) FROM loan
+`
+
+func (q *Queries) CountLoans(ctx context.Context) (int64, error) {
+	row := q.db.QueryRow(ctx, countLoans)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countSearchLoans = `-- name: CountSearchLoans :one
+SELECT count(*) FROM loan
+WHERE search @@ websearch_to_tsquery('simple', $1) OR $1 <% borrower
+`
+
+func (q *Queries) CountSearchLoans(ctx context.Context, query string) (int64, error) {
+	row := q.db.QueryRow(ctx, countSearchLoans, query)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createLoan = `-- name: CreateLoan :one
 INSERT INTO loan (borrower, amount, subject) VALUES ($1, $2, $3) RETURNING id, borrower, amount, subject, created_at, updated_at
 `
@@ -72,7 +96,7 @@ func (q *Queries) GetLoan(ctx context.Context, id uuid.UUID) (Loan, error) {
 }
 
 const listLoans = `-- name: ListLoans :many
-SELECT id, borrower, amount, subject, created_at, updated_at FROM loan ORDER BY id OFFSET $1 LIMIT $2
+SELECT id, borrower, amount, subject, created_at, updated_at FROM loan ORDER BY created_at, id OFFSET $1 LIMIT $2
 `
 
 type ListLoansParams struct {
@@ -107,18 +131,206 @@ func (q *Queries) ListLoans(ctx context.Context, arg ListLoansParams) ([]Loan, e
 	return items, nil
 }
 
+const listLoansAfter = `-- name: ListLoansAfter :many
+SELECT id, borrower, amount, subject, created_at, updated_at FROM loan
+WHERE (created_at, id) > ($1::timestamptz, $2::uuid)
+ORDER BY created_at, id
+LIMIT $3
+`
+
+type ListLoansAfterParams struct {
+	CreatedAt time.Time `json:"created_at"`
+	ID        uuid.UUID `json:"id"`
+	Limit     int32     `json:"limit"`
+}
+
+func (q *Queries) ListLoansAfter(ctx context.Context, arg ListLoansAfterParams) ([]Loan, error) {
+	rows, err := q.db.Query(ctx, listLoansAfter, arg.CreatedAt, arg.ID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Loan{}
+	for rows.Next() {
+		var i Loan
+		if err := rows.Scan(
+			&i.ID,
+			&i.Borrower,
+			&i.Amount,
+			&i.Subject,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listLoansBefore = `-- name: ListLoansBefore :many
+SELECT id, borrower, amount, subject, created_at, updated_at FROM loan
+WHERE (created_at, id) < ($1::timestamptz, $2::uuid)
+ORDER BY created_at DESC, id DESC
+LIMIT $3
+`
+
+type ListLoansBeforeParams struct {
+	CreatedAt time.Time `json:"created_at"`
+	ID        uuid.UUID `json:"id"`
+	Limit     int32     `json:"limit"`
+}
+
+func (q *Queries) ListLoansBefore(ctx context.Context, arg ListLoansBeforeParams) ([]Loan, error) {
+	rows, err := q.db.Query(ctx, listLoansBefore, arg.CreatedAt, arg.ID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Loan{}
+	for rows.Next() {
+		var i Loan
+		if err := rows.Scan(
+			&i.ID,
+			&i.Borrower,
+			&i.Amount,
+			&i.Subject,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const searchLoans = `-- name: SearchLoans :many
-SELECT id, borrower, amount, subject, created_at, updated_at FROM loan WHERE borrower ILIKE $1 ORDER BY id OFFSET $2 LIMIT $3
+WITH q AS (
+    SELECT websearch_to_tsquery('simple', $1::text) AS tsq
+), hits AS (
+    SELECT id, borrower, amount, subject, created_at, updated_at,
+        (ts_rank(search, q.tsq) + word_similarity($1, borrower))::real AS rank
+    FROM loan, q
+    WHERE search @@ q.tsq OR $1 <% borrower
+)
+SELECT id, borrower, amount, subject, created_at, updated_at FROM hits
+ORDER BY rank DESC, created_at, id
+OFFSET $2 LIMIT $3
 `
 
 type SearchLoansParams struct {
-	Borrower string `json:"borrower"`
-	Offset   int32  `json:"offset"`
-	Limit    int32  `json:"limit"`
+	Query  string `json:"query"`
+	Offset int32  `json:"offset"`
+	Limit  int32  `json:"limit"`
 }
 
 func (q *Queries) SearchLoans(ctx context.Context, arg SearchLoansParams) ([]Loan, error) {
-	rows, err := q.db.Query(ctx, searchLoans, arg.Borrower, arg.Offset, arg.Limit)
+	rows, err := q.db.Query(ctx, searchLoans, arg.Query, arg.Offset, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Loan{}
+	for rows.Next() {
+		var i Loan
+		if err := rows.Scan(
+			&i.ID,
+			&i.Borrower,
+			&i.Amount,
+			&i.Subject,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchLoansAfter = `-- name: SearchLoansAfter :many
+WITH q AS (
+    SELECT websearch_to_tsquery('simple', $1::text) AS tsq
+), hits AS (
+    SELECT id, borrower, amount, subject, created_at, updated_at,
+        (ts_rank(search, q.tsq) + word_similarity($1, borrower))::real AS rank
+    FROM loan, q
+    WHERE search @@ q.tsq OR $1 <% borrower
+)
+SELECT id, borrower, amount, subject, created_at, updated_at FROM hits
+WHERE (-rank, created_at, id) > (SELECT -rank, $2::timestamptz, $3::uuid FROM hits WHERE id = $3::uuid)
+ORDER BY rank DESC, created_at, id
+LIMIT $4
+`
+
+type SearchLoansAfterParams struct {
+	Query     string    `json:"query"`
+	CreatedAt time.Time `json:"created_at"`
+	ID        uuid.UUID `json:"id"`
+	Limit     int32     `json:"limit"`
+}
+
+func (q *Queries) SearchLoansAfter(ctx context.Context, arg SearchLoansAfterParams) ([]Loan, error) {
+	rows, err := q.db.Query(ctx, searchLoansAfter, arg.Query, arg.CreatedAt, arg.ID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Loan{}
+	for rows.Next() {
+		var i Loan
+		if err := rows.Scan(
+			&i.ID,
+			&i.Borrower,
+			&i.Amount,
+			&i.Subject,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchLoansBefore = `-- name: SearchLoansBefore :many
+WITH q AS (
+    SELECT websearch_to_tsquery('simple', $1::text) AS tsq
+), hits AS (
+    SELECT id, borrower, amount, subject, created_at, updated_at,
+        (ts_rank(search, q.tsq) + word_similarity($1, borrower))::real AS rank
+    FROM loan, q
+    WHERE search @@ q.tsq OR $1 <% borrower
+)
+SELECT id, borrower, amount, subject, created_at, updated_at FROM hits
+WHERE (-rank, created_at, id) < (SELECT -rank, $2::timestamptz, $3::uuid FROM hits WHERE id = $3::uuid)
+ORDER BY rank, created_at DESC, id DESC
+LIMIT $4
+`
+
+type SearchLoansBeforeParams struct {
+	Query     string    `json:"query"`
+	CreatedAt time.Time `json:"created_at"`
+	ID        uuid.UUID `json:"id"`
+	Limit     int32     `json:"limit"`
+}
+
+func (q *Queries) SearchLoansBefore(ctx context.Context, arg SearchLoansBeforeParams) ([]Loan, error) {
+	rows, err := q.db.Query(ctx, searchLoansBefore, arg.Query, arg.CreatedAt, arg.ID, arg.Limit)
 	if err != nil {
 		return nil, err
 	}
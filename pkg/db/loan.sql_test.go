@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/lushenle/plam/pkg/util"
 	"github.com/stretchr/testify/require"
@@ -75,9 +76,9 @@ func TestSearchLoan(t *testing.T) {
 	require.NotZero(t, loan.CreatedAt)
 
 	arg2 := SearchLoansParams{
-		Borrower: arg.Borrower,
-		Offset:   0,
-		Limit:    5,
+		Query:  arg.Borrower,
+		Offset: 0,
+		Limit:  5,
 	}
 	loans, err := testStore.SearchLoans(context.Background(), arg2)
 	require.NoError(t, err)
@@ -105,3 +106,71 @@ func TestDeleteLoan(t *testing.T) {
 	require.ErrorIs(t, err, ErrRecordNotFound)
 	require.Empty(t, loan3)
 }
+
+func TestListLoansCursor(t *testing.T) {
+	for i := 0; i < 3; i++ {
+		createRandomLoan(t)
+	}
+
+	loans, err := testStore.ListLoans(context.Background(), ListLoansParams{Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, loans, 2)
+
+	after, err := testStore.ListLoansAfter(context.Background(), ListLoansAfterParams{
+		CreatedAt: loans[0].CreatedAt,
+		ID:        loans[0].ID,
+		Limit:     1,
+	})
+	require.NoError(t, err)
+	require.Len(t, after, 1)
+	require.Equal(t, loans[1].ID, after[0].ID)
+
+	before, err := testStore.ListLoansBefore(context.Background(), ListLoansBeforeParams{
+		CreatedAt: loans[1].CreatedAt,
+		ID:        loans[1].ID,
+		Limit:     1,
+	})
+	require.NoError(t, err)
+	require.Len(t, before, 1)
+	require.Equal(t, loans[0].ID, before[0].ID)
+}
+
+func TestSearchLoansCursor(t *testing.T) {
+	loan := createRandomLoan(t)
+
+	after, err := testStore.SearchLoansAfter(context.Background(), SearchLoansAfterParams{
+		Query:     loan.Borrower,
+		CreatedAt: loan.CreatedAt,
+		ID:        loan.ID,
+		Limit:     5,
+	})
+	require.NoError(t, err)
+	require.Empty(t, after)
+
+	before, err := testStore.SearchLoansBefore(context.Background(), SearchLoansBeforeParams{
+		Query:     loan.Borrower,
+		CreatedAt: loan.CreatedAt.Add(time.Second),
+		ID:        loan.ID,
+		Limit:     5,
+	})
+	require.NoError(t, err)
+	require.Len(t, before, 1)
+	require.Equal(t, loan.ID, before[0].ID)
+}
+
+func TestCountLoans(t *testing.T) {
+	createRandomLoan(t)
+
+	count, err := testStore.CountLoans(context.Background())
+	require.NoError(t, err)
+	require.Positive(t, count)
+
+	loan := createRandomLoan(t)
+	count2, err := testStore.CountLoans(context.Background())
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, count2, count+1)
+
+	count, err = testStore.CountSearchLoans(context.Background(), loan.Borrower)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), count)
+}
@@ -36,6 +36,216 @@ func (m *MockStore) EXPECT() *MockStoreMockRecorder {
 	return m.recorder
 }
 
+// ChangePasswordTx mocks base method.
+func (m *MockStore) ChangePasswordTx(arg0 context.Context, arg1 db.ChangePasswordTxParams) (db.ChangePasswordTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangePasswordTx", arg0, arg1)
+	ret0, _ := ret[0].(db.ChangePasswordTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ChangePasswordTx indicates an expected call of ChangePasswordTx.
+func (mr *MockStoreMockRecorder) ChangePasswordTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangePasswordTx", reflect.TypeOf((*MockStore)(nil).ChangePasswordTx), arg0, arg1)
+}
+
+// ConsumeInvitation mocks base method.
+func (m *MockStore) ConsumeInvitation(arg0 context.Context, arg1 db.ConsumeInvitationParams) (db.Invitation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConsumeInvitation", arg0, arg1)
+	ret0, _ := ret[0].(db.Invitation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConsumeInvitation indicates an expected call of ConsumeInvitation.
+func (mr *MockStoreMockRecorder) ConsumeInvitation(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConsumeInvitation", reflect.TypeOf((*MockStore)(nil).ConsumeInvitation), arg0, arg1)
+}
+
+// CountFilterIncomes mocks base method.
+func (m *MockStore) CountFilterIncomes(arg0 context.Context, arg1 db.ListFilter) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountFilterIncomes", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountFilterIncomes indicates an expected call of CountFilterIncomes.
+func (mr *MockStoreMockRecorder) CountFilterIncomes(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountFilterIncomes", reflect.TypeOf((*MockStore)(nil).CountFilterIncomes), arg0, arg1)
+}
+
+// CountFilterLoans mocks base method.
+func (m *MockStore) CountFilterLoans(arg0 context.Context, arg1 db.ListFilter) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountFilterLoans", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountFilterLoans indicates an expected call of CountFilterLoans.
+func (mr *MockStoreMockRecorder) CountFilterLoans(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountFilterLoans", reflect.TypeOf((*MockStore)(nil).CountFilterLoans), arg0, arg1)
+}
+
+// CountFilterPayOuts mocks base method.
+func (m *MockStore) CountFilterPayOuts(arg0 context.Context, arg1 db.ListFilter) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountFilterPayOuts", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountFilterPayOuts indicates an expected call of CountFilterPayOuts.
+func (mr *MockStoreMockRecorder) CountFilterPayOuts(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountFilterPayOuts", reflect.TypeOf((*MockStore)(nil).CountFilterPayOuts), arg0, arg1)
+}
+
+// CountFilterProjects mocks base method.
+func (m *MockStore) CountFilterProjects(arg0 context.Context, arg1 db.ListFilter) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountFilterProjects", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountFilterProjects indicates an expected call of CountFilterProjects.
+func (mr *MockStoreMockRecorder) CountFilterProjects(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountFilterProjects", reflect.TypeOf((*MockStore)(nil).CountFilterProjects), arg0, arg1)
+}
+
+// CountIncomes mocks base method.
+func (m *MockStore) CountIncomes(arg0 context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountIncomes", arg0)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountIncomes indicates an expected call of CountIncomes.
+func (mr *MockStoreMockRecorder) CountIncomes(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountIncomes", reflect.TypeOf((*MockStore)(nil).CountIncomes), arg0)
+}
+
+// CountLoans mocks base method.
+func (m *MockStore) CountLoans(arg0 context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountLoans", arg0)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountLoans indicates an expected call of CountLoans.
+func (mr *MockStoreMockRecorder) CountLoans(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountLoans", reflect.TypeOf((*MockStore)(nil).CountLoans), arg0)
+}
+
+// CountPayOuts mocks base method.
+func (m *MockStore) CountPayOuts(arg0 context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountPayOuts", arg0)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountPayOuts indicates an expected call of CountPayOuts.
+func (mr *MockStoreMockRecorder) CountPayOuts(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountPayOuts", reflect.TypeOf((*MockStore)(nil).CountPayOuts), arg0)
+}
+
+// CountProjects mocks base method.
+func (m *MockStore) CountProjects(arg0 context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountProjects", arg0)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountProjects indicates an expected call of CountProjects.
+func (mr *MockStoreMockRecorder) CountProjects(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountProjects", reflect.TypeOf((*MockStore)(nil).CountProjects), arg0)
+}
+
+// CountSearchIncomes mocks base method.
+func (m *MockStore) CountSearchIncomes(arg0 context.Context, arg1 string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountSearchIncomes", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountSearchIncomes indicates an expected call of CountSearchIncomes.
+func (mr *MockStoreMockRecorder) CountSearchIncomes(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountSearchIncomes", reflect.TypeOf((*MockStore)(nil).CountSearchIncomes), arg0, arg1)
+}
+
+// CountSearchLoans mocks base method.
+func (m *MockStore) CountSearchLoans(arg0 context.Context, arg1 string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountSearchLoans", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountSearchLoans indicates an expected call of CountSearchLoans.
+func (mr *MockStoreMockRecorder) CountSearchLoans(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountSearchLoans", reflect.TypeOf((*MockStore)(nil).CountSearchLoans), arg0, arg1)
+}
+
+// CountSearchPayOuts mocks base method.
+func (m *MockStore) CountSearchPayOuts(arg0 context.Context, arg1 string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountSearchPayOuts", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountSearchPayOuts indicates an expected call of CountSearchPayOuts.
+func (mr *MockStoreMockRecorder) CountSearchPayOuts(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountSearchPayOuts", reflect.TypeOf((*MockStore)(nil).CountSearchPayOuts), arg0, arg1)
+}
+
+// CountSearchProjects mocks base method.
+func (m *MockStore) CountSearchProjects(arg0 context.Context, arg1 string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountSearchProjects", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountSearchProjects indicates an expected call of CountSearchProjects.
+func (mr *MockStoreMockRecorder) CountSearchProjects(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountSearchProjects", reflect.TypeOf((*MockStore)(nil).CountSearchProjects), arg0, arg1)
+}
+
 // CreateIncome mocks base method.
 func (m *MockStore) CreateIncome(arg0 context.Context, arg1 db.CreateIncomeParams) (db.Income, error) {
 	m.ctrl.T.Helper()
@@ -51,6 +261,21 @@ func (mr *MockStoreMockRecorder) CreateIncome(arg0, arg1 interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateIncome", reflect.TypeOf((*MockStore)(nil).CreateIncome), arg0, arg1)
 }
 
+// CreateInvitation mocks base method.
+func (m *MockStore) CreateInvitation(arg0 context.Context, arg1 db.CreateInvitationParams) (db.Invitation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateInvitation", arg0, arg1)
+	ret0, _ := ret[0].(db.Invitation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateInvitation indicates an expected call of CreateInvitation.
+func (mr *MockStoreMockRecorder) CreateInvitation(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateInvitation", reflect.TypeOf((*MockStore)(nil).CreateInvitation), arg0, arg1)
+}
+
 // CreateLoan mocks base method.
 func (m *MockStore) CreateLoan(arg0 context.Context, arg1 db.CreateLoanParams) (db.Loan, error) {
 	m.ctrl.T.Helper()
@@ -66,6 +291,21 @@ func (mr *MockStoreMockRecorder) CreateLoan(arg0, arg1 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLoan", reflect.TypeOf((*MockStore)(nil).CreateLoan), arg0, arg1)
 }
 
+// CreatePasswordHistory mocks base method.
+func (m *MockStore) CreatePasswordHistory(arg0 context.Context, arg1 db.CreatePasswordHistoryParams) (db.PasswordHistory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePasswordHistory", arg0, arg1)
+	ret0, _ := ret[0].(db.PasswordHistory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePasswordHistory indicates an expected call of CreatePasswordHistory.
+func (mr *MockStoreMockRecorder) CreatePasswordHistory(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePasswordHistory", reflect.TypeOf((*MockStore)(nil).CreatePasswordHistory), arg0, arg1)
+}
+
 // CreatePayOut mocks base method.
 func (m *MockStore) CreatePayOut(arg0 context.Context, arg1 db.CreatePayOutParams) (db.PayOut, error) {
 	m.ctrl.T.Helper()
@@ -111,6 +351,36 @@ func (mr *MockStoreMockRecorder) CreateUser(arg0, arg1 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockStore)(nil).CreateUser), arg0, arg1)
 }
 
+// CreateUserIdentity mocks base method.
+func (m *MockStore) CreateUserIdentity(arg0 context.Context, arg1 db.CreateUserIdentityParams) (db.UserIdentity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUserIdentity", arg0, arg1)
+	ret0, _ := ret[0].(db.UserIdentity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUserIdentity indicates an expected call of CreateUserIdentity.
+func (mr *MockStoreMockRecorder) CreateUserIdentity(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUserIdentity", reflect.TypeOf((*MockStore)(nil).CreateUserIdentity), arg0, arg1)
+}
+
+// CreateUserWithInvitationTx mocks base method.
+func (m *MockStore) CreateUserWithInvitationTx(arg0 context.Context, arg1 db.CreateUserWithInvitationTxParams) (db.CreateUserWithInvitationTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUserWithInvitationTx", arg0, arg1)
+	ret0, _ := ret[0].(db.CreateUserWithInvitationTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUserWithInvitationTx indicates an expected call of CreateUserWithInvitationTx.
+func (mr *MockStoreMockRecorder) CreateUserWithInvitationTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUserWithInvitationTx", reflect.TypeOf((*MockStore)(nil).CreateUserWithInvitationTx), arg0, arg1)
+}
+
 // DeleteIncome mocks base method.
 func (m *MockStore) DeleteIncome(arg0 context.Context, arg1 uuid.UUID) (db.Income, error) {
 	m.ctrl.T.Helper()
@@ -171,6 +441,66 @@ func (mr *MockStoreMockRecorder) DeleteProject(arg0, arg1 interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteProject", reflect.TypeOf((*MockStore)(nil).DeleteProject), arg0, arg1)
 }
 
+// FilterIncomes mocks base method.
+func (m *MockStore) FilterIncomes(arg0 context.Context, arg1 db.FilterParams) ([]db.Income, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FilterIncomes", arg0, arg1)
+	ret0, _ := ret[0].([]db.Income)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FilterIncomes indicates an expected call of FilterIncomes.
+func (mr *MockStoreMockRecorder) FilterIncomes(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FilterIncomes", reflect.TypeOf((*MockStore)(nil).FilterIncomes), arg0, arg1)
+}
+
+// FilterLoans mocks base method.
+func (m *MockStore) FilterLoans(arg0 context.Context, arg1 db.FilterParams) ([]db.Loan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FilterLoans", arg0, arg1)
+	ret0, _ := ret[0].([]db.Loan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FilterLoans indicates an expected call of FilterLoans.
+func (mr *MockStoreMockRecorder) FilterLoans(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FilterLoans", reflect.TypeOf((*MockStore)(nil).FilterLoans), arg0, arg1)
+}
+
+// FilterPayOuts mocks base method.
+func (m *MockStore) FilterPayOuts(arg0 context.Context, arg1 db.FilterParams) ([]db.PayOut, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FilterPayOuts", arg0, arg1)
+	ret0, _ := ret[0].([]db.PayOut)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FilterPayOuts indicates an expected call of FilterPayOuts.
+func (mr *MockStoreMockRecorder) FilterPayOuts(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FilterPayOuts", reflect.TypeOf((*MockStore)(nil).FilterPayOuts), arg0, arg1)
+}
+
+// FilterProjects mocks base method.
+func (m *MockStore) FilterProjects(arg0 context.Context, arg1 db.FilterParams) ([]db.Project, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FilterProjects", arg0, arg1)
+	ret0, _ := ret[0].([]db.Project)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FilterProjects indicates an expected call of FilterProjects.
+func (mr *MockStoreMockRecorder) FilterProjects(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FilterProjects", reflect.TypeOf((*MockStore)(nil).FilterProjects), arg0, arg1)
+}
+
 // GetIncome mocks base method.
 func (m *MockStore) GetIncome(arg0 context.Context, arg1 uuid.UUID) (db.Income, error) {
 	m.ctrl.T.Helper()
@@ -246,6 +576,36 @@ func (mr *MockStoreMockRecorder) GetUser(arg0, arg1 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockStore)(nil).GetUser), arg0, arg1)
 }
 
+// GetUserByEmail mocks base method.
+func (m *MockStore) GetUserByEmail(arg0 context.Context, arg1 string) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserByEmail", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserByEmail indicates an expected call of GetUserByEmail.
+func (mr *MockStoreMockRecorder) GetUserByEmail(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByEmail", reflect.TypeOf((*MockStore)(nil).GetUserByEmail), arg0, arg1)
+}
+
+// GetUserIdentity mocks base method.
+func (m *MockStore) GetUserIdentity(arg0 context.Context, arg1 db.GetUserIdentityParams) (db.UserIdentity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserIdentity", arg0, arg1)
+	ret0, _ := ret[0].(db.UserIdentity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserIdentity indicates an expected call of GetUserIdentity.
+func (mr *MockStoreMockRecorder) GetUserIdentity(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserIdentity", reflect.TypeOf((*MockStore)(nil).GetUserIdentity), arg0, arg1)
+}
+
 // ListIncomes mocks base method.
 func (m *MockStore) ListIncomes(arg0 context.Context, arg1 db.ListIncomesParams) ([]db.Income, error) {
 	m.ctrl.T.Helper()
@@ -261,6 +621,36 @@ func (mr *MockStoreMockRecorder) ListIncomes(arg0, arg1 interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListIncomes", reflect.TypeOf((*MockStore)(nil).ListIncomes), arg0, arg1)
 }
 
+// ListIncomesAfter mocks base method.
+func (m *MockStore) ListIncomesAfter(arg0 context.Context, arg1 db.ListIncomesAfterParams) ([]db.Income, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListIncomesAfter", arg0, arg1)
+	ret0, _ := ret[0].([]db.Income)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListIncomesAfter indicates an expected call of ListIncomesAfter.
+func (mr *MockStoreMockRecorder) ListIncomesAfter(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListIncomesAfter", reflect.TypeOf((*MockStore)(nil).ListIncomesAfter), arg0, arg1)
+}
+
+// ListIncomesBefore mocks base method.
+func (m *MockStore) ListIncomesBefore(arg0 context.Context, arg1 db.ListIncomesBeforeParams) ([]db.Income, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListIncomesBefore", arg0, arg1)
+	ret0, _ := ret[0].([]db.Income)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListIncomesBefore indicates an expected call of ListIncomesBefore.
+func (mr *MockStoreMockRecorder) ListIncomesBefore(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListIncomesBefore", reflect.TypeOf((*MockStore)(nil).ListIncomesBefore), arg0, arg1)
+}
+
 // ListLoans mocks base method.
 func (m *MockStore) ListLoans(arg0 context.Context, arg1 db.ListLoansParams) ([]db.Loan, error) {
 	m.ctrl.T.Helper()
@@ -276,6 +666,51 @@ func (mr *MockStoreMockRecorder) ListLoans(arg0, arg1 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLoans", reflect.TypeOf((*MockStore)(nil).ListLoans), arg0, arg1)
 }
 
+// ListLoansAfter mocks base method.
+func (m *MockStore) ListLoansAfter(arg0 context.Context, arg1 db.ListLoansAfterParams) ([]db.Loan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListLoansAfter", arg0, arg1)
+	ret0, _ := ret[0].([]db.Loan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListLoansAfter indicates an expected call of ListLoansAfter.
+func (mr *MockStoreMockRecorder) ListLoansAfter(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLoansAfter", reflect.TypeOf((*MockStore)(nil).ListLoansAfter), arg0, arg1)
+}
+
+// ListLoansBefore mocks base method.
+func (m *MockStore) ListLoansBefore(arg0 context.Context, arg1 db.ListLoansBeforeParams) ([]db.Loan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListLoansBefore", arg0, arg1)
+	ret0, _ := ret[0].([]db.Loan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListLoansBefore indicates an expected call of ListLoansBefore.
+func (mr *MockStoreMockRecorder) ListLoansBefore(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLoansBefore", reflect.TypeOf((*MockStore)(nil).ListLoansBefore), arg0, arg1)
+}
+
+// ListPasswordHistory mocks base method.
+func (m *MockStore) ListPasswordHistory(arg0 context.Context, arg1 db.ListPasswordHistoryParams) ([]db.PasswordHistory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPasswordHistory", arg0, arg1)
+	ret0, _ := ret[0].([]db.PasswordHistory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPasswordHistory indicates an expected call of ListPasswordHistory.
+func (mr *MockStoreMockRecorder) ListPasswordHistory(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPasswordHistory", reflect.TypeOf((*MockStore)(nil).ListPasswordHistory), arg0, arg1)
+}
+
 // ListPayOuts mocks base method.
 func (m *MockStore) ListPayOuts(arg0 context.Context, arg1 db.ListPayOutsParams) ([]db.PayOut, error) {
 	m.ctrl.T.Helper()
@@ -291,6 +726,36 @@ func (mr *MockStoreMockRecorder) ListPayOuts(arg0, arg1 interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPayOuts", reflect.TypeOf((*MockStore)(nil).ListPayOuts), arg0, arg1)
 }
 
+// ListPayOutsAfter mocks base method.
+func (m *MockStore) ListPayOutsAfter(arg0 context.Context, arg1 db.ListPayOutsAfterParams) ([]db.PayOut, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPayOutsAfter", arg0, arg1)
+	ret0, _ := ret[0].([]db.PayOut)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPayOutsAfter indicates an expected call of ListPayOutsAfter.
+func (mr *MockStoreMockRecorder) ListPayOutsAfter(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPayOutsAfter", reflect.TypeOf((*MockStore)(nil).ListPayOutsAfter), arg0, arg1)
+}
+
+// ListPayOutsBefore mocks base method.
+func (m *MockStore) ListPayOutsBefore(arg0 context.Context, arg1 db.ListPayOutsBeforeParams) ([]db.PayOut, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPayOutsBefore", arg0, arg1)
+	ret0, _ := ret[0].([]db.PayOut)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPayOutsBefore indicates an expected call of ListPayOutsBefore.
+func (mr *MockStoreMockRecorder) ListPayOutsBefore(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPayOutsBefore", reflect.TypeOf((*MockStore)(nil).ListPayOutsBefore), arg0, arg1)
+}
+
 // ListProjects mocks base method.
 func (m *MockStore) ListProjects(arg0 context.Context, arg1 db.ListProjectsParams) ([]db.Project, error) {
 	m.ctrl.T.Helper()
@@ -306,6 +771,96 @@ func (mr *MockStoreMockRecorder) ListProjects(arg0, arg1 interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListProjects", reflect.TypeOf((*MockStore)(nil).ListProjects), arg0, arg1)
 }
 
+// ListProjectsAfter mocks base method.
+func (m *MockStore) ListProjectsAfter(arg0 context.Context, arg1 db.ListProjectsAfterParams) ([]db.Project, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListProjectsAfter", arg0, arg1)
+	ret0, _ := ret[0].([]db.Project)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListProjectsAfter indicates an expected call of ListProjectsAfter.
+func (mr *MockStoreMockRecorder) ListProjectsAfter(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListProjectsAfter", reflect.TypeOf((*MockStore)(nil).ListProjectsAfter), arg0, arg1)
+}
+
+// ListProjectsBefore mocks base method.
+func (m *MockStore) ListProjectsBefore(arg0 context.Context, arg1 db.ListProjectsBeforeParams) ([]db.Project, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListProjectsBefore", arg0, arg1)
+	ret0, _ := ret[0].([]db.Project)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListProjectsBefore indicates an expected call of ListProjectsBefore.
+func (mr *MockStoreMockRecorder) ListProjectsBefore(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListProjectsBefore", reflect.TypeOf((*MockStore)(nil).ListProjectsBefore), arg0, arg1)
+}
+
+// LoginOIDCUserTx mocks base method.
+func (m *MockStore) LoginOIDCUserTx(arg0 context.Context, arg1 db.LoginOIDCUserTxParams) (db.LoginOIDCUserTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoginOIDCUserTx", arg0, arg1)
+	ret0, _ := ret[0].(db.LoginOIDCUserTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoginOIDCUserTx indicates an expected call of LoginOIDCUserTx.
+func (mr *MockStoreMockRecorder) LoginOIDCUserTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoginOIDCUserTx", reflect.TypeOf((*MockStore)(nil).LoginOIDCUserTx), arg0, arg1)
+}
+
+// MigrationVersion mocks base method.
+func (m *MockStore) MigrationVersion(arg0 context.Context) (int64, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MigrationVersion", arg0)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// MigrationVersion indicates an expected call of MigrationVersion.
+func (mr *MockStoreMockRecorder) MigrationVersion(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MigrationVersion", reflect.TypeOf((*MockStore)(nil).MigrationVersion), arg0)
+}
+
+// Ping mocks base method.
+func (m *MockStore) Ping(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Ping", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Ping indicates an expected call of Ping.
+func (mr *MockStoreMockRecorder) Ping(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ping", reflect.TypeOf((*MockStore)(nil).Ping), arg0)
+}
+
+// SearchAll mocks base method.
+func (m *MockStore) SearchAll(arg0 context.Context, arg1 db.SearchAllParams) ([]db.SearchAllRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchAll", arg0, arg1)
+	ret0, _ := ret[0].([]db.SearchAllRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchAll indicates an expected call of SearchAll.
+func (mr *MockStoreMockRecorder) SearchAll(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchAll", reflect.TypeOf((*MockStore)(nil).SearchAll), arg0, arg1)
+}
+
 // SearchIncomes mocks base method.
 func (m *MockStore) SearchIncomes(arg0 context.Context, arg1 db.SearchIncomesParams) ([]db.Income, error) {
 	m.ctrl.T.Helper()
@@ -321,6 +876,36 @@ func (mr *MockStoreMockRecorder) SearchIncomes(arg0, arg1 interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchIncomes", reflect.TypeOf((*MockStore)(nil).SearchIncomes), arg0, arg1)
 }
 
+// SearchIncomesAfter mocks base method.
+func (m *MockStore) SearchIncomesAfter(arg0 context.Context, arg1 db.SearchIncomesAfterParams) ([]db.Income, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchIncomesAfter", arg0, arg1)
+	ret0, _ := ret[0].([]db.Income)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchIncomesAfter indicates an expected call of SearchIncomesAfter.
+func (mr *MockStoreMockRecorder) SearchIncomesAfter(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchIncomesAfter", reflect.TypeOf((*MockStore)(nil).SearchIncomesAfter), arg0, arg1)
+}
+
+// SearchIncomesBefore mocks base method.
+func (m *MockStore) SearchIncomesBefore(arg0 context.Context, arg1 db.SearchIncomesBeforeParams) ([]db.Income, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchIncomesBefore", arg0, arg1)
+	ret0, _ := ret[0].([]db.Income)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchIncomesBefore indicates an expected call of SearchIncomesBefore.
+func (mr *MockStoreMockRecorder) SearchIncomesBefore(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchIncomesBefore", reflect.TypeOf((*MockStore)(nil).SearchIncomesBefore), arg0, arg1)
+}
+
 // SearchLoans mocks base method.
 func (m *MockStore) SearchLoans(arg0 context.Context, arg1 db.SearchLoansParams) ([]db.Loan, error) {
 	m.ctrl.T.Helper()
@@ -336,6 +921,36 @@ func (mr *MockStoreMockRecorder) SearchLoans(arg0, arg1 interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchLoans", reflect.TypeOf((*MockStore)(nil).SearchLoans), arg0, arg1)
 }
 
+// SearchLoansAfter mocks base method.
+func (m *MockStore) SearchLoansAfter(arg0 context.Context, arg1 db.SearchLoansAfterParams) ([]db.Loan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchLoansAfter", arg0, arg1)
+	ret0, _ := ret[0].([]db.Loan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchLoansAfter indicates an expected call of SearchLoansAfter.
+func (mr *MockStoreMockRecorder) SearchLoansAfter(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchLoansAfter", reflect.TypeOf((*MockStore)(nil).SearchLoansAfter), arg0, arg1)
+}
+
+// SearchLoansBefore mocks base method.
+func (m *MockStore) SearchLoansBefore(arg0 context.Context, arg1 db.SearchLoansBeforeParams) ([]db.Loan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchLoansBefore", arg0, arg1)
+	ret0, _ := ret[0].([]db.Loan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchLoansBefore indicates an expected call of SearchLoansBefore.
+func (mr *MockStoreMockRecorder) SearchLoansBefore(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchLoansBefore", reflect.TypeOf((*MockStore)(nil).SearchLoansBefore), arg0, arg1)
+}
+
 // SearchPayOuts mocks base method.
 func (m *MockStore) SearchPayOuts(arg0 context.Context, arg1 db.SearchPayOutsParams) ([]db.PayOut, error) {
 	m.ctrl.T.Helper()
@@ -351,6 +966,36 @@ func (mr *MockStoreMockRecorder) SearchPayOuts(arg0, arg1 interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchPayOuts", reflect.TypeOf((*MockStore)(nil).SearchPayOuts), arg0, arg1)
 }
 
+// SearchPayOutsAfter mocks base method.
+func (m *MockStore) SearchPayOutsAfter(arg0 context.Context, arg1 db.SearchPayOutsAfterParams) ([]db.PayOut, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchPayOutsAfter", arg0, arg1)
+	ret0, _ := ret[0].([]db.PayOut)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchPayOutsAfter indicates an expected call of SearchPayOutsAfter.
+func (mr *MockStoreMockRecorder) SearchPayOutsAfter(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchPayOutsAfter", reflect.TypeOf((*MockStore)(nil).SearchPayOutsAfter), arg0, arg1)
+}
+
+// SearchPayOutsBefore mocks base method.
+func (m *MockStore) SearchPayOutsBefore(arg0 context.Context, arg1 db.SearchPayOutsBeforeParams) ([]db.PayOut, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchPayOutsBefore", arg0, arg1)
+	ret0, _ := ret[0].([]db.PayOut)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchPayOutsBefore indicates an expected call of SearchPayOutsBefore.
+func (mr *MockStoreMockRecorder) SearchPayOutsBefore(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchPayOutsBefore", reflect.TypeOf((*MockStore)(nil).SearchPayOutsBefore), arg0, arg1)
+}
+
 // SearchProjects mocks base method.
 func (m *MockStore) SearchProjects(arg0 context.Context, arg1 db.SearchProjectsParams) ([]db.Project, error) {
 	m.ctrl.T.Helper()
@@ -365,3 +1010,78 @@ func (mr *MockStoreMockRecorder) SearchProjects(arg0, arg1 interface{}) *gomock.
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchProjects", reflect.TypeOf((*MockStore)(nil).SearchProjects), arg0, arg1)
 }
+
+// SearchProjectsAfter mocks base method.
+func (m *MockStore) SearchProjectsAfter(arg0 context.Context, arg1 db.SearchProjectsAfterParams) ([]db.Project, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchProjectsAfter", arg0, arg1)
+	ret0, _ := ret[0].([]db.Project)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchProjectsAfter indicates an expected call of SearchProjectsAfter.
+func (mr *MockStoreMockRecorder) SearchProjectsAfter(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchProjectsAfter", reflect.TypeOf((*MockStore)(nil).SearchProjectsAfter), arg0, arg1)
+}
+
+// SearchProjectsBefore mocks base method.
+func (m *MockStore) SearchProjectsBefore(arg0 context.Context, arg1 db.SearchProjectsBeforeParams) ([]db.Project, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchProjectsBefore", arg0, arg1)
+	ret0, _ := ret[0].([]db.Project)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchProjectsBefore indicates an expected call of SearchProjectsBefore.
+func (mr *MockStoreMockRecorder) SearchProjectsBefore(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchProjectsBefore", reflect.TypeOf((*MockStore)(nil).SearchProjectsBefore), arg0, arg1)
+}
+
+// UpdateUserLocale mocks base method.
+func (m *MockStore) UpdateUserLocale(arg0 context.Context, arg1 db.UpdateUserLocaleParams) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserLocale", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateUserLocale indicates an expected call of UpdateUserLocale.
+func (mr *MockStoreMockRecorder) UpdateUserLocale(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserLocale", reflect.TypeOf((*MockStore)(nil).UpdateUserLocale), arg0, arg1)
+}
+
+// UpdateUserPassword mocks base method.
+func (m *MockStore) UpdateUserPassword(arg0 context.Context, arg1 db.UpdateUserPasswordParams) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserPassword", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateUserPassword indicates an expected call of UpdateUserPassword.
+func (mr *MockStoreMockRecorder) UpdateUserPassword(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserPassword", reflect.TypeOf((*MockStore)(nil).UpdateUserPassword), arg0, arg1)
+}
+
+// UpdateUserRole mocks base method.
+func (m *MockStore) UpdateUserRole(arg0 context.Context, arg1 db.UpdateUserRoleParams) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserRole", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateUserRole indicates an expected call of UpdateUserRole.
+func (mr *MockStoreMockRecorder) UpdateUserRole(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserRole", reflect.TypeOf((*MockStore)(nil).UpdateUserRole), arg0, arg1)
+}
@@ -19,6 +19,17 @@ type Income struct {
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
+type Invitation struct {
+	Code      string    `json:"code"`
+	Email     string    `json:"email"`
+	Role      string    `json:"role"`
+	CreatedBy string    `json:"created_by"`
+	UsedBy    string    `json:"used_by"`
+	ExpiresAt time.Time `json:"expires_at"`
+	UsedAt    time.Time `json:"used_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
 type Loan struct {
 	ID        uuid.UUID `json:"id"`
 	Borrower  string    `json:"borrower"`
@@ -28,6 +39,13 @@ type Loan struct {
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
+type PasswordHistory struct {
+	ID             int64     `json:"id"`
+	Username       string    `json:"username"`
+	HashedPassword string    `json:"hashed_password"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
 type PayOut struct {
 	ID        uuid.UUID `json:"id"`
 	Owner     string    `json:"owner"`
@@ -55,4 +73,13 @@ type User struct {
 	PasswordChangedAt time.Time `json:"password_changed_at"`
 	CreatedAt         time.Time `json:"created_at"`
 	UpdatedAt         time.Time `json:"updated_at"`
+	Locale            string    `json:"locale"`
+}
+
+type UserIdentity struct {
+	Issuer      string    `json:"issuer"`
+	Subject     string    `json:"subject"`
+	Username    string    `json:"username"`
+	CreatedAt   time.Time `json:"created_at"`
+	Provisioned bool      `json:"provisioned"`
 }
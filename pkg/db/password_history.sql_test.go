@@ -0,0 +1,54 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/lushenle/plam/pkg/util"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomPasswordHistory(t *testing.T, user User) PasswordHistory {
+	hashedPassword, err := util.HashPassword(util.RandomString(6))
+	require.NoError(t, err)
+
+	arg := CreatePasswordHistoryParams{
+		Username:       user.Username,
+		HashedPassword: hashedPassword,
+	}
+
+	history, err := testStore.CreatePasswordHistory(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotEmpty(t, history)
+
+	require.NotZero(t, history.ID)
+	require.Equal(t, arg.Username, history.Username)
+	require.Equal(t, arg.HashedPassword, history.HashedPassword)
+	require.NotZero(t, history.CreatedAt)
+
+	return history
+}
+
+func TestCreatePasswordHistory(t *testing.T) {
+	user := createRandomUser(t)
+	createRandomPasswordHistory(t, user)
+}
+
+func TestListPasswordHistory(t *testing.T) {
+	user := createRandomUser(t)
+	for i := 0; i < 5; i++ {
+		createRandomPasswordHistory(t, user)
+	}
+
+	arg := ListPasswordHistoryParams{
+		Username: user.Username,
+		Limit:    3,
+	}
+	history, err := testStore.ListPasswordHistory(context.Background(), arg)
+	require.NoError(t, err)
+	require.Len(t, history, 3)
+
+	for _, entry := range history {
+		require.Equal(t, user.Username, entry.Username)
+	}
+}
@@ -7,10 +7,34 @@ package db
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 )
 
+const countPayOuts = `-- name: CountPayOuts :one
+SELECT count(*) FROM pay_out
+`
+
+func (q *Queries) CountPayOuts(ctx context.Context) (int64, error) {
+	row := q.db.QueryRow(ctx, countPayOuts)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countSearchPayOuts = `-- name: CountSearchPayOuts :one
+SELECT count(*) FROM pay_out
+WHERE search @@ websearch_to_tsquery('simple', $1) OR $1 <% owner
+`
+
+func (q *Queries) CountSearchPayOuts(ctx context.Context, query string) (int64, error) {
+	row := q.db.QueryRow(ctx, countSearchPayOuts, query)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createPayOut = `-- name: CreatePayOut :one
 INSERT INTO pay_out (owner, amount, subject) VALUES ($1, $2, $3) RETURNING id, owner, amount, subject, created_at, updated_at
 `
@@ -72,7 +96,7 @@ func (q *Queries) GetPayOut(ctx context.Context, id uuid.UUID) (PayOut, error) {
 }
 
 const listPayOuts = `-- name: ListPayOuts :many
-SELECT id, owner, amount, subject, created_at, updated_at FROM pay_out ORDER BY id OFFSET $1 LIMIT $2
+SELECT id, owner, amount, subject, created_at, updated_at FROM pay_out ORDER BY created_at, id OFFSET $1 LIMIT $2
 `
 
 type ListPayOutsParams struct {
@@ -107,18 +131,206 @@ func (q *Queries) ListPayOuts(ctx context.Context, arg ListPayOutsParams) ([]Pay
 	return items, nil
 }
 
+const listPayOutsAfter = `-- name: ListPayOutsAfter :many
+SELECT id, owner, amount, subject, created_at, updated_at FROM pay_out
+WHERE (created_at, id) > ($1::timestamptz, $2::uuid)
+ORDER BY created_at, id
+LIMIT $3
+`
+
+type ListPayOutsAfterParams struct {
+	CreatedAt time.Time `json:"created_at"`
+	ID        uuid.UUID `json:"id"`
+	Limit     int32     `json:"limit"`
+}
+
+func (q *Queries) ListPayOutsAfter(ctx context.Context, arg ListPayOutsAfterParams) ([]PayOut, error) {
+	rows, err := q.db.Query(ctx, listPayOutsAfter, arg.CreatedAt, arg.ID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []PayOut{}
+	for rows.Next() {
+		var i PayOut
+		if err := rows.Scan(
+			&i.ID,
+			&i.Owner,
+			&i.Amount,
+			&i.Subject,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPayOutsBefore = `-- name: ListPayOutsBefore :many
+SELECT id, owner, amount, subject, created_at, updated_at FROM pay_out
+WHERE (created_at, id) < ($1::timestamptz, $2::uuid)
+ORDER BY created_at DESC, id DESC
+LIMIT $3
+`
+
+type ListPayOutsBeforeParams struct {
+	CreatedAt time.Time `json:"created_at"`
+	ID        uuid.UUID `json:"id"`
+	Limit     int32     `json:"limit"`
+}
+
+func (q *Queries) ListPayOutsBefore(ctx context.Context, arg ListPayOutsBeforeParams) ([]PayOut, error) {
+	rows, err := q.db.Query(ctx, listPayOutsBefore, arg.CreatedAt, arg.ID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []PayOut{}
+	for rows.Next() {
+		var i PayOut
+		if err := rows.Scan(
+			&i.ID,
+			&i.Owner,
+			&i.Amount,
+			&i.Subject,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const searchPayOuts = `-- name: SearchPayOuts :many
-SELECT id, owner, amount, subject, created_at, updated_at FROM pay_out WHERE owner ILIKE $1 ORDER BY id OFFSET $2 LIMIT $3
+WITH q AS (
+    SELECT websearch_to_tsquery('simple', $1::text) AS tsq
+), hits AS (
+    SELECT id, owner, amount, subject, created_at, updated_at,
+        (ts_rank(search, q.tsq) + word_similarity($1, owner))::real AS rank
+    FROM pay_out, q
+    WHERE search @@ q.tsq OR $1 <% owner
+)
+SELECT id, owner, amount, subject, created_at, updated_at FROM hits
+ORDER BY rank DESC, created_at, id
+OFFSET $2 LIMIT $3
 `
 
 type SearchPayOutsParams struct {
-	Owner  string `json:"owner"`
+	Query  string `json:"query"`
 	Offset int32  `json:"offset"`
 	Limit  int32  `json:"limit"`
 }
 
 func (q *Queries) SearchPayOuts(ctx context.Context, arg SearchPayOutsParams) ([]PayOut, error) {
-	rows, err := q.db.Query(ctx, searchPayOuts, arg.Owner, arg.Offset, arg.Limit)
+	rows, err := q.db.Query(ctx, searchPayOuts, arg.Query, arg.Offset, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []PayOut{}
+	for rows.Next() {
+		var i PayOut
+		if err := rows.Scan(
+			&i.ID,
+			&i.Owner,
+			&i.Amount,
+			&i.Subject,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchPayOutsAfter = `-- name: SearchPayOutsAfter :many
+WITH q AS (
+    SELECT websearch_to_tsquery('simple', $1::text) AS tsq
+), hits AS (
+    SELECT id, owner, amount, subject, created_at, updated_at,
+        (ts_rank(search, q.tsq) + word_similarity($1, owner))::real AS rank
+    FROM pay_out, q
+    WHERE search @@ q.tsq OR $1 <% owner
+)
+SELECT id, owner, amount, subject, created_at, updated_at FROM hits
+WHERE (-rank, created_at, id) > (SELECT -rank, $2::timestamptz, $3::uuid FROM hits WHERE id = $3::uuid)
+ORDER BY rank DESC, created_at, id
+LIMIT $4
+`
+
+type SearchPayOutsAfterParams struct {
+	Query     string    `json:"query"`
+	CreatedAt time.Time `json:"created_at"`
+	ID        uuid.UUID `json:"id"`
+	Limit     int32     `json:"limit"`
+}
+
+func (q *Queries) SearchPayOutsAfter(ctx context.Context, arg SearchPayOutsAfterParams) ([]PayOut, error) {
+	rows, err := q.db.Query(ctx, searchPayOutsAfter, arg.Query, arg.CreatedAt, arg.ID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []PayOut{}
+	for rows.Next() {
+		var i PayOut
+		if err := rows.Scan(
+			&i.ID,
+			&i.Owner,
+			&i.Amount,
+			&i.Subject,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchPayOutsBefore = `-- name: SearchPayOutsBefore :many
+WITH q AS (
+    SELECT websearch_to_tsquery('simple', $1::text) AS tsq
+), hits AS (
+    SELECT id, owner, amount, subject, created_at, updated_at,
+        (ts_rank(search, q.tsq) + word_similarity($1, owner))::real AS rank
+    FROM pay_out, q
+    WHERE search @@ q.tsq OR $1 <% owner
+)
+SELECT id, owner, amount, subject, created_at, updated_at FROM hits
+WHERE (-rank, created_at, id) < (SELECT -rank, $2::timestamptz, $3::uuid FROM hits WHERE id = $3::uuid)
+ORDER BY rank, created_at DESC, id DESC
+LIMIT $4
+`
+
+type SearchPayOutsBeforeParams struct {
+	Query     string    `json:"query"`
+	CreatedAt time.Time `json:"created_at"`
+	ID        uuid.UUID `json:"id"`
+	Limit     int32     `json:"limit"`
+}
+
+func (q *Queries) SearchPayOutsBefore(ctx context.Context, arg SearchPayOutsBeforeParams) ([]PayOut, error) {
+	rows, err := q.db.Query(ctx, searchPayOutsBefore, arg.Query, arg.CreatedAt, arg.ID, arg.Limit)
 	if err != nil {
 		return nil, err
 	}
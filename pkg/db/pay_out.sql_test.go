@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/lushenle/plam/pkg/util"
 	"github.com/stretchr/testify/require"
@@ -78,7 +79,7 @@ func TestSearchPayOut(t *testing.T) {
 	require.NotZero(t, payOut1.CreatedAt)
 
 	arg2 := SearchPayOutsParams{
-		Owner:  arg.Owner,
+		Query:  arg.Owner,
 		Offset: 0,
 		Limit:  5,
 	}
@@ -113,3 +114,71 @@ func TestDeletePayOut(t *testing.T) {
 	require.ErrorIs(t, err, ErrRecordNotFound)
 	require.Empty(t, payOut3)
 }
+
+func TestListPayOutsCursor(t *testing.T) {
+	for i := 0; i < 3; i++ {
+		createRandomPayOut(t)
+	}
+
+	payOuts, err := testStore.ListPayOuts(context.Background(), ListPayOutsParams{Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, payOuts, 2)
+
+	after, err := testStore.ListPayOutsAfter(context.Background(), ListPayOutsAfterParams{
+		CreatedAt: payOuts[0].CreatedAt,
+		ID:        payOuts[0].ID,
+		Limit:     1,
+	})
+	require.NoError(t, err)
+	require.Len(t, after, 1)
+	require.Equal(t, payOuts[1].ID, after[0].ID)
+
+	before, err := testStore.ListPayOutsBefore(context.Background(), ListPayOutsBeforeParams{
+		CreatedAt: payOuts[1].CreatedAt,
+		ID:        payOuts[1].ID,
+		Limit:     1,
+	})
+	require.NoError(t, err)
+	require.Len(t, before, 1)
+	require.Equal(t, payOuts[0].ID, before[0].ID)
+}
+
+func TestSearchPayOutsCursor(t *testing.T) {
+	payOut := createRandomPayOut(t)
+
+	after, err := testStore.SearchPayOutsAfter(context.Background(), SearchPayOutsAfterParams{
+		Query:     payOut.Owner,
+		CreatedAt: payOut.CreatedAt,
+		ID:        payOut.ID,
+		Limit:     5,
+	})
+	require.NoError(t, err)
+	require.Empty(t, after)
+
+	before, err := testStore.SearchPayOutsBefore(context.Background(), SearchPayOutsBeforeParams{
+		Query:     payOut.Owner,
+		CreatedAt: payOut.CreatedAt.Add(time.Second),
+		ID:        payOut.ID,
+		Limit:     5,
+	})
+	require.NoError(t, err)
+	require.Len(t, before, 1)
+	require.Equal(t, payOut.ID, before[0].ID)
+}
+
+func TestCountPayOuts(t *testing.T) {
+	createRandomPayOut(t)
+
+	count, err := testStore.CountPayOuts(context.Background())
+	require.NoError(t, err)
+	require.Positive(t, count)
+
+	payOut := createRandomPayOut(t)
+	count2, err := testStore.CountPayOuts(context.Background())
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, count2, count+1)
+
+	count, err = testStore.CountSearchPayOuts(context.Background(), payOut.Owner)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), count)
+}
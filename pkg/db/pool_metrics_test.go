@@ -0,0 +1,40 @@
+package db
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPoolCollector(t *testing.T) {
+	config, err := pgxpool.ParseConfig("postgresql://root@localhost:5432/plam")
+	require.NoError(t, err)
+	config.MaxConns = 7
+
+	// The pool connects lazily, its statistics are available without a database
+	pool, err := pgxpool.NewWithConfig(context.Background(), config)
+	require.NoError(t, err)
+	defer pool.Close()
+
+	collector := NewPoolCollector(pool, "primary")
+
+	registry := prometheus.NewPedanticRegistry()
+	require.NoError(t, registry.Register(collector))
+	require.Equal(t, 12, testutil.CollectAndCount(collector))
+
+	expected := `
+# HELP plam_db_pool_max_conns Maximum size of the pool.
+# TYPE plam_db_pool_max_conns gauge
+plam_db_pool_max_conns{pool="primary"} 7
+# HELP plam_db_pool_acquired_conns Number of connections currently in use.
+# TYPE plam_db_pool_acquired_conns gauge
+plam_db_pool_acquired_conns{pool="primary"} 0
+`
+	require.NoError(t, testutil.GatherAndCompare(registry, strings.NewReader(expected),
+		"plam_db_pool_max_conns", "plam_db_pool_acquired_conns"))
+}
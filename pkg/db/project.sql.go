@@ -7,10 +7,34 @@ package db
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 )
 
+const countProjects = `-- name: CountProjects :one
+SELECT count(*) FROM project
+`
+
+func (q *Queries) CountProjects(ctx context.Context) (int64, error) {
+	row := q.db.QueryRow(ctx, countProjects)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countSearchProjects = `-- name: CountSearchProjects :one
+SELECT count(*) FROM project
+WHERE search @@ websearch_to_tsquery('simple', $1) OR $1 <% name
+`
+
+func (q *Queries) CountSearchProjects(ctx context.Context, query string) (int64, error) {
+	row := q.db.QueryRow(ctx, countSearchProjects, query)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createProject = `-- name: CreateProject :one
 INSERT INTO project (name, description, amount) VALUES ($1, $2, $3) RETURNING id, name, amount, description, created_at, updated_at
 `
@@ -72,7 +96,7 @@ func (q *Queries) GetProject(ctx context.Context, id uuid.UUID) (Project, error)
 }
 
 const listProjects = `-- name: ListProjects :many
-SELECT id, name, amount, description, created_at, updated_at FROM project ORDER BY id OFFSET $1 LIMIT $2
+SELECT id, name, amount, description, created_at, updated_at FROM project ORDER BY created_at, id OFFSET $1 LIMIT $2
 `
 
 type ListProjectsParams struct {
@@ -107,18 +131,206 @@ func (q *Queries) ListProjects(ctx context.Context, arg ListProjectsParams) ([]P
 	return items, nil
 }
 
+const listProjectsAfter = `-- name: ListProjectsAfter :many
+SELECT id, name, amount, description, created_at, updated_at FROM project
+WHERE (created_at, id) > ($1::timestamptz, $2::uuid)
+ORDER BY created_at, id
+LIMIT $3
+`
+
+type ListProjectsAfterParams struct {
+	CreatedAt time.Time `json:"created_at"`
+	ID        uuid.UUID `json:"id"`
+	Limit     int32     `json:"limit"`
+}
+
+func (q *Queries) ListProjectsAfter(ctx context.Context, arg ListProjectsAfterParams) ([]Project, error) {
+	rows, err := q.db.Query(ctx, listProjectsAfter, arg.CreatedAt, arg.ID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Project{}
+	for rows.Next() {
+		var i Project
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Amount,
+			&i.Description,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listProjectsBefore = `-- name: ListProjectsBefore :many
+SELECT id, name, amount, description, created_at, updated_at FROM project
+WHERE (created_at, id) < ($1::timestamptz, $2::uuid)
+ORDER BY created_at DESC, id DESC
+LIMIT $3
+`
+
+type ListProjectsBeforeParams struct {
+	CreatedAt time.Time `json:"created_at"`
+	ID        uuid.UUID `json:"id"`
+	Limit     int32     `json:"limit"`
+}
+
+func (q *Queries) ListProjectsBefore(ctx context.Context, arg ListProjectsBeforeParams) ([]Project, error) {
+	rows, err := q.db.Query(ctx, listProjectsBefore, arg.CreatedAt, arg.ID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Project{}
+	for rows.Next() {
+		var i Project
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Amount,
+			&i.Description,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const searchProjects = `-- name: SearchProjects :many
-SELECT id, name, amount, description, created_at, updated_at FROM project WHERE name ILIKE $1 ORDER BY id OFFSET $2 LIMIT $3
+WITH q AS (
+    SELECT websearch_to_tsquery('simple', $1::text) AS tsq
+), hits AS (
+    SELECT id, name, amount, description, created_at, updated_at,
+        (ts_rank(search, q.tsq) + word_similarity($1, name))::real AS rank
+    FROM project, q
+    WHERE search @@ q.tsq OR $1 <% name
+)
+SELECT id, name, amount, description, created_at, updated_at FROM hits
+ORDER BY rank DESC, created_at, id
+OFFSET $2 LIMIT $3
 `
 
 type SearchProjectsParams struct {
-	Name   string `json:"name"`
+	Query  string `json:"query"`
 	Offset int32  `json:"offset"`
 	Limit  int32  `json:"limit"`
 }
 
 func (q *Queries) SearchProjects(ctx context.Context, arg SearchProjectsParams) ([]Project, error) {
-	rows, err := q.db.Query(ctx, searchProjects, arg.Name, arg.Offset, arg.Limit)
+	rows, err := q.db.Query(ctx, searchProjects, arg.Query, arg.Offset, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Project{}
+	for rows.Next() {
+		var i Project
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Amount,
+			&i.Description,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchProjectsAfter = `-- name: SearchProjectsAfter :many
+WITH q AS (
+    SELECT websearch_to_tsquery('simple', $1::text) AS tsq
+), hits AS (
+    SELECT id, name, amount, description, created_at, updated_at,
+        (ts_rank(search, q.tsq) + word_similarity($1, name))::real AS rank
+    FROM project, q
+    WHERE search @@ q.tsq OR $1 <% name
+)
+SELECT id, name, amount, description, created_at, updated_at FROM hits
+WHERE (-rank, created_at, id) > (SELECT -rank, $2::timestamptz, $3::uuid FROM hits WHERE id = $3::uuid)
+ORDER BY rank DESC, created_at, id
+LIMIT $4
+`
+
+type SearchProjectsAfterParams struct {
+	Query     string    `json:"query"`
+	CreatedAt time.Time `json:"created_at"`
+	ID        uuid.UUID `json:"id"`
+	Limit     int32     `json:"limit"`
+}
+
+func (q *Queries) SearchProjectsAfter(ctx context.Context, arg SearchProjectsAfterParams) ([]Project, error) {
+	rows, err := q.db.Query(ctx, searchProjectsAfter, arg.Query, arg.CreatedAt, arg.ID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Project{}
+	for rows.Next() {
+		var i Project
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Amount,
+			&i.Description,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchProjectsBefore = `-- name: SearchProjectsBefore :many
+WITH q AS (
+    SELECT websearch_to_tsquery('simple', $1::text) AS tsq
+), hits AS (
+    SELECT id, name, amount, description, created_at, updated_at,
+        (ts_rank(search, q.tsq) + word_similarity($1, name))::real AS rank
+    FROM project, q
+    WHERE search @@ q.tsq OR $1 <% name
+)
+SELECT id, name, amount, description, created_at, updated_at FROM hits
+WHERE (-rank, created_at, id) < (SELECT -rank, $2::timestamptz, $3::uuid FROM hits WHERE id = $3::uuid)
+ORDER BY rank, created_at DESC, id DESC
+LIMIT $4
+`
+
+type SearchProjectsBeforeParams struct {
+	Query     string    `json:"query"`
+	CreatedAt time.Time `json:"created_at"`
+	ID        uuid.UUID `json:"id"`
+	Limit     int32     `json:"limit"`
+}
+
+func (q *Queries) SearchProjectsBefore(ctx context.Context, arg SearchProjectsBeforeParams) ([]Project, error) {
+	rows, err := q.db.Query(ctx, searchProjectsBefore, arg.Query, arg.CreatedAt, arg.ID, arg.Limit)
 	if err != nil {
 		return nil, err
 	}
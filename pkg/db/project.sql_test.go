@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/lushenle/plam/pkg/util"
 	"github.com/stretchr/testify/require"
@@ -84,7 +85,7 @@ func TestSearchProjects(t *testing.T) {
 	require.NotZero(t, project.CreatedAt)
 
 	searchArg := SearchProjectsParams{
-		Name:   arg.Name,
+		Query:  arg.Name,
 		Offset: 0,
 		Limit:  5,
 	}
@@ -116,3 +117,114 @@ func TestDeleteProject(t *testing.T) {
 	require.ErrorIs(t, err, ErrRecordNotFound)
 	require.Empty(t, project3)
 }
+
+func TestListProjectsCursor(t *testing.T) {
+	for i := 0; i < 3; i++ {
+		createRandomProject(t)
+	}
+
+	projects, err := testStore.ListProjects(context.Background(), ListProjectsParams{Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, projects, 2)
+
+	after, err := testStore.ListProjectsAfter(context.Background(), ListProjectsAfterParams{
+		CreatedAt: projects[0].CreatedAt,
+		ID:        projects[0].ID,
+		Limit:     1,
+	})
+	require.NoError(t, err)
+	require.Len(t, after, 1)
+	require.Equal(t, projects[1].ID, after[0].ID)
+
+	before, err := testStore.ListProjectsBefore(context.Background(), ListProjectsBeforeParams{
+		CreatedAt: projects[1].CreatedAt,
+		ID:        projects[1].ID,
+		Limit:     1,
+	})
+	require.NoError(t, err)
+	require.Len(t, before, 1)
+	require.Equal(t, projects[0].ID, before[0].ID)
+}
+
+func TestSearchProjectsCursor(t *testing.T) {
+	project := createRandomProject(t)
+
+	after, err := testStore.SearchProjectsAfter(context.Background(), SearchProjectsAfterParams{
+		Query:     project.Name,
+		CreatedAt: project.CreatedAt,
+		ID:        project.ID,
+		Limit:     5,
+	})
+	require.NoError(t, err)
+	require.Empty(t, after)
+
+	before, err := testStore.SearchProjectsBefore(context.Background(), SearchProjectsBeforeParams{
+		Query:     project.Name,
+		CreatedAt: project.CreatedAt.Add(time.Second),
+		ID:        project.ID,
+		Limit:     5,
+	})
+	require.NoError(t, err)
+	require.Len(t, before, 1)
+	require.Equal(t, project.ID, before[0].ID)
+}
+
+func TestSearchProjectsRank(t *testing.T) {
+	word := "rank" + util.RandomString(8)
+	// The older project only mentions the word in its description, so it ranks lower
+	older, err := testStore.CreateProject(context.Background(), CreateProjectParams{
+		Name:        util.RandomString(8),
+		Description: word,
+		Amount:      util.RandomFloat32(300, 1000),
+	})
+	require.NoError(t, err)
+	newer, err := testStore.CreateProject(context.Background(), CreateProjectParams{
+		Name:        word,
+		Description: util.RandomString(30),
+		Amount:      util.RandomFloat32(300, 1000),
+	})
+	require.NoError(t, err)
+
+	projects, err := testStore.SearchProjects(context.Background(), SearchProjectsParams{Query: word, Limit: 5})
+	require.NoError(t, err)
+	require.Len(t, projects, 2)
+	require.Equal(t, newer.ID, projects[0].ID)
+	require.Equal(t, older.ID, projects[1].ID)
+
+	after, err := testStore.SearchProjectsAfter(context.Background(), SearchProjectsAfterParams{
+		Query:     word,
+		CreatedAt: newer.CreatedAt,
+		ID:        newer.ID,
+		Limit:     5,
+	})
+	require.NoError(t, err)
+	require.Len(t, after, 1)
+	require.Equal(t, older.ID, after[0].ID)
+
+	before, err := testStore.SearchProjectsBefore(context.Background(), SearchProjectsBeforeParams{
+		Query:     word,
+		CreatedAt: older.CreatedAt,
+		ID:        older.ID,
+		Limit:     5,
+	})
+	require.NoError(t, err)
+	require.Len(t, before, 1)
+	require.Equal(t, newer.ID, before[0].ID)
+}
+
+func TestCountProjects(t *testing.T) {
+	createRandomProject(t)
+
+	count, err := testStore.CountProjects(context.Background())
+	require.NoError(t, err)
+	require.Positive(t, count)
+
+	project := createRandomProject(t)
+	count2, err := testStore.CountProjects(context.Background())
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, count2, count+1)
+
+	count, err = testStore.CountSearchProjects(context.Background(), project.Name)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), count)
+}
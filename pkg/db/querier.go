@@ -11,11 +11,23 @@ import (
 )
 
 type Querier interface {
+	ConsumeInvitation(ctx context.Context, arg ConsumeInvitationParams) (Invitation, error)
+	CountIncomes(ctx context.Context) (int64, error)
+	CountLoans(ctx context.Context) (int64, error)
+	CountPayOuts(ctx context.Context) (int64, error)
+	CountProjects(ctx context.Context) (int64, error)
+	CountSearchIncomes(ctx context.Context, query string) (int64, error)
+	CountSearchLoans(ctx context.Context, query string) (int64, error)
+	CountSearchPayOuts(ctx context.Context, query string) (int64, error)
+	CountSearchProjects(ctx context.Context, query string) (int64, error)
 	CreateIncome(ctx context.Context, arg CreateIncomeParams) (Income, error)
+	CreateInvitation(ctx context.Context, arg CreateInvitationParams) (Invitation, error)
 	CreateLoan(ctx context.Context, arg CreateLoanParams) (Loan, error)
+	CreatePasswordHistory(ctx context.Context, arg CreatePasswordHistoryParams) (PasswordHistory, error)
 	CreatePayOut(ctx context.Context, arg CreatePayOutParams) (PayOut, error)
 	CreateProject(ctx context.Context, arg CreateProjectParams) (Project, error)
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
+	CreateUserIdentity(ctx context.Context, arg CreateUserIdentityParams) (UserIdentity, error)
 	DeleteIncome(ctx context.Context, id uuid.UUID) (Income, error)
 	DeleteLoan(ctx context.Context, id uuid.UUID) (Loan, error)
 	DeletePayOut(ctx context.Context, id uuid.UUID) (PayOut, error)
@@ -25,14 +37,37 @@ type Querier interface {
 	GetPayOut(ctx context.Context, id uuid.UUID) (PayOut, error)
 	GetProject(ctx context.Context, id uuid.UUID) (Project, error)
 	GetUser(ctx context.Context, username string) (User, error)
+	GetUserByEmail(ctx context.Context, email string) (User, error)
+	GetUserIdentity(ctx context.Context, arg GetUserIdentityParams) (UserIdentity, error)
 	ListIncomes(ctx context.Context, arg ListIncomesParams) ([]Income, error)
+	ListIncomesAfter(ctx context.Context, arg ListIncomesAfterParams) ([]Income, error)
+	ListIncomesBefore(ctx context.Context, arg ListIncomesBeforeParams) ([]Income, error)
 	ListLoans(ctx context.Context, arg ListLoansParams) ([]Loan, error)
+	ListLoansAfter(ctx context.Context, arg ListLoansAfterParams) ([]Loan, error)
+	ListLoansBefore(ctx context.Context, arg ListLoansBeforeParams) ([]Loan, error)
+	ListPasswordHistory(ctx context.Context, arg ListPasswordHistoryParams) ([]PasswordHistory, error)
 	ListPayOuts(ctx context.Context, arg ListPayOutsParams) ([]PayOut, error)
+	ListPayOutsAfter(ctx context.Context, arg ListPayOutsAfterParams) ([]PayOut, error)
+	ListPayOutsBefore(ctx context.Context, arg ListPayOutsBeforeParams) ([]PayOut, error)
 	ListProjects(ctx context.Context, arg ListProjectsParams) ([]Project, error)
+	ListProjectsAfter(ctx context.Context, arg ListProjectsAfterParams) ([]Project, error)
+	ListProjectsBefore(ctx context.Context, arg ListProjectsBeforeParams) ([]Project, error)
+	SearchAll(ctx context.Context, arg SearchAllParams) ([]SearchAllRow, error)
 	SearchIncomes(ctx context.Context, arg SearchIncomesParams) ([]Income, error)
+	SearchIncomesAfter(ctx context.Context, arg SearchIncomesAfterParams) ([]Income, error)
+	SearchIncomesBefore(ctx context.Context, arg SearchIncomesBeforeParams) ([]Income, error)
 	SearchLoans(ctx context.Context, arg SearchLoansParams) ([]Loan, error)
+	SearchLoansAfter(ctx context.Context, arg SearchLoansAfterParams) ([]Loan, error)
+	SearchLoansBefore(ctx context.Context, arg SearchLoansBeforeParams) ([]Loan, error)
 	SearchPayOuts(ctx context.Context, arg SearchPayOutsParams) ([]PayOut, error)
+	SearchPayOutsAfter(ctx context.Context, arg SearchPayOutsAfterParams) ([]PayOut, error)
+	SearchPayOutsBefore(ctx context.Context, arg SearchPayOutsBeforeParams) ([]PayOut, error)
 	SearchProjects(ctx context.Context, arg SearchProjectsParams) ([]Project, error)
+	SearchProjectsAfter(ctx context.Context, arg SearchProjectsAfterParams) ([]Project, error)
+	SearchProjectsBefore(ctx context.Context, arg SearchProjectsBeforeParams) ([]Project, error)
+	UpdateUserLocale(ctx context.Context, arg UpdateUserLocaleParams) (User, error)
+	UpdateUserPassword(ctx context.Context, arg UpdateUserPasswordParams) (User, error)
+	UpdateUserRole(ctx context.Context, arg UpdateUserRoleParams) (User, error)
 }
 
 var _ Querier = (*Queries)(nil)
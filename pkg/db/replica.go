@@ -0,0 +1,288 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// replicaBackoff is how long reads stay on the primary after the replica failed
+const replicaBackoff = 30 * time.Second
+
+type primaryKey struct{}
+
+// WithPrimary returns a context whose reads are served by the primary, so that
+// they observe the writes made just before, e.g. by the same request
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryKey{}, true)
+}
+
+// ReadsFromPrimary reports whether ctx was returned by WithPrimary
+func ReadsFromPrimary(ctx context.Context) bool {
+	primary, _ := ctx.Value(primaryKey{}).(bool)
+	return primary
+}
+
+// ReplicaStore is a Store sending Count*, Filter*, Get*, List* and Search* queries to a read replica,
+// writes and transactions go to the primary. Reads fall back to the primary when the
+// context is marked by WithPrimary or when the replica cannot be reached.
+type ReplicaStore struct {
+	*SQLStore
+	replica *Queries
+
+	// unhealthyUntil is the unix time in nanoseconds until which reads skip the replica
+	unhealthyUntil atomic.Int64
+	now            func() time.Time
+}
+
+// NewReplicaStore creates a store reading from the replica pool and writing to the primary pool
+func NewReplicaStore(primary, replica *pgxpool.Pool) Store {
+	return newReplicaStore(&SQLStore{connPool: primary, Queries: New(primary)}, New(replica))
+}
+
+func newReplicaStore(primary *SQLStore, replica *Queries) *ReplicaStore {
+	return &ReplicaStore{
+		SQLStore: primary,
+		replica:  replica,
+		now:      time.Now,
+	}
+}
+
+// replicaHealthy reports whether reads may be sent to the replica
+func (store *ReplicaStore) replicaHealthy() bool {
+	return store.now().UnixNano() >= store.unhealthyUntil.Load()
+}
+
+// markReplicaUnhealthy sends reads to the primary for replicaBackoff
+func (store *ReplicaStore) markReplicaUnhealthy() {
+	store.unhealthyUntil.Store(store.now().Add(replicaBackoff).UnixNano())
+}
+
+// isReplicaFailure reports whether err means that the replica could not serve the query,
+// as opposed to the query itself failing
+func isReplicaFailure(err error) bool {
+	if err == nil || errors.Is(err, pgx.ErrNoRows) {
+		return false
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var pgErr *pgconn.PgError
+	return !errors.As(err, &pgErr)
+}
+
+// read runs query on the replica when possible, retrying on the primary when the replica fails
+func read[T any](ctx context.Context, store *ReplicaStore, query func(q *Queries) (T, error)) (T, error) {
+	if ReadsFromPrimary(ctx) || !store.replicaHealthy() {
+		return query(store.Queries)
+	}
+
+	result, err := query(store.replica)
+	if isReplicaFailure(err) && ctx.Err() == nil {
+		store.markReplicaUnhealthy()
+		return query(store.Queries)
+	}
+	return result, err
+}
+
+func (store *ReplicaStore) CountFilterIncomes(ctx context.Context, filter ListFilter) (int64, error) {
+	return read(ctx, store, func(q *Queries) (int64, error) { return q.CountFilterIncomes(ctx, filter) })
+}
+
+func (store *ReplicaStore) CountFilterLoans(ctx context.Context, filter ListFilter) (int64, error) {
+	return read(ctx, store, func(q *Queries) (int64, error) { return q.CountFilterLoans(ctx, filter) })
+}
+
+func (store *ReplicaStore) CountFilterPayOuts(ctx context.Context, filter ListFilter) (int64, error) {
+	return read(ctx, store, func(q *Queries) (int64, error) { return q.CountFilterPayOuts(ctx, filter) })
+}
+
+func (store *ReplicaStore) CountFilterProjects(ctx context.Context, filter ListFilter) (int64, error) {
+	return read(ctx, store, func(q *Queries) (int64, error) { return q.CountFilterProjects(ctx, filter) })
+}
+
+func (store *ReplicaStore) CountIncomes(ctx context.Context) (int64, error) {
+	return read(ctx, store, func(q *Queries) (int64, error) { return q.CountIncomes(ctx) })
+}
+
+func (store *ReplicaStore) CountLoans(ctx context.Context) (int64, error) {
+	return read(ctx, store, func(q *Queries) (int64, error) { return q.CountLoans(ctx) })
+}
+
+func (store *ReplicaStore) CountPayOuts(ctx context.Context) (int64, error) {
+	return read(ctx, store, func(q *Queries) (int64, error) { return q.CountPayOuts(ctx) })
+}
+
+func (store *ReplicaStore) CountProjects(ctx context.Context) (int64, error) {
+	return read(ctx, store, func(q *Queries) (int64, error) { return q.CountProjects(ctx) })
+}
+
+func (store *ReplicaStore) CountSearchIncomes(ctx context.Context, query string) (int64, error) {
+	return read(ctx, store, func(q *Queries) (int64, error) { return q.CountSearchIncomes(ctx, query) })
+}
+
+func (store *ReplicaStore) CountSearchLoans(ctx context.Context, query string) (int64, error) {
+	return read(ctx, store, func(q *Queries) (int64, error) { return q.CountSearchLoans(ctx, query) })
+}
+
+func (store *ReplicaStore) CountSearchPayOuts(ctx context.Context, query string) (int64, error) {
+	return read(ctx, store, func(q *Queries) (int64, error) { return q.CountSearchPayOuts(ctx, query) })
+}
+
+func (store *ReplicaStore) CountSearchProjects(ctx context.Context, query string) (int64, error) {
+	return read(ctx, store, func(q *Queries) (int64, error) { return q.CountSearchProjects(ctx, query) })
+}
+
+func (store *ReplicaStore) FilterIncomes(ctx context.Context, arg FilterParams) ([]Income, error) {
+	return read(ctx, store, func(q *Queries) ([]Income, error) { return q.FilterIncomes(ctx, arg) })
+}
+
+func (store *ReplicaStore) FilterLoans(ctx context.Context, arg FilterParams) ([]Loan, error) {
+	return read(ctx, store, func(q *Queries) ([]Loan, error) { return q.FilterLoans(ctx, arg) })
+}
+
+func (store *ReplicaStore) FilterPayOuts(ctx context.Context, arg FilterParams) ([]PayOut, error) {
+	return read(ctx, store, func(q *Queries) ([]PayOut, error) { return q.FilterPayOuts(ctx, arg) })
+}
+
+func (store *ReplicaStore) FilterProjects(ctx context.Context, arg FilterParams) ([]Project, error) {
+	return read(ctx, store, func(q *Queries) ([]Project, error) { return q.FilterProjects(ctx, arg) })
+}
+
+func (store *ReplicaStore) GetIncome(ctx context.Context, id uuid.UUID) (Income, error) {
+	return read(ctx, store, func(q *Queries) (Income, error) { return q.GetIncome(ctx, id) })
+}
+
+func (store *ReplicaStore) GetLoan(ctx context.Context, id uuid.UUID) (Loan, error) {
+	return read(ctx, store, func(q *Queries) (Loan, error) { return q.GetLoan(ctx, id) })
+}
+
+func (store *ReplicaStore) GetPayOut(ctx context.Context, id uuid.UUID) (PayOut, error) {
+	return read(ctx, store, func(q *Queries) (PayOut, error) { return q.GetPayOut(ctx, id) })
+}
+
+func (store *ReplicaStore) GetProject(ctx context.Context, id uuid.UUID) (Project, error) {
+	return read(ctx, store, func(q *Queries) (Project, error) { return q.GetProject(ctx, id) })
+}
+
+func (store *ReplicaStore) GetUser(ctx context.Context, username string) (User, error) {
+	return read(ctx, store, func(q *Queries) (User, error) { return q.GetUser(ctx, username) })
+}
+
+func (store *ReplicaStore) GetUserByEmail(ctx context.Context, email string) (User, error) {
+	return read(ctx, store, func(q *Queries) (User, error) { return q.GetUserByEmail(ctx, email) })
+}
+
+func (store *ReplicaStore) GetUserIdentity(ctx context.Context, arg GetUserIdentityParams) (UserIdentity, error) {
+	return read(ctx, store, func(q *Queries) (UserIdentity, error) { return q.GetUserIdentity(ctx, arg) })
+}
+
+func (store *ReplicaStore) ListIncomes(ctx context.Context, arg ListIncomesParams) ([]Income, error) {
+	return read(ctx, store, func(q *Queries) ([]Income, error) { return q.ListIncomes(ctx, arg) })
+}
+
+func (store *ReplicaStore) ListIncomesAfter(ctx context.Context, arg ListIncomesAfterParams) ([]Income, error) {
+	return read(ctx, store, func(q *Queries) ([]Income, error) { return q.ListIncomesAfter(ctx, arg) })
+}
+
+func (store *ReplicaStore) ListIncomesBefore(ctx context.Context, arg ListIncomesBeforeParams) ([]Income, error) {
+	return read(ctx, store, func(q *Queries) ([]Income, error) { return q.ListIncomesBefore(ctx, arg) })
+}
+
+func (store *ReplicaStore) ListLoans(ctx context.Context, arg ListLoansParams) ([]Loan, error) {
+	return read(ctx, store, func(q *Queries) ([]Loan, error) { return q.ListLoans(ctx, arg) })
+}
+
+func (store *ReplicaStore) ListLoansAfter(ctx context.Context, arg ListLoansAfterParams) ([]Loan, error) {
+	return read(ctx, store, func(q *Queries) ([]Loan, error) { return q.ListLoansAfter(ctx, arg) })
+}
+
+func (store *ReplicaStore) ListLoansBefore(ctx context.Context, arg ListLoansBeforeParams) ([]Loan, error) {
+	return read(ctx, store, func(q *Queries) ([]Loan, error) { return q.ListLoansBefore(ctx, arg) })
+}
+
+func (store *ReplicaStore) ListPasswordHistory(ctx context.Context, arg ListPasswordHistoryParams) ([]PasswordHistory, error) {
+	return read(ctx, store, func(q *Queries) ([]PasswordHistory, error) { return q.ListPasswordHistory(ctx, arg) })
+}
+
+func (store *ReplicaStore) ListPayOuts(ctx context.Context, arg ListPayOutsParams) ([]PayOut, error) {
+	return read(ctx, store, func(q *Queries) ([]PayOut, error) { return q.ListPayOuts(ctx, arg) })
+}
+
+func (store *ReplicaStore) ListPayOutsAfter(ctx context.Context, arg ListPayOutsAfterParams) ([]PayOut, error) {
+	return read(ctx, store, func(q *Queries) ([]PayOut, error) { return q.ListPayOutsAfter(ctx, arg) })
+}
+
+func (store *ReplicaStore) ListPayOutsBefore(ctx context.Context, arg ListPayOutsBeforeParams) ([]PayOut, error) {
+	return read(ctx, store, func(q *Queries) ([]PayOut, error) { return q.ListPayOutsBefore(ctx, arg) })
+}
+
+func (store *ReplicaStore) ListProjects(ctx context.Context, arg ListProjectsParams) ([]Project, error) {
+	return read(ctx, store, func(q *Queries) ([]Project, error) { return q.ListProjects(ctx, arg) })
+}
+
+func (store *ReplicaStore) ListProjectsAfter(ctx context.Context, arg ListProjectsAfterParams) ([]Project, error) {
+	return read(ctx, store, func(q *Queries) ([]Project, error) { return q.ListProjectsAfter(ctx, arg) })
+}
+
+func (store *ReplicaStore) ListProjectsBefore(ctx context.Context, arg ListProjectsBeforeParams) ([]Project, error) {
+	return read(ctx, store, func(q *Queries) ([]Project, error) { return q.ListProjectsBefore(ctx, arg) })
+}
+
+func (store *ReplicaStore) SearchAll(ctx context.Context, arg SearchAllParams) ([]SearchAllRow, error) {
+	return read(ctx, store, func(q *Queries) ([]SearchAllRow, error) { return q.SearchAll(ctx, arg) })
+}
+
+func (store *ReplicaStore) SearchIncomes(ctx context.Context, arg SearchIncomesParams) ([]Income, error) {
+	return read(ctx, store, func(q *Queries) ([]Income, error) { return q.SearchIncomes(ctx, arg) })
+}
+
+func (store *ReplicaStore) SearchIncomesAfter(ctx context.Context, arg SearchIncomesAfterParams) ([]Income, error) {
+	return read(ctx, store, func(q *Queries) ([]Income, error) { return q.SearchIncomesAfter(ctx, arg) })
+}
+
+func (store *ReplicaStore) SearchIncomesBefore(ctx context.Context, arg SearchIncomesBeforeParams) ([]Income, error) {
+	return read(ctx, store, func(q *Queries) ([]Income, error) { return q.SearchIncomesBefore(ctx, arg) })
+}
+
+func (store *ReplicaStore) SearchLoans(ctx context.Context, arg SearchLoansParams) ([]Loan, error) {
+	return read(ctx, store, func(q *Queries) ([]Loan, error) { return q.SearchLoans(ctx, arg) })
+}
+
+func (store *ReplicaStore) SearchLoansAfter(ctx context.Context, arg SearchLoansAfterParams) ([]Loan, error) {
+	return read(ctx, store, func(q *Queries) ([]Loan, error) { return q.SearchLoansAfter(ctx, arg) })
+}
+
+func (store *ReplicaStore) SearchLoansBefore(ctx context.Context, arg SearchLoansBeforeParams) ([]Loan, error) {
+	return read(ctx, store, func(q *Queries) ([]Loan, error) { return q.SearchLoansBefore(ctx, arg) })
+}
+
+func (store *ReplicaStore) SearchPayOuts(ctx context.Context, arg SearchPayOutsParams) ([]PayOut, error) {
+	return read(ctx, store, func(q *Queries) ([]PayOut, error) { return q.SearchPayOuts(ctx, arg) })
+}
+
+func (store *ReplicaStore) SearchPayOutsAfter(ctx context.Context, arg SearchPayOutsAfterParams) ([]PayOut, error) {
+	return read(ctx, store, func(q *Queries) ([]PayOut, error) { return q.SearchPayOutsAfter(ctx, arg) })
+}
+
+func (store *ReplicaStore) SearchPayOutsBefore(ctx context.Context, arg SearchPayOutsBeforeParams) ([]PayOut, error) {
+	return read(ctx, store, func(q *Queries) ([]PayOut, error) { return q.SearchPayOutsBefore(ctx, arg) })
+}
+
+func (store *ReplicaStore) SearchProjects(ctx context.Context, arg SearchProjectsParams) ([]Project, error) {
+	return read(ctx, store, func(q *Queries) ([]Project, error) { return q.SearchProjects(ctx, arg) })
+}
+
+func (store *ReplicaStore) SearchProjectsAfter(ctx context.Context, arg SearchProjectsAfterParams) ([]Project, error) {
+	return read(ctx, store, func(q *Queries) ([]Project, error) { return q.SearchProjectsAfter(ctx, arg) })
+}
+
+func (store *ReplicaStore) SearchProjectsBefore(ctx context.Context, arg SearchProjectsBeforeParams) ([]Project, error) {
+	return read(ctx, store, func(q *Queries) ([]Project, error) { return q.SearchProjectsBefore(ctx, arg) })
+}
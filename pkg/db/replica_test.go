@@ -0,0 +1,165 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeDB counts the queries it receives and fails them with err
+type fakeDB struct {
+	queries int
+	err     error
+}
+
+func (db *fakeDB) Exec(context.Context, string, ...interface{}) (pgconn.CommandTag, error) {
+	db.queries++
+	return pgconn.CommandTag{}, db.err
+}
+
+func (db *fakeDB) Query(context.Context, string, ...interface{}) (pgx.Rows, error) {
+	db.queries++
+	return nil, db.err
+}
+
+func (db *fakeDB) QueryRow(context.Context, string, ...interface{}) pgx.Row {
+	db.queries++
+	return fakeRow{err: db.err}
+}
+
+type fakeRow struct {
+	err error
+}
+
+func (row fakeRow) Scan(...any) error {
+	return row.err
+}
+
+func TestReplicaStoreRouting(t *testing.T) {
+	errConnRefused := errors.New("dial tcp 127.0.0.1:5433: connect: connection refused")
+	errUndefinedColumn := &pgconn.PgError{Code: "42703"}
+
+	testCases := []struct {
+		name        string
+		replicaErr  error
+		ctx         func() context.Context
+		query       func(ctx context.Context, store *ReplicaStore) error
+		wantPrimary int
+		wantReplica int
+		wantErr     error
+		unhealthy   bool
+	}{
+		{
+			name:       "GetFromReplica",
+			replicaErr: pgx.ErrNoRows,
+			ctx:        context.Background,
+			query: func(ctx context.Context, store *ReplicaStore) error {
+				_, err := store.GetIncome(ctx, uuid.New())
+				return err
+			},
+			wantReplica: 1,
+			wantErr:     pgx.ErrNoRows,
+		},
+		{
+			name:       "ListFromReplica",
+			replicaErr: pgx.ErrNoRows,
+			ctx:        context.Background,
+			query: func(ctx context.Context, store *ReplicaStore) error {
+				_, err := store.ListIncomes(ctx, ListIncomesParams{Limit: 5})
+				return err
+			},
+			wantReplica: 1,
+			wantErr:     pgx.ErrNoRows,
+		},
+		{
+			name:       "WriteToPrimary",
+			replicaErr: pgx.ErrNoRows,
+			ctx:        context.Background,
+			query: func(ctx context.Context, store *ReplicaStore) error {
+				_, err := store.DeleteIncome(ctx, uuid.New())
+				return err
+			},
+			wantPrimary: 1,
+		},
+		{
+			name:       "ReadYourWrites",
+			replicaErr: pgx.ErrNoRows,
+			ctx: func() context.Context {
+				return WithPrimary(context.Background())
+			},
+			query: func(ctx context.Context, store *ReplicaStore) error {
+				_, err := store.GetLoan(ctx, uuid.New())
+				return err
+			},
+			wantPrimary: 1,
+		},
+		{
+			name:       "FallbackOnConnectionError",
+			replicaErr: errConnRefused,
+			ctx:        context.Background,
+			query: func(ctx context.Context, store *ReplicaStore) error {
+				_, err := store.GetUser(ctx, "user")
+				return err
+			},
+			wantPrimary: 1,
+			wantReplica: 1,
+			unhealthy:   true,
+		},
+		{
+			name:       "NoFallbackOnQueryError",
+			replicaErr: errUndefinedColumn,
+			ctx:        context.Background,
+			query: func(ctx context.Context, store *ReplicaStore) error {
+				_, err := store.GetUser(ctx, "user")
+				return err
+			},
+			wantReplica: 1,
+			wantErr:     errUndefinedColumn,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			primary := &fakeDB{}
+			replica := &fakeDB{err: tc.replicaErr}
+			store := newReplicaStore(&SQLStore{Queries: New(primary)}, New(replica))
+
+			err := tc.query(tc.ctx(), store)
+			require.ErrorIs(t, err, tc.wantErr)
+			require.Equal(t, tc.wantPrimary, primary.queries)
+			require.Equal(t, tc.wantReplica, replica.queries)
+			require.Equal(t, !tc.unhealthy, store.replicaHealthy())
+		})
+	}
+}
+
+func TestReplicaStoreBackoff(t *testing.T) {
+	now := time.Now()
+	primary := &fakeDB{}
+	replica := &fakeDB{err: errors.New("conn closed")}
+	store := newReplicaStore(&SQLStore{Queries: New(primary)}, New(replica))
+	store.now = func() time.Time { return now }
+
+	_, err := store.GetProject(context.Background(), uuid.New())
+	require.NoError(t, err)
+	require.Equal(t, 1, replica.queries)
+
+	// Reads skip the replica while it is unhealthy
+	_, err = store.GetProject(context.Background(), uuid.New())
+	require.NoError(t, err)
+	require.Equal(t, 1, replica.queries)
+	require.Equal(t, 2, primary.queries)
+
+	// and try it again once the backoff is over
+	now = now.Add(replicaBackoff)
+	replica.err = pgx.ErrNoRows
+	_, err = store.GetProject(context.Background(), uuid.New())
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+	require.Equal(t, 2, replica.queries)
+}
@@ -0,0 +1,70 @@
+package db
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/lushenle/plam/pkg/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchAll(t *testing.T) {
+	word := util.RandomString(12)
+
+	project, err := testStore.CreateProject(context.Background(), CreateProjectParams{
+		Name:        util.RandomString(6),
+		Description: "budget " + word + " review",
+		Amount:      util.RandomFloat32(0, 1000),
+	})
+	require.NoError(t, err)
+
+	loan, err := testStore.CreateLoan(context.Background(), CreateLoanParams{
+		Borrower: word,
+		Amount:   util.RandomFloat32(0, 1000),
+		Subject:  util.RandomString(20),
+	})
+	require.NoError(t, err)
+
+	hits, err := testStore.SearchAll(context.Background(), SearchAllParams{Query: word, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, hits, 2)
+
+	// The borrower matches both the full-text and the trigram search so it ranks first
+	require.Equal(t, "loan", hits[0].Resource)
+	require.Equal(t, loan.ID, hits[0].ID)
+	require.Equal(t, word, hits[0].Title)
+	require.Greater(t, hits[0].Rank, hits[1].Rank)
+
+	require.Equal(t, "project", hits[1].Resource)
+	require.Equal(t, project.ID, hits[1].ID)
+	require.Equal(t, project.Name, hits[1].Title)
+	require.Contains(t, hits[1].Snippet, "<mark>"+word+"</mark>")
+
+	hits, err = testStore.SearchAll(context.Background(), SearchAllParams{Query: word, Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, hits, 1)
+}
+
+func TestSearchAllEscapesSnippet(t *testing.T) {
+	word := util.RandomString(12)
+
+	payOut, err := testStore.CreatePayOut(context.Background(), CreatePayOutParams{
+		Owner:   util.RandomString(10),
+		Amount:  util.RandomFloat32(0, 100),
+		Subject: `<img src=x onerror="alert('` + word + `')"> R&D ` + word,
+	})
+	require.NoError(t, err)
+
+	hits, err := testStore.SearchAll(context.Background(), SearchAllParams{Query: word, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, hits, 1)
+	require.Equal(t, payOut.ID, hits[0].ID)
+
+	// The record text is escaped, only the highlighting is markup
+	snippet := hits[0].Snippet
+	require.NotContains(t, snippet, "<img")
+	require.Contains(t, snippet, "&lt;img src=x onerror=&quot;alert(&#39;")
+	require.Contains(t, snippet, "R&amp;D <mark>"+word+"</mark>")
+	require.Equal(t, strings.Count(snippet, "<"), 2*strings.Count(snippet, "<mark>"))
+}
@@ -1,12 +1,32 @@
 package db
 
 import (
+	"context"
+
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 // Store provides all functions to execute db queries and transactions
 type Store interface {
 	Querier
+	Filterer
+	ChangePasswordTx(ctx context.Context, arg ChangePasswordTxParams) (ChangePasswordTxResult, error)
+	CreateUserWithInvitationTx(ctx context.Context, arg CreateUserWithInvitationTxParams) (CreateUserWithInvitationTxResult, error)
+	LoginOIDCUserTx(ctx context.Context, arg LoginOIDCUserTxParams) (LoginOIDCUserTxResult, error)
+	Ping(ctx context.Context) error
+	MigrationVersion(ctx context.Context) (version int64, dirty bool, err error)
+}
+
+// Filterer runs the list queries built at runtime from a ListFilter
+type Filterer interface {
+	CountFilterIncomes(ctx context.Context, filter ListFilter) (int64, error)
+	CountFilterLoans(ctx context.Context, filter ListFilter) (int64, error)
+	CountFilterPayOuts(ctx context.Context, filter ListFilter) (int64, error)
+	CountFilterProjects(ctx context.Context, filter ListFilter) (int64, error)
+	FilterIncomes(ctx context.Context, arg FilterParams) ([]Income, error)
+	FilterLoans(ctx context.Context, arg FilterParams) ([]Loan, error)
+	FilterPayOuts(ctx context.Context, arg FilterParams) ([]PayOut, error)
+	FilterProjects(ctx context.Context, arg FilterParams) ([]Project, error)
 }
 
 // SQLStore provides all functions to execute db queries and transactions
@@ -0,0 +1,104 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName is the instrumentation scope of the query spans
+const tracerName = "github.com/lushenle/plam/pkg/db"
+
+// sqlcNamePrefix starts the comment sqlc puts before every generated query
+const sqlcNamePrefix = "-- name: "
+
+// queryDuration is served on /metrics with the other metrics of the default registry
+var queryDuration = newQueryDuration(promauto.With(prometheus.DefaultRegisterer))
+
+func newQueryDuration(factory promauto.Factory) *prometheus.HistogramVec {
+	return factory.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "plam",
+		Subsystem: "db",
+		Name:      "query_duration_seconds",
+		Help:      "Duration of the database queries, by sqlc query name.",
+		Buckets:   []float64{0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5},
+	}, []string{"query"})
+}
+
+// queryStartKey holds the queryStart of the query in progress
+type queryStartKey struct{}
+
+type queryStart struct {
+	name string
+	time time.Time
+}
+
+// QueryTracer is a pgx.QueryTracer recording a span for every query, named after the
+// sqlc query, and the duration of the query in the plam_db_query_duration_seconds histogram.
+// It uses the global tracer provider, so it records no span until tracing is set up.
+type QueryTracer struct {
+	tracer   trace.Tracer
+	duration *prometheus.HistogramVec
+}
+
+var _ pgx.QueryTracer = (*QueryTracer)(nil)
+
+// NewQueryTracer creates a QueryTracer, set it as the Tracer of the pool connection config
+func NewQueryTracer() *QueryTracer {
+	return &QueryTracer{tracer: otel.Tracer(tracerName), duration: queryDuration}
+}
+
+// TraceQueryStart starts the span and the timer of a query
+func (t *QueryTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	name := QueryName(data.SQL)
+	ctx = context.WithValue(ctx, queryStartKey{}, queryStart{name: name, time: time.Now()})
+	ctx, _ = t.tracer.Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.DBSystemPostgreSQL,
+			semconv.DBOperation(name),
+			semconv.DBStatement(data.SQL),
+		),
+	)
+	return ctx
+}
+
+// TraceQueryEnd ends the span and observes the duration of a query, a query finding no row is not an error
+func (t *QueryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	if start, ok := ctx.Value(queryStartKey{}).(queryStart); ok {
+		t.duration.WithLabelValues(start.name).Observe(time.Since(start.time).Seconds())
+	}
+
+	span := trace.SpanFromContext(ctx)
+	if data.Err != nil && !errors.Is(data.Err, pgx.ErrNoRows) {
+		span.RecordError(data.Err)
+		span.SetStatus(codes.Error, data.Err.Error())
+	} else {
+		span.SetAttributes(attribute.Int64("db.rows_affected", data.CommandTag.RowsAffected()))
+	}
+	span.End()
+}
+
+// QueryName returns the sqlc name of a query, e.g. GetUser, or its SQL command, e.g. SELECT,
+// for the queries built at runtime
+func QueryName(sql string) string {
+	sql = strings.TrimSpace(sql)
+	if rest, ok := strings.CutPrefix(sql, sqlcNamePrefix); ok {
+		if name, _, ok := strings.Cut(rest, " "); ok && name != "" {
+			return name
+		}
+	}
+
+	command, _, _ := strings.Cut(sql, " ")
+	return strings.ToUpper(command)
+}
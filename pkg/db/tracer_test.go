@@ -0,0 +1,86 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestQueryName(t *testing.T) {
+	require.Equal(t, "GetUser", QueryName(getUser))
+	require.Equal(t, "UpdateUserLocale", QueryName(updateUserLocale))
+	require.Equal(t, "SELECT", QueryName("SELECT id FROM income WHERE amount >= $1"))
+	require.Equal(t, "SELECT", QueryName("  select 1"))
+	require.Equal(t, "", QueryName(""))
+
+	// The queries built by the Filter* and CountFilter* methods are named too
+	query, _, err := buildFilterQuery(loanTable, FilterParams{Limit: 5})
+	require.NoError(t, err)
+	require.Equal(t, "FilterLoans", QueryName(query))
+	query, _ = buildCountQuery(payOutTable, ListFilter{})
+	require.Equal(t, "CountFilterPayOuts", QueryName(query))
+}
+
+func TestQueryTracer(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	registry := prometheus.NewPedanticRegistry()
+	duration := newQueryDuration(promauto.With(registry))
+	tracer := &QueryTracer{tracer: provider.Tracer(tracerName), duration: duration}
+
+	ctx := tracer.TraceQueryStart(context.Background(), nil, pgx.TraceQueryStartData{SQL: getUser})
+	tracer.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{Err: pgx.ErrNoRows})
+
+	ctx = tracer.TraceQueryStart(context.Background(), nil, pgx.TraceQueryStartData{SQL: deleteIncome})
+	tracer.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{CommandTag: pgconn.NewCommandTag("DELETE 1")})
+
+	ctx = tracer.TraceQueryStart(context.Background(), nil, pgx.TraceQueryStartData{SQL: createUser})
+	tracer.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{Err: errors.New("conn closed")})
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 3)
+
+	require.Equal(t, "GetUser", spans[0].Name())
+	require.Equal(t, codes.Unset, spans[0].Status().Code)
+
+	require.Equal(t, "DeleteIncome", spans[1].Name())
+	require.Contains(t, spans[1].Attributes(), attribute.Int64("db.rows_affected", 1))
+
+	require.Equal(t, "CreateUser", spans[2].Name())
+	require.Equal(t, codes.Error, spans[2].Status().Code)
+	require.Len(t, spans[2].Events(), 1)
+
+	filterIncomes, _, err := buildFilterQuery(incomeTable, FilterParams{Limit: 5})
+	require.NoError(t, err)
+	ctx = tracer.TraceQueryStart(context.Background(), nil, pgx.TraceQueryStartData{SQL: filterIncomes})
+	tracer.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{CommandTag: pgconn.NewCommandTag("SELECT 5")})
+
+	spans = recorder.Ended()
+	require.Len(t, spans, 4)
+	require.Equal(t, "FilterIncomes", spans[3].Name())
+
+	// A series per query name
+	require.Equal(t, 4, testutil.CollectAndCount(duration, "plam_db_query_duration_seconds"))
+	families, err := registry.Gather()
+	require.NoError(t, err)
+	require.Len(t, families, 1)
+	var queries []string
+	for _, metric := range families[0].GetMetric() {
+		queries = append(queries, metric.GetLabel()[0].GetValue())
+	}
+	require.ElementsMatch(t, []string{"GetUser", "DeleteIncome", "CreateUser", "FilterIncomes"}, queries)
+	problems, err := testutil.GatherAndLint(registry)
+	require.NoError(t, err)
+	require.Empty(t, problems)
+}
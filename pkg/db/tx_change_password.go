@@ -0,0 +1,40 @@
+package db
+
+import "context"
+
+// ChangePasswordTxParams contains the input parameters of the change password transaction
+type ChangePasswordTxParams struct {
+	Username          string
+	OldHashedPassword string
+	NewHashedPassword string
+}
+
+// ChangePasswordTxResult is the result of the change password transaction
+type ChangePasswordTxResult struct {
+	User User
+}
+
+// ChangePasswordTx updates the password of a user and records the old hash in the password history
+func (store *SQLStore) ChangePasswordTx(ctx context.Context, arg ChangePasswordTxParams) (ChangePasswordTxResult, error) {
+	var result ChangePasswordTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		_, err = q.CreatePasswordHistory(ctx, CreatePasswordHistoryParams{
+			Username:       arg.Username,
+			HashedPassword: arg.OldHashedPassword,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.User, err = q.UpdateUserPassword(ctx, UpdateUserPasswordParams{
+			Username:       arg.Username,
+			HashedPassword: arg.NewHashedPassword,
+		})
+		return err
+	})
+
+	return result, err
+}
@@ -0,0 +1,55 @@
+package db
+
+import (
+	"context"
+	"errors"
+)
+
+// CreateUserWithInvitationTxParams contains the input parameters of the create user with invitation transaction
+type CreateUserWithInvitationTxParams struct {
+	CreateUserParams
+	InvitationCode string
+}
+
+// CreateUserWithInvitationTxResult is the result of the create user with invitation transaction
+type CreateUserWithInvitationTxResult struct {
+	User       User
+	Invitation Invitation
+}
+
+// CreateUserWithInvitationTx creates a new user and consumes the invitation bound to the user's email,
+// the user gets the role of the invitation
+func (store *SQLStore) CreateUserWithInvitationTx(ctx context.Context, arg CreateUserWithInvitationTxParams) (CreateUserWithInvitationTxResult, error) {
+	var result CreateUserWithInvitationTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		result.User, err = q.CreateUser(ctx, arg.CreateUserParams)
+		if err != nil {
+			return err
+		}
+
+		result.Invitation, err = q.ConsumeInvitation(ctx, ConsumeInvitationParams{
+			Code:   arg.InvitationCode,
+			Email:  arg.Email,
+			UsedBy: result.User.Username,
+		})
+		if err != nil {
+			if errors.Is(err, ErrRecordNotFound) {
+				return ErrInvalidInvitation
+			}
+			return err
+		}
+
+		if result.User.Role != result.Invitation.Role {
+			result.User, err = q.UpdateUserRole(ctx, UpdateUserRoleParams{
+				Username: result.User.Username,
+				Role:     result.Invitation.Role,
+			})
+		}
+		return err
+	})
+
+	return result, err
+}
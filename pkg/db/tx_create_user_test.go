@@ -0,0 +1,51 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lushenle/plam/pkg/util"
+	"github.com/stretchr/testify/require"
+)
+
+func randomCreateUserParams(t *testing.T, email string) CreateUserParams {
+	hashedPassword, err := util.HashPassword(util.RandomString(6))
+	require.NoError(t, err)
+
+	return CreateUserParams{
+		Username:       util.RandomString(6),
+		Role:           util.RoleUser,
+		HashedPassword: hashedPassword,
+		FullName:       util.RandomString(6),
+		Email:          email,
+	}
+}
+
+func TestCreateUserWithInvitationTx(t *testing.T) {
+	invitation := createRandomInvitation(t, time.Now().Add(time.Hour))
+
+	result, err := testStore.CreateUserWithInvitationTx(context.Background(), CreateUserWithInvitationTxParams{
+		CreateUserParams: randomCreateUserParams(t, invitation.Email),
+		InvitationCode:   invitation.Code,
+	})
+	require.NoError(t, err)
+	require.Equal(t, invitation.Email, result.User.Email)
+	require.Equal(t, invitation.Role, result.User.Role)
+	require.Equal(t, result.User.Username, result.Invitation.UsedBy)
+}
+
+func TestCreateUserWithInvitationTxInvalid(t *testing.T) {
+	invitation := createRandomInvitation(t, time.Now().Add(-time.Minute))
+
+	arg := randomCreateUserParams(t, invitation.Email)
+	_, err := testStore.CreateUserWithInvitationTx(context.Background(), CreateUserWithInvitationTxParams{
+		CreateUserParams: arg,
+		InvitationCode:   invitation.Code,
+	})
+	require.ErrorIs(t, err, ErrInvalidInvitation)
+
+	// The user is rolled back together with the invitation
+	_, err = testStore.GetUser(context.Background(), arg.Username)
+	require.ErrorIs(t, err, ErrRecordNotFound)
+}
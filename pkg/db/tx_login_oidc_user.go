@@ -0,0 +1,135 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+)
+
+// maxUsernameAttempts is the number of suffixed usernames tried before giving up
+const maxUsernameAttempts = 5
+
+// ErrProvisioningDisabled is returned when no user is linked to an identity and Provision is not set
+var ErrProvisioningDisabled = errors.New("no user is linked to the identity and signup is not open")
+
+// ErrEmailRequired is returned when a user would be provisioned from an identity without an email,
+// users.email is unique so a second empty email would conflict
+var ErrEmailRequired = errors.New("identity provider did not provide an email")
+
+// LoginOIDCUserTxParams contains the input parameters of the OIDC login transaction
+type LoginOIDCUserTxParams struct {
+	Issuer   string
+	Subject  string
+	Username string
+	FullName string
+	Email    string
+	Role     string
+	// LinkByEmail allows linking the identity to an existing user with the same email,
+	// it must only be set when the identity provider has verified the email
+	LinkByEmail bool
+	// Provision allows creating a user when none is linked to the identity
+	Provision bool
+	// HashedPassword is stored for provisioned users, it should not be derivable from any known password
+	HashedPassword string
+}
+
+// LoginOIDCUserTxResult is the result of the OIDC login transaction
+type LoginOIDCUserTxResult struct {
+	User        User
+	Provisioned bool
+}
+
+// LoginOIDCUserTx finds the user linked to an identity provider subject, linking an
+// existing user by email or provisioning a new one when needed. Only the role of the users
+// it provisioned is synced, the role of a linked local user is never changed.
+// It returns ErrProvisioningDisabled or ErrEmailRequired when a user cannot be provisioned.
+func (store *SQLStore) LoginOIDCUserTx(ctx context.Context, arg LoginOIDCUserTxParams) (LoginOIDCUserTxResult, error) {
+	var result LoginOIDCUserTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		identity, err := q.GetUserIdentity(ctx, GetUserIdentityParams{
+			Issuer:  arg.Issuer,
+			Subject: arg.Subject,
+		})
+		switch {
+		case err == nil:
+			result.User, err = q.GetUser(ctx, identity.Username)
+			if err != nil || !identity.Provisioned || result.User.Role == arg.Role {
+				return err
+			}
+			result.User, err = q.UpdateUserRole(ctx, UpdateUserRoleParams{
+				Username: result.User.Username,
+				Role:     arg.Role,
+			})
+			return err
+		case errors.Is(err, ErrRecordNotFound):
+			result.User, result.Provisioned, err = linkOrProvisionUser(ctx, q, arg)
+			if err != nil {
+				return err
+			}
+
+			_, err = q.CreateUserIdentity(ctx, CreateUserIdentityParams{
+				Issuer:      arg.Issuer,
+				Subject:     arg.Subject,
+				Username:    result.User.Username,
+				Provisioned: result.Provisioned,
+			})
+			return err
+		default:
+			return err
+		}
+	})
+
+	return result, err
+}
+
+func linkOrProvisionUser(ctx context.Context, q *Queries, arg LoginOIDCUserTxParams) (User, bool, error) {
+	if arg.LinkByEmail {
+		user, err := q.GetUserByEmail(ctx, arg.Email)
+		if err == nil {
+			return user, false, nil
+		}
+		if !errors.Is(err, ErrRecordNotFound) {
+			return User{}, false, err
+		}
+	}
+
+	if !arg.Provision {
+		return User{}, false, ErrProvisioningDisabled
+	}
+	if arg.Email == "" {
+		return User{}, false, ErrEmailRequired
+	}
+
+	username, err := availableUsername(ctx, q, arg.Username)
+	if err != nil {
+		return User{}, false, err
+	}
+
+	user, err := q.CreateUser(ctx, CreateUserParams{
+		Username:       username,
+		Role:           arg.Role,
+		HashedPassword: arg.HashedPassword,
+		FullName:       arg.FullName,
+		Email:          arg.Email,
+	})
+	return user, true, err
+}
+
+// availableUsername returns username, or username with a numeric suffix when it is already taken
+func availableUsername(ctx context.Context, q *Queries, username string) (string, error) {
+	candidate := username
+	for i := 0; i < maxUsernameAttempts; i++ {
+		_, err := q.GetUser(ctx, candidate)
+		if errors.Is(err, ErrRecordNotFound) {
+			return candidate, nil
+		}
+		if err != nil {
+			return "", err
+		}
+		candidate = fmt.Sprintf("%s%d", username, rand.Intn(10000))
+	}
+
+	return "", fmt.Errorf("cannot find an available username for %s", username)
+}
@@ -0,0 +1,160 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/lushenle/plam/pkg/util"
+	"github.com/stretchr/testify/require"
+)
+
+func randomLoginOIDCUserTxParams(t *testing.T) LoginOIDCUserTxParams {
+	hashedPassword, err := util.HashPassword(util.RandomString(32))
+	require.NoError(t, err)
+
+	return LoginOIDCUserTxParams{
+		Issuer:         "https://" + util.RandomString(6) + ".com",
+		Subject:        util.RandomString(10),
+		Username:       util.RandomString(6),
+		FullName:       util.RandomString(6),
+		Email:          util.RandomEmail(),
+		Role:           util.RoleUser,
+		Provision:      true,
+		HashedPassword: hashedPassword,
+	}
+}
+
+func TestLoginOIDCUserTxProvision(t *testing.T) {
+	arg := randomLoginOIDCUserTxParams(t)
+
+	result1, err := testStore.LoginOIDCUserTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.True(t, result1.Provisioned)
+	require.Equal(t, arg.Username, result1.User.Username)
+	require.Equal(t, arg.Email, result1.User.Email)
+	require.Equal(t, util.RoleUser, result1.User.Role)
+
+	identity, err := testStore.GetUserIdentity(context.Background(), GetUserIdentityParams{
+		Issuer:  arg.Issuer,
+		Subject: arg.Subject,
+	})
+	require.NoError(t, err)
+	require.True(t, identity.Provisioned)
+
+	// The second login finds the linked user and syncs the role
+	arg.Role = util.RoleAdmin
+	result2, err := testStore.LoginOIDCUserTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.False(t, result2.Provisioned)
+	require.Equal(t, result1.User.Username, result2.User.Username)
+	require.Equal(t, util.RoleAdmin, result2.User.Role)
+}
+
+func TestLoginOIDCUserTxLinkByEmail(t *testing.T) {
+	user := createRandomUser(t)
+
+	arg := randomLoginOIDCUserTxParams(t)
+	arg.Email = user.Email
+	arg.LinkByEmail = true
+
+	result, err := testStore.LoginOIDCUserTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.False(t, result.Provisioned)
+	require.Equal(t, user.Username, result.User.Username)
+	require.Equal(t, user.HashedPassword, result.User.HashedPassword)
+
+	identity, err := testStore.GetUserIdentity(context.Background(), GetUserIdentityParams{
+		Issuer:  arg.Issuer,
+		Subject: arg.Subject,
+	})
+	require.NoError(t, err)
+	require.Equal(t, user.Username, identity.Username)
+	require.False(t, identity.Provisioned)
+}
+
+func TestLoginOIDCUserTxKeepsLocalRole(t *testing.T) {
+	user := createRandomUser(t)
+	admin, err := testStore.UpdateUserRole(context.Background(), UpdateUserRoleParams{
+		Username: user.Username,
+		Role:     util.RoleAdmin,
+	})
+	require.NoError(t, err)
+
+	arg := randomLoginOIDCUserTxParams(t)
+	arg.Email = admin.Email
+	arg.LinkByEmail = true
+	arg.Role = util.RoleUser
+
+	// Neither the login linking the local admin nor the following ones downgrade it
+	for i := 0; i < 2; i++ {
+		result, err := testStore.LoginOIDCUserTx(context.Background(), arg)
+		require.NoError(t, err)
+		require.Equal(t, admin.Username, result.User.Username)
+		require.Equal(t, util.RoleAdmin, result.User.Role)
+	}
+
+	stored, err := testStore.GetUser(context.Background(), admin.Username)
+	require.NoError(t, err)
+	require.Equal(t, util.RoleAdmin, stored.Role)
+}
+
+func TestLoginOIDCUserTxUnverifiedEmail(t *testing.T) {
+	user := createRandomUser(t)
+
+	// Without a verified email the identity is not linked, the provisioning conflicts instead
+	arg := randomLoginOIDCUserTxParams(t)
+	arg.Email = user.Email
+
+	_, err := testStore.LoginOIDCUserTx(context.Background(), arg)
+	require.Equal(t, UniqueViolation, ErrorCode(err))
+
+	_, err = testStore.GetUserIdentity(context.Background(), GetUserIdentityParams{
+		Issuer:  arg.Issuer,
+		Subject: arg.Subject,
+	})
+	require.ErrorIs(t, err, ErrRecordNotFound)
+}
+
+func TestLoginOIDCUserTxProvisioningDisabled(t *testing.T) {
+	arg := randomLoginOIDCUserTxParams(t)
+	arg.Provision = false
+
+	_, err := testStore.LoginOIDCUserTx(context.Background(), arg)
+	require.ErrorIs(t, err, ErrProvisioningDisabled)
+
+	// An existing user is still linked by a verified email
+	user := createRandomUser(t)
+	arg.Email = user.Email
+	arg.LinkByEmail = true
+
+	result, err := testStore.LoginOIDCUserTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, user.Username, result.User.Username)
+}
+
+func TestLoginOIDCUserTxUsernameTaken(t *testing.T) {
+	user := createRandomUser(t)
+
+	arg := randomLoginOIDCUserTxParams(t)
+	arg.Username = user.Username
+
+	result, err := testStore.LoginOIDCUserTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.True(t, result.Provisioned)
+	require.NotEqual(t, user.Username, result.User.Username)
+}
+
+func TestLoginOIDCUserTxMissingEmail(t *testing.T) {
+	arg := randomLoginOIDCUserTxParams(t)
+	arg.Email = ""
+
+	_, err := testStore.LoginOIDCUserTx(context.Background(), arg)
+	require.ErrorIs(t, err, ErrEmailRequired)
+
+	// The identity is not linked when the provisioning fails
+	_, err = testStore.GetUserIdentity(context.Background(), GetUserIdentityParams{
+		Issuer:  arg.Issuer,
+		Subject: arg.Subject,
+	})
+	require.ErrorIs(t, err, ErrRecordNotFound)
+}
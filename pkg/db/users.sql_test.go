@@ -14,6 +14,7 @@ func createRandomUser(t *testing.T) User {
 
 	arg := CreateUserParams{
 		Username:       util.RandomString(6),
+		Role:           util.RoleUser,
 		HashedPassword: hashedPassword,
 		FullName:       util.RandomString(6),
 		Email:          util.RandomEmail(),
@@ -52,3 +53,36 @@ func TestGetUser(t *testing.T) {
 	require.WithinDuration(t, user1.CreatedAt, user2.CreatedAt, 0)
 	require.WithinDuration(t, user1.UpdatedAt, user2.UpdatedAt, 0)
 }
+
+func TestUpdateUserPassword(t *testing.T) {
+	user1 := createRandomUser(t)
+
+	hashedPassword, err := util.HashPassword(util.RandomString(6))
+	require.NoError(t, err)
+
+	arg := UpdateUserPasswordParams{
+		Username:       user1.Username,
+		HashedPassword: hashedPassword,
+	}
+	user2, err := testStore.UpdateUserPassword(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotEmpty(t, user2)
+
+	require.Equal(t, user1.Username, user2.Username)
+	require.Equal(t, hashedPassword, user2.HashedPassword)
+	require.True(t, user2.PasswordChangedAt.After(user1.PasswordChangedAt))
+}
+
+func TestUpdateUserLocale(t *testing.T) {
+	user1 := createRandomUser(t)
+	require.Empty(t, user1.Locale)
+
+	user2, err := testStore.UpdateUserLocale(context.Background(), UpdateUserLocaleParams{
+		Username: user1.Username,
+		Locale:   "zh",
+	})
+	require.NoError(t, err)
+	require.Equal(t, user1.Username, user2.Username)
+	require.Equal(t, "zh", user2.Locale)
+	require.True(t, user2.UpdatedAt.After(user1.UpdatedAt))
+}
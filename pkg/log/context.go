@@ -0,0 +1,66 @@
+package log
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// Fields holding the request ID and the trace in log lines
+const (
+	RequestIDField = "request_id"
+	TraceIDField   = "trace_id"
+	SpanIDField    = "span_id"
+)
+
+type contextKey int
+
+const (
+	loggerKey contextKey = iota
+	requestIDKey
+)
+
+// WithLogger returns a copy of ctx carrying logger
+func WithLogger(ctx context.Context, logger *zap.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey, logger)
+}
+
+// WithRequestID returns a copy of ctx carrying the ID of the request it serves
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey, requestID)
+}
+
+// RequestID returns the request ID carried by ctx, empty when there is none
+func RequestID(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey).(string)
+	return requestID
+}
+
+// Fields returns the request ID and the trace and span IDs carried by ctx
+func Fields(ctx context.Context) []zap.Field {
+	var fields []zap.Field
+	if requestID := RequestID(ctx); requestID != "" {
+		fields = append(fields, zap.String(RequestIDField, requestID))
+	}
+	if span := trace.SpanContextFromContext(ctx); span.IsValid() {
+		fields = append(fields,
+			zap.String(TraceIDField, span.TraceID().String()),
+			zap.String(SpanIDField, span.SpanID().String()),
+		)
+	}
+	return fields
+}
+
+// FromContext returns the logger carried by ctx, or the global logger when there is none.
+// Its lines carry the request ID and the trace of ctx, see Fields.
+func FromContext(ctx context.Context) *zap.Logger {
+	logger, ok := ctx.Value(loggerKey).(*zap.Logger)
+	if !ok {
+		logger = zap.L()
+	}
+	if fields := Fields(ctx); len(fields) > 0 {
+		logger = logger.With(fields...)
+	}
+	return logger
+}
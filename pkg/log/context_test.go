@@ -0,0 +1,50 @@
+package log
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestFromContext(t *testing.T) {
+	core, logs := observer.New(zapcore.InfoLevel)
+	ctx := WithLogger(context.Background(), zap.New(core))
+
+	FromContext(ctx).Info("no request")
+	FromContext(WithRequestID(ctx, "req-1")).Info("request")
+
+	entries := logs.AllUntimed()
+	require.Len(t, entries, 2)
+	require.Empty(t, entries[0].Context)
+	require.Equal(t, []zapcore.Field{zap.String(RequestIDField, "req-1")}, entries[1].Context)
+}
+
+func TestFromContextTrace(t *testing.T) {
+	core, logs := observer.New(zapcore.InfoLevel)
+	ctx := WithLogger(context.Background(), zap.New(core))
+
+	traceID := trace.TraceID{1, 2, 3}
+	spanID := trace.SpanID{4, 5, 6}
+	ctx = trace.ContextWithSpanContext(ctx, trace.NewSpanContext(trace.SpanContextConfig{TraceID: traceID, SpanID: spanID}))
+
+	FromContext(WithRequestID(ctx, "req-1")).Info("request")
+
+	entries := logs.AllUntimed()
+	require.Len(t, entries, 1)
+	require.Equal(t, []zapcore.Field{
+		zap.String(RequestIDField, "req-1"),
+		zap.String(TraceIDField, traceID.String()),
+		zap.String(SpanIDField, spanID.String()),
+	}, entries[0].Context)
+}
+
+func TestRequestID(t *testing.T) {
+	require.Empty(t, RequestID(context.Background()))
+	require.Equal(t, "req-1", RequestID(WithRequestID(context.Background(), "req-1")))
+	require.NotNil(t, FromContext(context.Background()))
+}
@@ -0,0 +1,209 @@
+package sso
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/lushenle/plam/pkg/util"
+	"golang.org/x/oauth2"
+)
+
+const defaultGroupsClaim = "groups"
+
+// Different types of error returned by the Provider
+var (
+	ErrNotAuthorized = errors.New("user is not a member of any authorized group")
+	ErrInvalidNonce  = errors.New("id token nonce does not match")
+	ErrMissingToken  = errors.New("token response does not contain an id_token")
+)
+
+// Provider performs the OpenID Connect authorization code flow with PKCE
+type Provider struct {
+	config   util.OIDC
+	oauth2   oauth2.Config
+	verifier *oidc.IDTokenVerifier
+}
+
+// NewProvider discovers the identity provider configuration from its issuer URL
+func NewProvider(ctx context.Context, config util.OIDC) (*Provider, error) {
+	provider, err := oidc.NewProvider(ctx, config.IssuerURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover oidc provider: %w", err)
+	}
+
+	scopes := append([]string{oidc.ScopeOpenID}, config.Scopes...)
+
+	return &Provider{
+		config: config,
+		oauth2: oauth2.Config{
+			ClientID:     config.ClientID,
+			ClientSecret: config.ClientSecret,
+			RedirectURL:  config.RedirectURL,
+			Endpoint:     provider.Endpoint(),
+			Scopes:       scopes,
+		},
+		verifier: provider.Verifier(&oidc.Config{ClientID: config.ClientID}),
+	}, nil
+}
+
+// AuthRequest holds the values of a login attempt that must be kept until the callback
+type AuthRequest struct {
+	URL          string
+	State        string
+	Nonce        string
+	CodeVerifier string
+}
+
+// NewAuthRequest creates the authorization URL for a new login attempt
+func (p *Provider) NewAuthRequest() (*AuthRequest, error) {
+	state, err := util.RandomToken(32)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce, err := util.RandomToken(32)
+	if err != nil {
+		return nil, err
+	}
+
+	codeVerifier := oauth2.GenerateVerifier()
+
+	return &AuthRequest{
+		URL:          p.oauth2.AuthCodeURL(state, oidc.Nonce(nonce), oauth2.S256ChallengeOption(codeVerifier)),
+		State:        state,
+		Nonce:        nonce,
+		CodeVerifier: codeVerifier,
+	}, nil
+}
+
+// Identity is the verified identity of the logged-in user
+type Identity struct {
+	Issuer            string
+	Subject           string
+	Email             string
+	EmailVerified     bool
+	Name              string
+	PreferredUsername string
+	Groups            []string
+}
+
+// Exchange exchanges the authorization code for tokens and verifies the returned ID token
+func (p *Provider) Exchange(ctx context.Context, code, codeVerifier, nonce string) (*Identity, error) {
+	oauth2Token, err := p.oauth2.Exchange(ctx, code, oauth2.VerifierOption(codeVerifier))
+	if err != nil {
+		return nil, fmt.Errorf("failed to exchange authorization code: %w", err)
+	}
+
+	rawIDToken, ok := oauth2Token.Extra("id_token").(string)
+	if !ok {
+		return nil, ErrMissingToken
+	}
+
+	idToken, err := p.verifier.Verify(ctx, rawIDToken)
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify id token: %w", err)
+	}
+
+	if idToken.Nonce != nonce {
+		return nil, ErrInvalidNonce
+	}
+
+	var claims struct {
+		Email             string `json:"email"`
+		EmailVerified     bool   `json:"email_verified"`
+		Name              string `json:"name"`
+		PreferredUsername string `json:"preferred_username"`
+	}
+	if err = idToken.Claims(&claims); err != nil {
+		return nil, fmt.Errorf("failed to parse id token claims: %w", err)
+	}
+
+	var rawClaims map[string]any
+	if err = idToken.Claims(&rawClaims); err != nil {
+		return nil, fmt.Errorf("failed to parse id token claims: %w", err)
+	}
+
+	return &Identity{
+		Issuer:            idToken.Issuer,
+		Subject:           idToken.Subject,
+		Email:             claims.Email,
+		EmailVerified:     claims.EmailVerified,
+		Name:              claims.Name,
+		PreferredUsername: claims.PreferredUsername,
+		Groups:            groupsFromClaim(rawClaims[p.groupsClaim()]),
+	}, nil
+}
+
+// Role maps the identity provider groups to a PLAM role
+func (p *Provider) Role(groups []string) (string, error) {
+	if intersects(groups, p.config.AdminGroups) {
+		return util.RoleAdmin, nil
+	}
+
+	if len(p.config.UserGroups) == 0 || intersects(groups, p.config.UserGroups) {
+		return util.RoleUser, nil
+	}
+
+	return "", ErrNotAuthorized
+}
+
+func (p *Provider) groupsClaim() string {
+	if p.config.GroupsClaim == "" {
+		return defaultGroupsClaim
+	}
+	return p.config.GroupsClaim
+}
+
+// Username returns an alphanumeric username derived from the identity
+func (identity *Identity) Username() string {
+	local, _, _ := strings.Cut(identity.Email, "@")
+	for _, candidate := range []string{identity.PreferredUsername, local, identity.Subject} {
+		if username := alphanumeric(candidate); username != "" {
+			return username
+		}
+	}
+
+	return "user"
+}
+
+func alphanumeric(s string) string {
+	var sb strings.Builder
+	for _, r := range s {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+func groupsFromClaim(claim any) []string {
+	switch v := claim.(type) {
+	case string:
+		return []string{v}
+	case []any:
+		groups := make([]string, 0, len(v))
+		for _, group := range v {
+			if s, ok := group.(string); ok {
+				groups = append(groups, s)
+			}
+		}
+		return groups
+	}
+
+	return nil
+}
+
+func intersects(a, b []string) bool {
+	for _, x := range a {
+		for _, y := range b {
+			if x == y {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,128 @@
+package sso
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/lushenle/plam/pkg/sso/ssotest"
+	"github.com/lushenle/plam/pkg/util"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestProvider(t *testing.T) (*Provider, *ssotest.IdP) {
+	idp, err := ssotest.NewIdP("plam")
+	require.NoError(t, err)
+	t.Cleanup(idp.Close)
+
+	config := util.OIDC{
+		Enabled:     true,
+		IssuerURL:   idp.URL,
+		ClientID:    idp.ClientID,
+		RedirectURL: "http://localhost:8080/v1/auth/oidc/callback",
+		Scopes:      []string{"email", "profile"},
+		AdminGroups: []string{"plam-admins"},
+		UserGroups:  []string{"plam-users"},
+	}
+
+	provider, err := NewProvider(context.Background(), config)
+	require.NoError(t, err)
+
+	return provider, idp
+}
+
+// authorize follows the authorization URL and returns the callback query
+func authorize(t *testing.T, authURL string) url.Values {
+	client := &http.Client{
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	rsp, err := client.Get(authURL)
+	require.NoError(t, err)
+	defer rsp.Body.Close()
+	require.Equal(t, http.StatusFound, rsp.StatusCode)
+
+	location, err := url.Parse(rsp.Header.Get("Location"))
+	require.NoError(t, err)
+
+	return location.Query()
+}
+
+func TestProviderExchange(t *testing.T) {
+	provider, idp := newTestProvider(t)
+
+	claims := ssotest.Claims{
+		Subject:           util.RandomString(10),
+		Email:             util.RandomEmail(),
+		EmailVerified:     true,
+		Name:              util.RandomString(8),
+		PreferredUsername: "john.doe",
+		Groups:            []string{"plam-admins"},
+	}
+	idp.SetClaims(claims)
+
+	authRequest, err := provider.NewAuthRequest()
+	require.NoError(t, err)
+
+	callback := authorize(t, authRequest.URL)
+	require.Equal(t, authRequest.State, callback.Get("state"))
+
+	identity, err := provider.Exchange(context.Background(), callback.Get("code"), authRequest.CodeVerifier, authRequest.Nonce)
+	require.NoError(t, err)
+	require.Equal(t, idp.URL, identity.Issuer)
+	require.Equal(t, claims.Subject, identity.Subject)
+	require.Equal(t, claims.Email, identity.Email)
+	require.True(t, identity.EmailVerified)
+	require.Equal(t, claims.Name, identity.Name)
+	require.Equal(t, claims.Groups, identity.Groups)
+	require.Equal(t, "johndoe", identity.Username())
+}
+
+func TestProviderExchangeInvalidVerifier(t *testing.T) {
+	provider, idp := newTestProvider(t)
+	idp.SetClaims(ssotest.Claims{Subject: util.RandomString(10)})
+
+	authRequest, err := provider.NewAuthRequest()
+	require.NoError(t, err)
+
+	callback := authorize(t, authRequest.URL)
+	_, err = provider.Exchange(context.Background(), callback.Get("code"), "invalid-verifier", authRequest.Nonce)
+	require.Error(t, err)
+}
+
+func TestProviderExchangeInvalidNonce(t *testing.T) {
+	provider, idp := newTestProvider(t)
+	idp.SetClaims(ssotest.Claims{Subject: util.RandomString(10)})
+
+	authRequest, err := provider.NewAuthRequest()
+	require.NoError(t, err)
+
+	callback := authorize(t, authRequest.URL)
+	_, err = provider.Exchange(context.Background(), callback.Get("code"), authRequest.CodeVerifier, "invalid-nonce")
+	require.ErrorIs(t, err, ErrInvalidNonce)
+}
+
+func TestProviderRole(t *testing.T) {
+	provider, _ := newTestProvider(t)
+
+	role, err := provider.Role([]string{"plam-users", "plam-admins"})
+	require.NoError(t, err)
+	require.Equal(t, util.RoleAdmin, role)
+
+	role, err = provider.Role([]string{"plam-users"})
+	require.NoError(t, err)
+	require.Equal(t, util.RoleUser, role)
+
+	_, err = provider.Role([]string{"others"})
+	require.ErrorIs(t, err, ErrNotAuthorized)
+}
+
+func TestIdentityUsername(t *testing.T) {
+	require.Equal(t, "johndoe", (&Identity{PreferredUsername: "john.doe"}).Username())
+	require.Equal(t, "jdoe", (&Identity{Email: "j-doe@example.com"}).Username())
+	require.Equal(t, "abc123", (&Identity{Subject: "abc|123"}).Username())
+	require.Equal(t, "user", (&Identity{}).Username())
+}
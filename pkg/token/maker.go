@@ -6,8 +6,9 @@ import (
 
 // Maker is an interface for managing tokens
 type Maker interface {
-	// CreateToken creates a new token for a specific username and duration
-	CreateToken(username, role string, duration time.Duration) (string, *Payload, error)
+	// CreateToken creates a new token for a specific username and duration,
+	// opts set the optional claims
+	CreateToken(username, role string, duration time.Duration, opts ...PayloadOption) (string, *Payload, error)
 
 	// VerifyToken checks if the token is valid or not
 	VerifyToken(token string) (*Payload, error)
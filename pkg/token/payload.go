@@ -20,10 +20,21 @@ type Payload struct {
 	Role      string    `json:"role"`
 	IssuedAt  time.Time `json:"issued_at"`
 	ExpiredAt time.Time `json:"expired_at"`
+	Locale    string    `json:"locale,omitempty"`
+}
+
+// PayloadOption sets an optional claim of a payload
+type PayloadOption func(payload *Payload)
+
+// WithLocale sets the preferred locale of the user
+func WithLocale(locale string) PayloadOption {
+	return func(payload *Payload) {
+		payload.Locale = locale
+	}
 }
 
 // NewPayload creates a new token payload with a username and duration
-func NewPayload(username, role string, duration time.Duration) (*Payload, error) {
+func NewPayload(username, role string, duration time.Duration, opts ...PayloadOption) (*Payload, error) {
 	tokenID, err := uuid.NewRandom()
 	if err != nil {
 		return nil, err
@@ -36,6 +47,9 @@ func NewPayload(username, role string, duration time.Duration) (*Payload, error)
 		IssuedAt:  time.Now(),
 		ExpiredAt: time.Now().Add(duration),
 	}
+	for _, opt := range opts {
+		opt(payload)
+	}
 
 	return payload, nil
 }
@@ -0,0 +1,54 @@
+package tracing
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lushenle/plam/pkg/util"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+)
+
+func TestSetupDisabled(t *testing.T) {
+	for _, exporter := range []string{"", util.TracingExporterNone} {
+		shutdown, err := Setup(context.Background(), util.Tracing{Exporter: exporter})
+		require.NoError(t, err)
+		require.NoError(t, shutdown(context.Background()))
+	}
+}
+
+func TestSetupFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "traces.json")
+	shutdown, err := Setup(context.Background(), util.Tracing{
+		Exporter:    util.TracingExporterFile,
+		File:        file,
+		ServiceName: "plam-test",
+		SampleRatio: 1,
+	})
+	require.NoError(t, err)
+
+	_, span := otel.Tracer("test").Start(context.Background(), "GET /v1/projects")
+	require.True(t, span.SpanContext().IsSampled())
+	span.End()
+
+	// Shutting down flushes the batched spans
+	require.NoError(t, shutdown(context.Background()))
+
+	data, err := os.ReadFile(file)
+	require.NoError(t, err)
+	require.Contains(t, string(data), `"Name":"GET /v1/projects"`)
+	require.Contains(t, string(data), `"plam-test"`)
+}
+
+func TestSetupInvalid(t *testing.T) {
+	_, err := Setup(context.Background(), util.Tracing{Exporter: "jaeger"})
+	require.Error(t, err)
+
+	_, err = Setup(context.Background(), util.Tracing{
+		Exporter: util.TracingExporterFile,
+		File:     filepath.Join(t.TempDir(), "missing", "traces.json"),
+	})
+	require.Error(t, err)
+}
@@ -1,6 +1,11 @@
 package util
 
 import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
 	"time"
 
 	"github.com/spf13/viper"
@@ -8,34 +13,251 @@ import (
 
 // Config stores all configuration of the applications
 type Config struct {
-	Loglevel string   `json:"loglevel" yaml:"loglevel"`
-	Logfile  string   `json:"logfile" yaml:"logfile"`
-	Database Database `json:"database" yaml:"database"`
-	Server   Server   `json:"server" yaml:"server"`
+	Loglevel string         `json:"loglevel" yaml:"loglevel"`
+	Logfile  string         `json:"logfile" yaml:"logfile"`
+	Database Database       `json:"database" yaml:"database"`
+	Server   Server         `json:"server" yaml:"server"`
+	Password PasswordPolicy `json:"password" yaml:"password"`
+	OIDC     OIDC           `json:"oidc" yaml:"oidc"`
+	Signup   Signup         `json:"signup" yaml:"signup"`
+	Tracing  Tracing        `json:"tracing" yaml:"tracing"`
 }
 
 type Server struct {
 	ServerAddress       string        `json:"serverAddress" yaml:"serverAddress"`
 	TokenSymmetricKey   string        `json:"tokenSymmetricKey" yaml:"tokenSymmetricKey"`
 	AccessTokenDuration time.Duration `json:"accessTokenDuration" yaml:"accessTokenDuration"`
+	// ShutdownTimeout is how long in-flight requests may take to drain on shutdown, defaults to 30s
+	ShutdownTimeout time.Duration `json:"shutdownTimeout" yaml:"shutdownTimeout"`
+	// ShutdownDelay is how long /readyz fails before the listener is closed on shutdown, so that
+	// load balancers stop routing requests to the server first, defaults to 5s
+	ShutdownDelay time.Duration `json:"shutdownDelay" yaml:"shutdownDelay"`
+	// ReadTimeout, ReadHeaderTimeout, WriteTimeout and IdleTimeout configure the http.Server, 0 disables them
+	ReadTimeout       time.Duration `json:"readTimeout" yaml:"readTimeout"`
+	ReadHeaderTimeout time.Duration `json:"readHeaderTimeout" yaml:"readHeaderTimeout"`
+	WriteTimeout      time.Duration `json:"writeTimeout" yaml:"writeTimeout"`
+	IdleTimeout       time.Duration `json:"idleTimeout" yaml:"idleTimeout"`
+	// HandlerTimeout cancels the context of a request, and of its database queries, once exceeded, 0 disables it
+	HandlerTimeout time.Duration `json:"handlerTimeout" yaml:"handlerTimeout"`
+	// MaxHeaderBytes limits the size of the request headers, 0 means http.DefaultMaxHeaderBytes
+	MaxHeaderBytes int `json:"maxHeaderBytes" yaml:"maxHeaderBytes"`
+	// MaxBodyBytes limits the size of request bodies, 0 disables the limit
+	MaxBodyBytes int64 `json:"maxBodyBytes" yaml:"maxBodyBytes"`
+	// TrustedProxies lists the IPs and CIDRs of the reverse proxies whose X-Forwarded-For header
+	// gives the client IP, the remote address is the client IP when it is empty
+	TrustedProxies []string  `json:"trustedProxies" yaml:"trustedProxies"`
+	CORS           CORS      `json:"cors" yaml:"cors"`
+	RateLimit      RateLimit `json:"rateLimit" yaml:"rateLimit"`
+	TLS            TLS       `json:"tls" yaml:"tls"`
+}
+
+// CORS stores the cross-origin resource sharing settings, no CORS headers are sent
+// when AllowedOrigins is empty
+type CORS struct {
+	// AllowedOrigins lists origins such as https://plam.example.com, "*" allows any origin
+	AllowedOrigins []string `json:"allowedOrigins" yaml:"allowedOrigins"`
+}
+
+// RateLimit limits the requests of every client IP, disabled when RequestsPerSecond is 0
+type RateLimit struct {
+	RequestsPerSecond float64 `json:"requestsPerSecond" yaml:"requestsPerSecond"`
+	Burst             int     `json:"burst" yaml:"burst"`
 }
 
 type Database struct {
 	DriverName     string `json:"driverName" yaml:"driverName"`
 	DataSourceName string `json:"dataSourceName" yaml:"dataSourceName"`
-	MigrationURL   string `json:"migrationURL" yaml:"migrationURL"`
+	// ReplicaDataSourceName connects to a read replica serving Count*, Filter*, Get*, List* and Search* queries,
+	// every query goes to the primary when it is empty
+	ReplicaDataSourceName string `json:"replicaDataSourceName" yaml:"replicaDataSourceName"`
+	// MigrationURL overrides the embedded migrations, e.g. file://db/migration
+	MigrationURL string `json:"migrationURL" yaml:"migrationURL"`
+	// AutoMigrate applies pending migrations when the server starts, defaults to true
+	AutoMigrate bool `json:"autoMigrate" yaml:"autoMigrate"`
+	Pool        Pool `json:"pool" yaml:"pool"`
+}
+
+// Pool stores the connection pool settings, zero values keep the pgxpool defaults
+type Pool struct {
+	MaxConns          int32         `json:"maxConns" yaml:"maxConns"`
+	MinConns          int32         `json:"minConns" yaml:"minConns"`
+	MaxConnLifetime   time.Duration `json:"maxConnLifetime" yaml:"maxConnLifetime"`
+	MaxConnIdleTime   time.Duration `json:"maxConnIdleTime" yaml:"maxConnIdleTime"`
+	HealthCheckPeriod time.Duration `json:"healthCheckPeriod" yaml:"healthCheckPeriod"`
+	// StatementTimeout aborts statements running longer, 0 disables it
+	StatementTimeout time.Duration `json:"statementTimeout" yaml:"statementTimeout"`
+}
+
+// OIDC stores the OpenID Connect single sign-on settings
+type OIDC struct {
+	Enabled              bool     `json:"enabled" yaml:"enabled"`
+	IssuerURL            string   `json:"issuerURL" yaml:"issuerURL"`
+	ClientID             string   `json:"clientID" yaml:"clientID"`
+	ClientSecret         string   `json:"clientSecret" yaml:"clientSecret"`
+	RedirectURL          string   `json:"redirectURL" yaml:"redirectURL"`
+	Scopes               []string `json:"scopes" yaml:"scopes"`
+	GroupsClaim          string   `json:"groupsClaim" yaml:"groupsClaim"`
+	AdminGroups          []string `json:"adminGroups" yaml:"adminGroups"`
+	UserGroups           []string `json:"userGroups" yaml:"userGroups"`
+	DisablePasswordLogin bool     `json:"disablePasswordLogin" yaml:"disablePasswordLogin"`
+}
+
+// Signup stores the public signup settings
+type Signup struct {
+	// Mode is one of SignupModeOpen, SignupModeInvite or SignupModeDisabled, empty means open
+	Mode               string        `json:"mode" yaml:"mode"`
+	InvitationDuration time.Duration `json:"invitationDuration" yaml:"invitationDuration"`
+}
+
+// Tracing stores the OpenTelemetry tracing settings
+type Tracing struct {
+	// Exporter is one of TracingExporterNone, TracingExporterOTLP, TracingExporterStdout or TracingExporterFile,
+	// empty means none
+	Exporter string `json:"exporter" yaml:"exporter"`
+	// Endpoint is the host:port of the OTLP gRPC collector, e.g. localhost:4317
+	Endpoint string `json:"endpoint" yaml:"endpoint"`
+	// Insecure connects to the OTLP collector without TLS
+	Insecure bool `json:"insecure" yaml:"insecure"`
+	// File is the file the spans are appended to by the file exporter
+	File string `json:"file" yaml:"file"`
+	// ServiceName names the service in the spans, defaults to plam
+	ServiceName string `json:"serviceName" yaml:"serviceName"`
+	// SampleRatio is the fraction of the traces started by the server which are sampled, defaults to 1
+	SampleRatio float64 `json:"sampleRatio" yaml:"sampleRatio"`
+}
+
+// envPrefix is prepended to the environment variables overriding config keys,
+// e.g. PLAM_DATABASE_DATASOURCENAME overrides database.dataSourceName
+const envPrefix = "PLAM"
+
+// envFileSuffix marks an environment variable holding the path of a file with the value,
+// e.g. PLAM_SERVER_TOKENSYMMETRICKEY_FILE=/run/secrets/token_key
+const envFileSuffix = "_FILE"
+
+// ConfigOptions selects where the configuration is read from
+type ConfigOptions struct {
+	// Path is the directory searched for config.yaml when File is empty,
+	// a missing config.yaml is not an error so that the config may come from the environment only
+	Path string
+	// File is the path of the config file, it must exist when set
+	File string
+	// Overrides maps config keys, e.g. "server.serverAddress", to values which
+	// take precedence over the config file and the environment
+	Overrides map[string]any
 }
 
 // LoadConfig reads configuration from file or environment variables
 func LoadConfig(path string) (config Config, err error) {
-	viper.AddConfigPath(path)
-	viper.SetConfigName("config")
-	viper.SetConfigType("yaml")
+	return LoadConfigWithOptions(ConfigOptions{Path: path})
+}
 
-	err = viper.ReadInConfig()
+// LoadConfigWithOptions reads the configuration, from lowest to highest precedence, from
+// defaults, the config file, PLAM_* environment variables, PLAM_*_FILE files and the overrides
+func LoadConfigWithOptions(opts ConfigOptions) (config Config, err error) {
+	v, err := newViper(opts)
 	if err != nil {
 		return
 	}
-	err = viper.Unmarshal(&config)
+
+	err = v.Unmarshal(&config)
 	return
 }
+
+// newViper creates a viper instance reading the sources selected by opts
+func newViper(opts ConfigOptions) (*viper.Viper, error) {
+	v := viper.New()
+	if opts.File != "" {
+		v.SetConfigFile(opts.File)
+	} else {
+		v.AddConfigPath(opts.Path)
+		v.SetConfigName("config")
+	}
+	v.SetConfigType("yaml")
+
+	v.SetDefault("loglevel", "info")
+	v.SetDefault("database.autoMigrate", true)
+	v.SetDefault("server.shutdownTimeout", 30*time.Second)
+	v.SetDefault("server.shutdownDelay", 5*time.Second)
+	v.SetDefault("server.readTimeout", 360*time.Second)
+	v.SetDefault("server.readHeaderTimeout", 10*time.Second)
+	v.SetDefault("server.writeTimeout", 360*time.Second)
+	v.SetDefault("server.idleTimeout", 720*time.Second)
+	v.SetDefault("server.handlerTimeout", 60*time.Second)
+	v.SetDefault("server.maxHeaderBytes", 1<<20)
+	v.SetDefault("server.maxBodyBytes", 1<<20)
+	v.SetDefault("tracing.serviceName", "plam")
+	v.SetDefault("tracing.sampleRatio", 1.0)
+
+	if err := v.ReadInConfig(); err != nil {
+		var notFound viper.ConfigFileNotFoundError
+		if opts.File != "" || !errors.As(err, &notFound) {
+			return nil, err
+		}
+	}
+
+	v.SetEnvPrefix(envPrefix)
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	for _, key := range configKeys(reflect.TypeOf(Config{}), "") {
+		if err := v.BindEnv(key); err != nil {
+			return nil, err
+		}
+		if err := bindEnvFile(v, key); err != nil {
+			return nil, err
+		}
+	}
+
+	for key, value := range opts.Overrides {
+		v.Set(key, value)
+	}
+
+	return v, nil
+}
+
+// bindEnvFile sets key from the content of the file named by its *_FILE environment variable
+func bindEnvFile(v *viper.Viper, key string) error {
+	name := envName(key)
+	file, ok := os.LookupEnv(name + envFileSuffix)
+	if !ok {
+		return nil
+	}
+	if _, ok = os.LookupEnv(name); ok {
+		return fmt.Errorf("both %s and %s are set", name, name+envFileSuffix)
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return fmt.Errorf("cannot read %s: %w", name+envFileSuffix, err)
+	}
+
+	// Secret files usually end with a newline
+	v.Set(key, strings.TrimRight(string(data), "\r\n"))
+	return nil
+}
+
+// envName returns the environment variable overriding key
+func envName(key string) string {
+	return envPrefix + "_" + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
+
+// configKeys lists the keys of every leaf field of t, named after the yaml tags
+func configKeys(t reflect.Type, prefix string) []string {
+	var keys []string
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		if name == "" {
+			name = field.Name
+		}
+		if name == "-" {
+			continue
+		}
+
+		if field.Type.Kind() == reflect.Struct {
+			keys = append(keys, configKeys(field.Type, prefix+name+".")...)
+			continue
+		}
+		keys = append(keys, prefix+name)
+	}
+	return keys
+}
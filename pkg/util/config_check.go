@@ -0,0 +1,254 @@
+package util
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// tokenSymmetricKeySize is the key size required by PASETO v2 local tokens
+const tokenSymmetricKeySize = 32
+
+// redacted replaces secrets in the output of Config.Redacted
+const redacted = "REDACTED"
+
+// LogLevels lists the accepted values of Config.Loglevel
+var LogLevels = []string{"debug", "info", "warn", "error"}
+
+// ConfigError lists every problem found in a configuration
+type ConfigError struct {
+	Problems []string `json:"problems"`
+}
+
+func (e *ConfigError) Error() string {
+	return fmt.Sprintf("invalid config: %s", strings.Join(e.Problems, "; "))
+}
+
+// Validate checks every field of the configuration and returns a *ConfigError listing all problems
+func (config Config) Validate() error {
+	var problems []string
+	addf := func(format string, args ...any) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	if !contains(LogLevels, config.Loglevel) {
+		addf("loglevel %q must be one of %s", config.Loglevel, strings.Join(LogLevels, ", "))
+	}
+
+	if config.Database.DataSourceName == "" {
+		addf("database.dataSourceName is required")
+	} else if _, err := pgxpool.ParseConfig(config.Database.DataSourceName); err != nil {
+		addf("database.dataSourceName cannot be parsed")
+	}
+	if config.Database.ReplicaDataSourceName != "" {
+		if _, err := pgxpool.ParseConfig(config.Database.ReplicaDataSourceName); err != nil {
+			addf("database.replicaDataSourceName cannot be parsed")
+		}
+	}
+	pool := config.Database.Pool
+	if pool.MaxConns < 0 || pool.MinConns < 0 {
+		addf("database.pool.maxConns and database.pool.minConns must not be negative")
+	} else if pool.MaxConns > 0 && pool.MinConns > pool.MaxConns {
+		addf("database.pool.minConns must not exceed database.pool.maxConns")
+	}
+	if pool.MaxConnLifetime < 0 || pool.MaxConnIdleTime < 0 || pool.HealthCheckPeriod < 0 || pool.StatementTimeout < 0 {
+		addf("database.pool durations must not be negative")
+	}
+	if config.Database.MigrationURL != "" {
+		if u, err := url.Parse(config.Database.MigrationURL); err != nil || u.Scheme == "" {
+			addf("database.migrationURL %q must be a URL such as file://db/migration", config.Database.MigrationURL)
+		}
+	}
+
+	if err := validateAddress(config.Server.ServerAddress); err != nil {
+		addf("server.serverAddress %q is invalid: %v", config.Server.ServerAddress, err)
+	}
+	if len(config.Server.TokenSymmetricKey) != tokenSymmetricKeySize {
+		addf("server.tokenSymmetricKey must be exactly %d characters long", tokenSymmetricKeySize)
+	}
+	if config.Server.AccessTokenDuration <= 0 {
+		addf("server.accessTokenDuration must be positive")
+	}
+	if config.Server.ShutdownTimeout <= 0 {
+		addf("server.shutdownTimeout must be positive")
+	}
+	if config.Server.ShutdownDelay < 0 {
+		addf("server.shutdownDelay must not be negative")
+	}
+	if config.Server.ReadTimeout < 0 || config.Server.ReadHeaderTimeout < 0 || config.Server.WriteTimeout < 0 ||
+		config.Server.IdleTimeout < 0 || config.Server.HandlerTimeout < 0 {
+		addf("server timeouts must not be negative")
+	}
+	if config.Server.HandlerTimeout > 0 && config.Server.WriteTimeout > 0 && config.Server.HandlerTimeout > config.Server.WriteTimeout {
+		addf("server.handlerTimeout must not exceed server.writeTimeout")
+	}
+	if config.Server.MaxHeaderBytes < 0 || config.Server.MaxBodyBytes < 0 {
+		addf("server.maxHeaderBytes and server.maxBodyBytes must not be negative")
+	}
+	for _, origin := range config.Server.CORS.AllowedOrigins {
+		if u, err := url.Parse(origin); origin != "*" && (err != nil || u.Scheme == "" || u.Host == "") {
+			addf("server.cors.allowedOrigins %q must be * or an origin such as https://plam.example.com", origin)
+		}
+	}
+	for _, proxy := range config.Server.TrustedProxies {
+		if _, _, err := net.ParseCIDR(proxy); err != nil && net.ParseIP(proxy) == nil {
+			addf("server.trustedProxies %q must be an IP or a CIDR", proxy)
+		}
+	}
+	if config.Server.RateLimit.RequestsPerSecond < 0 {
+		addf("server.rateLimit.requestsPerSecond must not be negative")
+	} else if config.Server.RateLimit.RequestsPerSecond > 0 && config.Server.RateLimit.Burst <= 0 {
+		addf("server.rateLimit.burst must be positive when rate limiting is enabled")
+	}
+
+	if config.Server.TLS.Enabled {
+		problems = append(problems, validateTLS(config.Server.TLS)...)
+	}
+
+	password := config.Password
+	if password.MinLength < 0 || password.MaxLength < 0 || password.HistorySize < 0 {
+		addf("password.minLength, password.maxLength and password.historySize must not be negative")
+	} else if password.minLength() > password.maxLength() {
+		addf("password.minLength %d is greater than the maximum length %d", password.minLength(), password.maxLength())
+	}
+	if password.BreachedListFile != "" {
+		if _, err := os.Stat(password.BreachedListFile); err != nil {
+			addf("password.breachedListFile: %v", err)
+		}
+	}
+
+	if config.OIDC.Enabled {
+		if u, err := url.Parse(config.OIDC.IssuerURL); err != nil || u.Scheme == "" || u.Host == "" {
+			addf("oidc.issuerURL must be an absolute URL")
+		}
+		if config.OIDC.ClientID == "" {
+			addf("oidc.clientID is required")
+		}
+		if u, err := url.Parse(config.OIDC.RedirectURL); err != nil || u.Scheme == "" || u.Host == "" {
+			addf("oidc.redirectURL must be an absolute URL")
+		}
+	}
+
+	switch config.Signup.Mode {
+	case "", SignupModeOpen, SignupModeInvite, SignupModeDisabled:
+	default:
+		addf("signup.mode %q must be one of %s, %s, %s", config.Signup.Mode, SignupModeOpen, SignupModeInvite, SignupModeDisabled)
+	}
+	if config.Signup.InvitationDuration < 0 {
+		addf("signup.invitationDuration must not be negative")
+	}
+
+	tracing := config.Tracing
+	switch tracing.Exporter {
+	case "", TracingExporterNone, TracingExporterStdout:
+	case TracingExporterOTLP:
+		if err := validateAddress(tracing.Endpoint); err != nil {
+			addf("tracing.endpoint %q is invalid: %v", tracing.Endpoint, err)
+		}
+	case TracingExporterFile:
+		if tracing.File == "" {
+			addf("tracing.file is required by the file exporter")
+		}
+	default:
+		addf("tracing.exporter %q must be one of %s", tracing.Exporter, strings.Join(TracingExporters, ", "))
+	}
+	if tracing.SampleRatio < 0 || tracing.SampleRatio > 1 {
+		addf("tracing.sampleRatio must be between 0 and 1")
+	}
+
+	if len(problems) > 0 {
+		return &ConfigError{Problems: problems}
+	}
+	return nil
+}
+
+func validateTLS(config TLS) []string {
+	var problems []string
+	if config.CertFile == "" || config.KeyFile == "" {
+		problems = append(problems, "server.tls.certFile and server.tls.keyFile are required when TLS is enabled")
+	}
+	if _, ok := tlsVersions[config.MinVersion]; config.MinVersion != "" && !ok {
+		problems = append(problems, fmt.Sprintf("server.tls.minVersion %q must be 1.2 or 1.3", config.MinVersion))
+	}
+	if _, err := parseCipherSuites(config.CipherSuites); err != nil {
+		problems = append(problems, fmt.Sprintf("server.tls.cipherSuites: %v", err))
+	}
+	switch config.ClientAuth {
+	case "", TLSClientAuthRequire, TLSClientAuthOptional:
+	default:
+		problems = append(problems, fmt.Sprintf("server.tls.clientAuth %q must be %s or %s", config.ClientAuth, TLSClientAuthRequire, TLSClientAuthOptional))
+	}
+	if config.RedirectAddress != "" {
+		if err := validateAddress(config.RedirectAddress); err != nil {
+			problems = append(problems, fmt.Sprintf("server.tls.redirectAddress %q is invalid: %v", config.RedirectAddress, err))
+		}
+	}
+
+	// Load the files once the settings are known to be valid
+	if len(problems) == 0 {
+		if _, err := NewTLSConfig(config); err != nil {
+			problems = append(problems, fmt.Sprintf("server.tls: %v", err))
+		}
+	}
+	return problems
+}
+
+func validateAddress(addr string) error {
+	_, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return err
+	}
+
+	n, err := strconv.Atoi(port)
+	if err != nil || n < 0 || n > 65535 {
+		return fmt.Errorf("invalid port %q", port)
+	}
+	return nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// dsnPassword matches the password of a keyword/value connection string
+var dsnPassword = regexp.MustCompile(`(password\s*=\s*)('(\\.|[^'])*'|\S+)`)
+
+// Redacted returns a copy of the configuration with secrets replaced, safe to print or log
+func (config Config) Redacted() Config {
+	if config.Server.TokenSymmetricKey != "" {
+		config.Server.TokenSymmetricKey = redacted
+	}
+	if config.OIDC.ClientSecret != "" {
+		config.OIDC.ClientSecret = redacted
+	}
+	config.Database.DataSourceName = redactDSN(config.Database.DataSourceName)
+	config.Database.ReplicaDataSourceName = redactDSN(config.Database.ReplicaDataSourceName)
+	return config
+}
+
+func redactDSN(dsn string) string {
+	if u, err := url.Parse(dsn); err == nil && u.Scheme != "" {
+		if _, ok := u.User.Password(); ok {
+			u.User = url.UserPassword(u.User.Username(), redacted)
+		}
+		q := u.Query()
+		if q.Has("password") {
+			q.Set("password", redacted)
+			u.RawQuery = q.Encode()
+		}
+		return u.String()
+	}
+
+	return dsnPassword.ReplaceAllString(dsn, "${1}"+redacted)
+}
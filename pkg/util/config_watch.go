@@ -0,0 +1,117 @@
+package util
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// ReloadableKeys lists the config keys which are applied without a restart
+var ReloadableKeys = []string{
+	"loglevel",
+	"server.accessTokenDuration",
+	"server.cors.allowedOrigins",
+	"server.rateLimit.requestsPerSecond",
+	"server.rateLimit.burst",
+}
+
+// WatchConfig watches the config file selected by opts and calls onChange with the
+// reloaded configuration, or the error which prevented loading it, on every change.
+// onChange is called from a background goroutine.
+func WatchConfig(opts ConfigOptions, onChange func(config Config, err error)) error {
+	v, err := newViper(opts)
+	if err != nil {
+		return err
+	}
+	if v.ConfigFileUsed() == "" {
+		return errors.New("no config file to watch")
+	}
+
+	v.OnConfigChange(func(fsnotify.Event) {
+		var config Config
+		err := v.Unmarshal(&config)
+		onChange(config, err)
+	})
+	v.WatchConfig()
+
+	return nil
+}
+
+// ChangedKeys returns the keys whose values differ between old and new
+func ChangedKeys(old, new Config) []string {
+	return changedKeys(reflect.ValueOf(old), reflect.ValueOf(new), "")
+}
+
+func changedKeys(old, new reflect.Value, prefix string) []string {
+	var keys []string
+	for i := 0; i < old.NumField(); i++ {
+		field := old.Type().Field(i)
+
+		name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		if name == "" {
+			name = field.Name
+		}
+		if name == "-" {
+			continue
+		}
+
+		if field.Type.Kind() == reflect.Struct {
+			keys = append(keys, changedKeys(old.Field(i), new.Field(i), prefix+name+".")...)
+			continue
+		}
+		if !equalValues(old.Field(i), new.Field(i)) {
+			keys = append(keys, prefix+name)
+		}
+	}
+	return keys
+}
+
+// RestartRequiredKeys returns the changed keys which only take effect after a restart
+func RestartRequiredKeys(old, new Config) []string {
+	var keys []string
+	for _, key := range ChangedKeys(old, new) {
+		if !contains(ReloadableKeys, key) {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// WithReloadable returns old with the reloadable keys set to their values in new, that is
+// the config running after new has been reloaded
+func WithReloadable(old, new Config) Config {
+	running := reflect.ValueOf(&old).Elem()
+	for _, key := range ReloadableKeys {
+		if dst, src := configField(running, key), configField(reflect.ValueOf(new), key); dst.IsValid() && src.IsValid() {
+			dst.Set(src)
+		}
+	}
+	return old
+}
+
+// configField returns the field of config named by a dotted key, or the zero Value
+func configField(config reflect.Value, key string) reflect.Value {
+	for _, name := range strings.Split(key, ".") {
+		found := false
+		for i := 0; i < config.NumField(); i++ {
+			if tag, _, _ := strings.Cut(config.Type().Field(i).Tag.Get("yaml"), ","); tag == name {
+				config, found = config.Field(i), true
+				break
+			}
+		}
+		if !found {
+			return reflect.Value{}
+		}
+	}
+	return config
+}
+
+// equalValues compares two config values, treating nil and empty slices as equal
+func equalValues(a, b reflect.Value) bool {
+	if a.Kind() == reflect.Slice && a.Len() == 0 && b.Len() == 0 {
+		return true
+	}
+	return reflect.DeepEqual(a.Interface(), b.Interface())
+}
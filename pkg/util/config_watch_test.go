@@ -0,0 +1,71 @@
+package util
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangedKeys(t *testing.T) {
+	old := validConfig()
+	require.Empty(t, ChangedKeys(old, old))
+
+	updated := old
+	updated.Loglevel = "debug"
+	updated.Server.ServerAddress = ":9090"
+	updated.Server.CORS.AllowedOrigins = []string{"https://plam.example.com"}
+	updated.OIDC.Scopes = []string{}
+
+	require.Equal(t, []string{"loglevel", "server.serverAddress", "server.cors.allowedOrigins"}, ChangedKeys(old, updated))
+	require.Equal(t, []string{"server.serverAddress"}, RestartRequiredKeys(old, updated))
+}
+
+func TestWithReloadable(t *testing.T) {
+	old := validConfig()
+
+	updated := old
+	updated.Loglevel = "debug"
+	updated.Server.ServerAddress = ":9090"
+	updated.Server.RateLimit.Burst = 42
+	updated.Server.CORS.AllowedOrigins = []string{"https://plam.example.com"}
+
+	running := WithReloadable(old, updated)
+	require.Equal(t, []string{"loglevel", "server.cors.allowedOrigins", "server.rateLimit.burst"}, ChangedKeys(old, running))
+	require.Equal(t, []string{"server.serverAddress"}, ChangedKeys(running, updated))
+
+	for _, key := range ReloadableKeys {
+		require.True(t, configField(reflect.ValueOf(old), key).IsValid(), key)
+	}
+}
+
+func TestWatchConfig(t *testing.T) {
+	dir := writeTestConfig(t)
+
+	changes := make(chan Config, 10)
+	err := WatchConfig(ConfigOptions{Path: dir}, func(config Config, err error) {
+		require.NoError(t, err)
+		changes <- config
+	})
+	require.NoError(t, err)
+
+	updated := strings.Replace(testConfig, "loglevel: info", "loglevel: debug", 1)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config.yaml"), []byte(updated), 0o600))
+
+	select {
+	case config := <-changes:
+		require.Equal(t, "debug", config.Loglevel)
+		require.Equal(t, ":8080", config.Server.ServerAddress)
+	case <-time.After(5 * time.Second):
+		t.Fatal("config change was not reported")
+	}
+}
+
+func TestWatchConfigWithoutFile(t *testing.T) {
+	err := WatchConfig(ConfigOptions{Path: t.TempDir()}, func(Config, error) {})
+	require.Error(t, err)
+}
@@ -2,21 +2,277 @@ package util
 
 import (
 	"errors"
+	"fmt"
+	"io"
+	"io/fs"
 
 	"github.com/golang-migrate/migrate/v4"
 	_ "github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/golang-migrate/migrate/v4/source"
 	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"github.com/lushenle/plam/db/migration"
+)
+
+const (
+	MigrationUp   = "up"
+	MigrationDown = "down"
 )
 
 func DBMigration(migrationURL, dbSource string) error {
-	migration, err := migrate.New(migrationURL, dbSource)
+	migrator, err := NewMigrator(migrationURL, dbSource)
 	if err != nil {
 		return err
 	}
+	defer migrator.Close()
+
+	return migrator.Up(0)
+}
+
+// MigrationFile is one version available in the migration source
+type MigrationFile struct {
+	Version    uint
+	Identifier string
+}
+
+// MigrationStep is a migration file applied in one direction
+type MigrationStep struct {
+	MigrationFile
+	Direction string
+}
+
+// Name returns the file name of the step, following the db/migration naming scheme
+func (step MigrationStep) Name() string {
+	return fmt.Sprintf("%06d_%s.%s.sql", step.Version, step.Identifier, step.Direction)
+}
+
+// Migrator manages the database schema version
+type Migrator struct {
+	migrate *migrate.Migrate
+	files   []MigrationFile
+}
+
+// NewMigrator creates a new Migrator reading migrations from migrationURL,
+// the migrations embedded in the binary are used when migrationURL is empty
+func NewMigrator(migrationURL, dbSource string) (*Migrator, error) {
+	src, err := openMigrationSource(migrationURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return newMigrator(src, dbSource)
+}
+
+// LatestMigrationVersion returns the highest version available in migrationURL,
+// or in the embedded migrations when migrationURL is empty
+func LatestMigrationVersion(migrationURL string) (uint, error) {
+	src, err := openMigrationSource(migrationURL)
+	if err != nil {
+		return 0, err
+	}
+	defer src.Close()
+
+	files, err := listMigrationFiles(src)
+	if err != nil {
+		return 0, err
+	}
+	if len(files) == 0 {
+		return 0, nil
+	}
+	return files[len(files)-1].Version, nil
+}
+
+func openMigrationSource(migrationURL string) (source.Driver, error) {
+	if migrationURL == "" {
+		return iofs.New(migration.FS, ".")
+	}
+	return source.Open(migrationURL)
+}
+
+func newMigrator(src source.Driver, dbSource string) (*Migrator, error) {
+	files, err := listMigrationFiles(src)
+	if err != nil {
+		src.Close()
+		return nil, err
+	}
+
+	m, err := migrate.NewWithSourceInstance("source", src, dbSource)
+	if err != nil {
+		src.Close()
+		return nil, err
+	}
+
+	return &Migrator{
+		migrate: m,
+		files:   files,
+	}, nil
+}
+
+// listMigrationFiles returns the migrations available in src in ascending version order
+func listMigrationFiles(src source.Driver) ([]MigrationFile, error) {
+	var files []MigrationFile
+
+	version, err := src.First()
+	for err == nil {
+		var r io.ReadCloser
+		var identifier string
+		r, identifier, err = src.ReadUp(version)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read migration %d: %w", version, err)
+		}
+		r.Close()
+
+		files = append(files, MigrationFile{Version: version, Identifier: identifier})
+		version, err = src.Next(version)
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	return files, nil
+}
+
+// Close closes the source and database connections
+func (m *Migrator) Close() error {
+	srcErr, dbErr := m.migrate.Close()
+	if srcErr != nil {
+		return srcErr
+	}
+	return dbErr
+}
 
-	if err = migration.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+// Files returns the migrations available in the source in ascending version order
+func (m *Migrator) Files() []MigrationFile {
+	return m.files
+}
+
+// Version returns the current schema version, 0 when no migration has been applied
+func (m *Migrator) Version() (version uint, dirty bool, err error) {
+	version, dirty, err = m.migrate.Version()
+	if errors.Is(err, migrate.ErrNilVersion) {
+		return 0, false, nil
+	}
+	return version, dirty, err
+}
+
+// Up applies limit pending migrations, or all of them when limit is not positive.
+// A limit beyond the pending migrations applies all of them, as PlanUp reports.
+func (m *Migrator) Up(limit int) error {
+	if limit <= 0 {
+		return ignoreNoChange(m.migrate.Up())
+	}
+	steps, err := m.PlanUp(limit)
+	if err != nil {
 		return err
 	}
+	return m.steps(len(steps))
+}
 
-	return nil
+// Down reverts the last n applied migrations, or all of them when n exceeds their count
+func (m *Migrator) Down(n int) error {
+	if n <= 0 {
+		return fmt.Errorf("number of migrations to revert must be positive, got %d", n)
+	}
+	steps, err := m.PlanDown(n)
+	if err != nil {
+		return err
+	}
+	return m.steps(-len(steps))
+}
+
+// steps runs n migrations up, or -n down, capped by the caller at the available count
+// because migrate.Steps fails with ErrShortLimit otherwise
+func (m *Migrator) steps(n int) error {
+	if n == 0 {
+		return nil
+	}
+	return ignoreNoChange(m.migrate.Steps(n))
+}
+
+// Goto migrates up or down to the given version
+func (m *Migrator) Goto(version uint) error {
+	return ignoreNoChange(m.migrate.Migrate(version))
+}
+
+// Force sets the schema version without running any migration and clears the dirty flag
+func (m *Migrator) Force(version int) error {
+	return m.migrate.Force(version)
+}
+
+// PlanUp returns the steps Up would apply
+func (m *Migrator) PlanUp(limit int) ([]MigrationStep, error) {
+	current, _, err := m.Version()
+	if err != nil {
+		return nil, err
+	}
+	return planUp(m.files, current, limit), nil
+}
+
+// PlanDown returns the steps Down would apply
+func (m *Migrator) PlanDown(n int) ([]MigrationStep, error) {
+	current, _, err := m.Version()
+	if err != nil {
+		return nil, err
+	}
+	return planDown(m.files, current, n), nil
+}
+
+// PlanGoto returns the steps Goto would apply
+func (m *Migrator) PlanGoto(version uint) ([]MigrationStep, error) {
+	current, _, err := m.Version()
+	if err != nil {
+		return nil, err
+	}
+	return planGoto(m.files, current, version), nil
+}
+
+func planUp(files []MigrationFile, current uint, limit int) []MigrationStep {
+	var steps []MigrationStep
+	for _, file := range files {
+		if file.Version <= current {
+			continue
+		}
+		if limit > 0 && len(steps) == limit {
+			break
+		}
+		steps = append(steps, MigrationStep{MigrationFile: file, Direction: MigrationUp})
+	}
+	return steps
+}
+
+func planDown(files []MigrationFile, current uint, n int) []MigrationStep {
+	var steps []MigrationStep
+	for i := len(files) - 1; i >= 0 && len(steps) < n; i-- {
+		if files[i].Version > current {
+			continue
+		}
+		steps = append(steps, MigrationStep{MigrationFile: files[i], Direction: MigrationDown})
+	}
+	return steps
+}
+
+func planGoto(files []MigrationFile, current, target uint) []MigrationStep {
+	var steps []MigrationStep
+	if target >= current {
+		for _, file := range files {
+			if file.Version > current && file.Version <= target {
+				steps = append(steps, MigrationStep{MigrationFile: file, Direction: MigrationUp})
+			}
+		}
+		return steps
+	}
+
+	for i := len(files) - 1; i >= 0; i-- {
+		if files[i].Version <= current && files[i].Version > target {
+			steps = append(steps, MigrationStep{MigrationFile: files[i], Direction: MigrationDown})
+		}
+	}
+	return steps
+}
+
+func ignoreNoChange(err error) error {
+	if errors.Is(err, migrate.ErrNoChange) {
+		return nil
+	}
+	return err
 }
@@ -0,0 +1,163 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/golang-migrate/migrate/v4/source"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"github.com/lushenle/plam/db/migration"
+	"github.com/stretchr/testify/require"
+)
+
+var testMigrationFiles = []MigrationFile{
+	{Version: 1, Identifier: "init_schema"},
+	{Version: 2, Identifier: "add_password_history"},
+	{Version: 3, Identifier: "add_user_identities"},
+}
+
+func stepNames(steps []MigrationStep) []string {
+	var names []string
+	for _, step := range steps {
+		names = append(names, step.Name())
+	}
+	return names
+}
+
+func TestListMigrationFiles(t *testing.T) {
+	src, err := source.Open("file://../../db/migration")
+	require.NoError(t, err)
+	defer src.Close()
+
+	files, err := listMigrationFiles(src)
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, len(files), 4)
+	require.Equal(t, MigrationFile{Version: 1, Identifier: "init_schema"}, files[0])
+
+	for i := 1; i < len(files); i++ {
+		require.Greater(t, files[i].Version, files[i-1].Version)
+	}
+}
+
+func TestEmbeddedMigrationFiles(t *testing.T) {
+	src, err := source.Open("file://../../db/migration")
+	require.NoError(t, err)
+	defer src.Close()
+
+	want, err := listMigrationFiles(src)
+	require.NoError(t, err)
+
+	embedded, err := iofs.New(migration.FS, ".")
+	require.NoError(t, err)
+	defer embedded.Close()
+
+	files, err := listMigrationFiles(embedded)
+	require.NoError(t, err)
+	require.Equal(t, want, files)
+}
+
+func TestLatestMigrationVersion(t *testing.T) {
+	embedded, err := LatestMigrationVersion("")
+	require.NoError(t, err)
+	require.NotZero(t, embedded)
+
+	version, err := LatestMigrationVersion("file://../../db/migration")
+	require.NoError(t, err)
+	require.Equal(t, embedded, version)
+}
+
+func TestPlanUp(t *testing.T) {
+	testCases := []struct {
+		name    string
+		current uint
+		limit   int
+		want    []string
+	}{
+		{
+			name:    "All",
+			current: 0,
+			want:    []string{"000001_init_schema.up.sql", "000002_add_password_history.up.sql", "000003_add_user_identities.up.sql"},
+		},
+		{
+			name:    "Limit",
+			current: 1,
+			limit:   1,
+			want:    []string{"000002_add_password_history.up.sql"},
+		},
+		{
+			name:    "NoChange",
+			current: 3,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.want, stepNames(planUp(testMigrationFiles, tc.current, tc.limit)))
+		})
+	}
+}
+
+func TestPlanDown(t *testing.T) {
+	testCases := []struct {
+		name    string
+		current uint
+		n       int
+		want    []string
+	}{
+		{
+			name:    "One",
+			current: 3,
+			n:       1,
+			want:    []string{"000003_add_user_identities.down.sql"},
+		},
+		{
+			name:    "MoreThanApplied",
+			current: 2,
+			n:       5,
+			want:    []string{"000002_add_password_history.down.sql", "000001_init_schema.down.sql"},
+		},
+		{
+			name:    "NoChange",
+			current: 0,
+			n:       1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.want, stepNames(planDown(testMigrationFiles, tc.current, tc.n)))
+		})
+	}
+}
+
+func TestPlanGoto(t *testing.T) {
+	testCases := []struct {
+		name    string
+		current uint
+		target  uint
+		want    []string
+	}{
+		{
+			name:    "Up",
+			current: 1,
+			target:  3,
+			want:    []string{"000002_add_password_history.up.sql", "000003_add_user_identities.up.sql"},
+		},
+		{
+			name:    "Down",
+			current: 3,
+			target:  1,
+			want:    []string{"000003_add_user_identities.down.sql", "000002_add_password_history.down.sql"},
+		},
+		{
+			name:    "NoChange",
+			current: 2,
+			target:  2,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.want, stepNames(planGoto(testMigrationFiles, tc.current, tc.target)))
+		})
+	}
+}
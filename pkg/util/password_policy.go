@@ -0,0 +1,281 @@
+package util
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	defaultPasswordMinLength = 6
+	defaultPasswordMaxLength = 32
+
+	// bcrypt ignores everything after the 72nd byte
+	bcryptMaxLength = 72
+
+	// minUserInfoLength is the length below which the username and the email local part
+	// are not looked for in passwords, shorter ones would reject most passwords
+	minUserInfoLength = 4
+
+	// breachedPrefixLength is the length of the SHA-1 hash prefix used to bucket breached hashes
+	breachedPrefixLength = 5
+)
+
+// PasswordPolicy stores the rules a password must satisfy
+type PasswordPolicy struct {
+	MinLength        int    `json:"minLength" yaml:"minLength"`
+	MaxLength        int    `json:"maxLength" yaml:"maxLength"`
+	RequireUpper     bool   `json:"requireUpper" yaml:"requireUpper"`
+	RequireLower     bool   `json:"requireLower" yaml:"requireLower"`
+	RequireDigit     bool   `json:"requireDigit" yaml:"requireDigit"`
+	RequireSymbol    bool   `json:"requireSymbol" yaml:"requireSymbol"`
+	DisallowUserInfo bool   `json:"disallowUserInfo" yaml:"disallowUserInfo"`
+	HistorySize      int    `json:"historySize" yaml:"historySize"`
+	BreachedListFile string `json:"breachedListFile" yaml:"breachedListFile"`
+}
+
+func (policy PasswordPolicy) minLength() int {
+	if policy.MinLength <= 0 {
+		return defaultPasswordMinLength
+	}
+	return policy.MinLength
+}
+
+func (policy PasswordPolicy) maxLength() int {
+	if policy.MaxLength <= 0 {
+		return defaultPasswordMaxLength
+	}
+	if policy.MaxLength > bcryptMaxLength {
+		return bcryptMaxLength
+	}
+	return policy.MaxLength
+}
+
+// Rules of the password policy, they identify a PasswordViolation
+const (
+	PasswordRuleMinLength = "min_length"
+	PasswordRuleMaxLength = "max_length"
+	PasswordRuleUpper     = "upper"
+	PasswordRuleLower     = "lower"
+	PasswordRuleDigit     = "digit"
+	PasswordRuleSymbol    = "symbol"
+	PasswordRuleUsername  = "username"
+	PasswordRuleEmail     = "email"
+	PasswordRuleBreached  = "breached"
+	PasswordRuleHistory   = "history"
+)
+
+// passwordRuleMessages are the English messages of the rules, {0} stands for the parameter
+var passwordRuleMessages = map[string]string{
+	PasswordRuleMinLength: "must be at least {0} characters long",
+	PasswordRuleMaxLength: "must be at most {0} characters long",
+	PasswordRuleUpper:     "must contain an upper case letter",
+	PasswordRuleLower:     "must contain a lower case letter",
+	PasswordRuleDigit:     "must contain a digit",
+	PasswordRuleSymbol:    "must contain a symbol",
+	PasswordRuleUsername:  "must not contain the username",
+	PasswordRuleEmail:     "must not contain the email address",
+	PasswordRuleBreached:  "has appeared in a data breach",
+	PasswordRuleHistory:   "must not reuse any of the last {0} passwords",
+}
+
+// PasswordViolation is a rule of the policy broken by a password
+type PasswordViolation struct {
+	Rule string `json:"rule"`
+	// Param is the parameter of the rule, such as the minimum length
+	Param string `json:"param,omitempty"`
+}
+
+// Template returns the English message of the rule, with {0} in place of the parameter
+func (v PasswordViolation) Template() string {
+	return passwordRuleMessages[v.Rule]
+}
+
+// Message returns the English message of the violation
+func (v PasswordViolation) Message() string {
+	return strings.ReplaceAll(v.Template(), "{0}", v.Param)
+}
+
+// PasswordPolicyError lists every rule a password violates
+type PasswordPolicyError struct {
+	Violations []PasswordViolation `json:"violations"`
+}
+
+func (e *PasswordPolicyError) Error() string {
+	messages := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		messages[i] = v.Message()
+	}
+	return fmt.Sprintf("password does not satisfy policy: %s", strings.Join(messages, "; "))
+}
+
+// BreachedPasswords is a set of SHA-1 hashes of known breached passwords,
+// bucketed by hash prefix in the same way as k-anonymity range lookups
+type BreachedPasswords struct {
+	ranges map[string]map[string]struct{}
+}
+
+// LoadBreachedPasswords reads a breached password list from a local file.
+// Each line holds an upper or lower case hex SHA-1 hash, optionally followed
+// by ":<count>" as in the Pwned Passwords downloads. Blank lines and lines
+// starting with "#" are ignored.
+func LoadBreachedPasswords(path string) (*BreachedPasswords, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open breached password list: %w", err)
+	}
+	defer file.Close()
+
+	breached := &BreachedPasswords{
+		ranges: make(map[string]map[string]struct{}),
+	}
+
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		hash, _, _ := strings.Cut(text, ":")
+		hash = strings.ToUpper(hash)
+		if len(hash) != sha1.Size*2 {
+			return nil, fmt.Errorf("invalid breached password hash on line %d", line)
+		}
+		if _, err = hex.DecodeString(hash); err != nil {
+			return nil, fmt.Errorf("invalid breached password hash on line %d: %w", line, err)
+		}
+
+		prefix, suffix := hash[:breachedPrefixLength], hash[breachedPrefixLength:]
+		if breached.ranges[prefix] == nil {
+			breached.ranges[prefix] = make(map[string]struct{})
+		}
+		breached.ranges[prefix][suffix] = struct{}{}
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read breached password list: %w", err)
+	}
+
+	return breached, nil
+}
+
+// Contains checks if the password appears in the breached password list
+func (breached *BreachedPasswords) Contains(password string) bool {
+	if breached == nil {
+		return false
+	}
+
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+
+	_, ok := breached.ranges[hash[:breachedPrefixLength]][hash[breachedPrefixLength:]]
+	return ok
+}
+
+// PasswordChecker validates passwords against a PasswordPolicy and an optional breached password list
+type PasswordChecker struct {
+	policy   PasswordPolicy
+	breached *BreachedPasswords
+}
+
+// NewPasswordChecker creates a new PasswordChecker, breached may be nil
+func NewPasswordChecker(policy PasswordPolicy, breached *BreachedPasswords) *PasswordChecker {
+	return &PasswordChecker{
+		policy:   policy,
+		breached: breached,
+	}
+}
+
+// HistorySize returns the number of previous password hashes that must not be reused
+func (checker *PasswordChecker) HistorySize() int {
+	if checker.policy.HistorySize < 0 {
+		return 0
+	}
+	return checker.policy.HistorySize
+}
+
+// Check checks the password against the policy and returns a *PasswordPolicyError
+// listing every violated rule
+func (checker *PasswordChecker) Check(password, username, email string) error {
+	var violations []PasswordViolation
+
+	length := utf8.RuneCountInString(password)
+	if length < checker.policy.minLength() {
+		violations = append(violations, PasswordViolation{Rule: PasswordRuleMinLength, Param: strconv.Itoa(checker.policy.minLength())})
+	}
+	if length > checker.policy.maxLength() || len(password) > bcryptMaxLength {
+		violations = append(violations, PasswordViolation{Rule: PasswordRuleMaxLength, Param: strconv.Itoa(checker.policy.maxLength())})
+	}
+
+	var hasUpper, hasLower, hasDigit, hasSymbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r):
+			hasSymbol = true
+		}
+	}
+	if checker.policy.RequireUpper && !hasUpper {
+		violations = append(violations, PasswordViolation{Rule: PasswordRuleUpper})
+	}
+	if checker.policy.RequireLower && !hasLower {
+		violations = append(violations, PasswordViolation{Rule: PasswordRuleLower})
+	}
+	if checker.policy.RequireDigit && !hasDigit {
+		violations = append(violations, PasswordViolation{Rule: PasswordRuleDigit})
+	}
+	if checker.policy.RequireSymbol && !hasSymbol {
+		violations = append(violations, PasswordViolation{Rule: PasswordRuleSymbol})
+	}
+
+	if checker.policy.DisallowUserInfo {
+		lower := strings.ToLower(password)
+		if containsUserInfo(lower, username) {
+			violations = append(violations, PasswordViolation{Rule: PasswordRuleUsername})
+		}
+		local, _, _ := strings.Cut(email, "@")
+		if containsUserInfo(lower, local) {
+			violations = append(violations, PasswordViolation{Rule: PasswordRuleEmail})
+		}
+	}
+
+	if checker.breached.Contains(password) {
+		violations = append(violations, PasswordViolation{Rule: PasswordRuleBreached})
+	}
+
+	if len(violations) > 0 {
+		return &PasswordPolicyError{Violations: violations}
+	}
+
+	return nil
+}
+
+// containsUserInfo reports whether the lower case password contains info, info shorter
+// than minUserInfoLength is ignored
+func containsUserInfo(password, info string) bool {
+	return utf8.RuneCountInString(info) >= minUserInfoLength && strings.Contains(password, strings.ToLower(info))
+}
+
+// CheckHistory checks that the password does not match any of the given bcrypt hashes
+func (checker *PasswordChecker) CheckHistory(password string, hashedPasswords []string) error {
+	for _, hashedPassword := range hashedPasswords {
+		if CheckPassword(password, hashedPassword) == nil {
+			return &PasswordPolicyError{
+				Violations: []PasswordViolation{{Rule: PasswordRuleHistory, Param: strconv.Itoa(checker.HistorySize())}},
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,151 @@
+package util
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func writeBreachedList(t *testing.T, passwords ...string) string {
+	var sb strings.Builder
+	sb.WriteString("# breached passwords\n")
+	for i, password := range passwords {
+		sum := sha1.Sum([]byte(password))
+		sb.WriteString(fmt.Sprintf("%s:%d\n", strings.ToUpper(hex.EncodeToString(sum[:])), i+1))
+	}
+
+	path := filepath.Join(t.TempDir(), "breached.txt")
+	require.NoError(t, os.WriteFile(path, []byte(sb.String()), 0o600))
+	return path
+}
+
+func requireViolations(t *testing.T, err error, count int) {
+	require.Error(t, err)
+
+	var policyErr *PasswordPolicyError
+	require.ErrorAs(t, err, &policyErr)
+	require.Len(t, policyErr.Violations, count)
+}
+
+func TestPasswordCheckerDefaults(t *testing.T) {
+	checker := NewPasswordChecker(PasswordPolicy{}, nil)
+
+	require.NoError(t, checker.Check(RandomString(6), RandomString(6), RandomEmail()))
+	requireViolations(t, checker.Check("abc", "", ""), 1)
+	requireViolations(t, checker.Check(RandomString(33), "", ""), 1)
+	require.Zero(t, checker.HistorySize())
+}
+
+func TestPasswordPolicyError(t *testing.T) {
+	checker := NewPasswordChecker(PasswordPolicy{MinLength: 8, RequireDigit: true}, nil)
+
+	err := checker.Check("abc", "", "")
+	var policyErr *PasswordPolicyError
+	require.ErrorAs(t, err, &policyErr)
+	require.Equal(t, []PasswordViolation{
+		{Rule: PasswordRuleMinLength, Param: "8"},
+		{Rule: PasswordRuleDigit},
+	}, policyErr.Violations)
+	require.Equal(t, "must be at least 8 characters long", policyErr.Violations[0].Message())
+	require.EqualError(t, err, "password does not satisfy policy: must be at least 8 characters long; must contain a digit")
+}
+
+func TestPasswordCheckerPolicy(t *testing.T) {
+	policy := PasswordPolicy{
+		MinLength:        8,
+		MaxLength:        64,
+		RequireUpper:     true,
+		RequireLower:     true,
+		RequireDigit:     true,
+		RequireSymbol:    true,
+		DisallowUserInfo: true,
+		HistorySize:      3,
+	}
+	checker := NewPasswordChecker(policy, nil)
+
+	testCases := []struct {
+		name       string
+		password   string
+		violations int
+	}{
+		{name: "OK", password: "Secret#2024", violations: 0},
+		{name: "TooShort", password: "Se#2", violations: 1},
+		{name: "TooLong", password: "Se#2" + strings.Repeat("a", 61), violations: 1},
+		{name: "NoUpper", password: "secret#2024", violations: 1},
+		{name: "NoLower", password: "SECRET#2024", violations: 1},
+		{name: "NoDigit", password: "Secret#word", violations: 1},
+		{name: "NoSymbol", password: "Secret2024", violations: 1},
+		{name: "ContainsUsername", password: "Johndoe#2024", violations: 1},
+		{name: "ContainsEmail", password: "Jdoe@mail#1", violations: 1},
+		{name: "Multiple", password: "abc", violations: 4},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := checker.Check(tc.password, "johndoe", "jdoe@example.com")
+			if tc.violations == 0 {
+				require.NoError(t, err)
+				return
+			}
+			requireViolations(t, err, tc.violations)
+		})
+	}
+
+	require.Equal(t, 3, checker.HistorySize())
+}
+
+func TestPasswordCheckerShortUserInfo(t *testing.T) {
+	checker := NewPasswordChecker(PasswordPolicy{DisallowUserInfo: true}, nil)
+
+	// Usernames and email local parts too short to be meaningful are not looked for
+	require.NoError(t, checker.Check("abcdef", "a", "x@example.com"))
+	require.NoError(t, checker.Check("bobcat", "bob", "bob@example.com"))
+
+	requireViolations(t, checker.Check("xjohn1", "john", "x@example.com"), 1)
+	requireViolations(t, checker.Check("Mary2024", "x", "mary@example.com"), 1)
+}
+
+func TestPasswordCheckerBreached(t *testing.T) {
+	path := writeBreachedList(t, "password123", "qwerty")
+
+	breached, err := LoadBreachedPasswords(path)
+	require.NoError(t, err)
+	require.True(t, breached.Contains("password123"))
+	require.True(t, breached.Contains("qwerty"))
+	require.False(t, breached.Contains(RandomString(12)))
+
+	checker := NewPasswordChecker(PasswordPolicy{}, breached)
+	requireViolations(t, checker.Check("password123", "", ""), 1)
+	require.NoError(t, checker.Check(RandomString(12), "", ""))
+}
+
+func TestLoadBreachedPasswordsInvalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "breached.txt")
+	require.NoError(t, os.WriteFile(path, []byte("not-a-hash:1\n"), 0o600))
+
+	_, err := LoadBreachedPasswords(path)
+	require.Error(t, err)
+
+	_, err = LoadBreachedPasswords(filepath.Join(t.TempDir(), "missing.txt"))
+	require.Error(t, err)
+}
+
+func TestPasswordCheckerHistory(t *testing.T) {
+	checker := NewPasswordChecker(PasswordPolicy{HistorySize: 2}, nil)
+
+	password := RandomString(8)
+	hashedPassword, err := HashPassword(password)
+	require.NoError(t, err)
+
+	otherHashedPassword, err := HashPassword(RandomString(8))
+	require.NoError(t, err)
+
+	requireViolations(t, checker.CheckHistory(password, []string{otherHashedPassword, hashedPassword}), 1)
+	require.NoError(t, checker.CheckHistory(RandomString(10), []string{otherHashedPassword, hashedPassword}))
+}
@@ -1,6 +1,9 @@
 package util
 
 import (
+	crand "crypto/rand"
+	"encoding/base64"
+	"fmt"
 	"math/rand"
 	"strings"
 )
@@ -33,3 +36,13 @@ func RandomEmail() string {
 func RandomFloat32(min, max float32) float32 {
 	return min + rand.Float32()*(max-min)
 }
+
+// RandomToken generates a cryptographically secure URL-safe token from n random bytes
+func RandomToken(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := crand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate random token: %w", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,7 @@
+package util
+
+const (
+	SignupModeOpen     = "open"
+	SignupModeInvite   = "invite"
+	SignupModeDisabled = "disabled"
+)
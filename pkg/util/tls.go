@@ -0,0 +1,200 @@
+package util
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	TLSClientAuthRequire  = "require"
+	TLSClientAuthOptional = "optional"
+)
+
+// certCheckInterval is how often the certificate files are checked for changes
+const certCheckInterval = 10 * time.Second
+
+// tlsVersions maps the accepted values of TLS.MinVersion to crypto/tls versions
+var tlsVersions = map[string]uint16{
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// TLS stores the HTTPS settings of the server
+type TLS struct {
+	Enabled  bool   `json:"enabled" yaml:"enabled"`
+	CertFile string `json:"certFile" yaml:"certFile"`
+	KeyFile  string `json:"keyFile" yaml:"keyFile"`
+	// MinVersion is 1.2 or 1.3, defaults to 1.2
+	MinVersion string `json:"minVersion" yaml:"minVersion"`
+	// CipherSuites restricts the TLS 1.2 cipher suites, e.g. TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+	// the Go defaults are used when empty. TLS 1.3 suites are not configurable.
+	CipherSuites []string `json:"cipherSuites" yaml:"cipherSuites"`
+	// ClientCAFile enables client certificate verification against the CA bundle
+	ClientCAFile string `json:"clientCAFile" yaml:"clientCAFile"`
+	// ClientAuth is require or optional, defaults to require when ClientCAFile is set
+	ClientAuth string `json:"clientAuth" yaml:"clientAuth"`
+	// RedirectAddress starts a plain HTTP listener redirecting to HTTPS, e.g. :80
+	RedirectAddress string `json:"redirectAddress" yaml:"redirectAddress"`
+}
+
+// NewTLSConfig creates the server TLS configuration, the certificate is reloaded when its files change
+func NewTLSConfig(config TLS) (*tls.Config, error) {
+	minVersion := uint16(tls.VersionTLS12)
+	if config.MinVersion != "" {
+		version, ok := tlsVersions[config.MinVersion]
+		if !ok {
+			return nil, fmt.Errorf("unsupported TLS version %q", config.MinVersion)
+		}
+		minVersion = version
+	}
+
+	cipherSuites, err := parseCipherSuites(config.CipherSuites)
+	if err != nil {
+		return nil, err
+	}
+
+	reloader, err := NewCertReloader(config.CertFile, config.KeyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion:     minVersion,
+		CipherSuites:   cipherSuites,
+		GetCertificate: reloader.GetCertificate,
+	}
+
+	if config.ClientCAFile != "" {
+		pem, err := os.ReadFile(config.ClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read client CA bundle: %w", err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, errors.New("client CA bundle contains no certificate")
+		}
+		tlsConfig.ClientCAs = pool
+
+		switch config.ClientAuth {
+		case "", TLSClientAuthRequire:
+			tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+		case TLSClientAuthOptional:
+			tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+		default:
+			return nil, fmt.Errorf("unsupported client auth %q", config.ClientAuth)
+		}
+	}
+
+	return tlsConfig, nil
+}
+
+// parseCipherSuites looks up the IDs of secure cipher suites by name
+func parseCipherSuites(names []string) ([]uint16, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	suites := make(map[string]uint16)
+	for _, suite := range tls.CipherSuites() {
+		suites[suite.Name] = suite.ID
+	}
+
+	ids := make([]uint16, 0, len(names))
+	for _, name := range names {
+		id, ok := suites[name]
+		if !ok {
+			return nil, fmt.Errorf("unsupported or insecure cipher suite %q", name)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// CertReloader serves a certificate and reloads it when the certificate or key file changes,
+// so that renewed certificates are picked up without a restart
+type CertReloader struct {
+	certFile string
+	keyFile  string
+
+	mu        sync.Mutex
+	cert      *tls.Certificate
+	modTime   time.Time
+	lastCheck time.Time
+}
+
+// NewCertReloader loads the certificate and key files
+func NewCertReloader(certFile, keyFile string) (*CertReloader, error) {
+	reloader := &CertReloader{
+		certFile: certFile,
+		keyFile:  keyFile,
+	}
+	if err := reloader.Reload(); err != nil {
+		return nil, err
+	}
+	return reloader, nil
+}
+
+// Reload loads the certificate and key files
+func (r *CertReloader) Reload() error {
+	modTime, err := r.latestModTime()
+	if err != nil {
+		return err
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return fmt.Errorf("cannot load certificate: %w", err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cert = &cert
+	r.modTime = modTime
+	r.lastCheck = time.Now()
+	return nil
+}
+
+// GetCertificate implements tls.Config.GetCertificate. The files are checked at most
+// every certCheckInterval, the previous certificate is kept when the new one cannot be loaded.
+func (r *CertReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mu.Lock()
+	cert := r.cert
+	check := time.Since(r.lastCheck) >= certCheckInterval
+	if check {
+		r.lastCheck = time.Now()
+	}
+	modTime := r.modTime
+	r.mu.Unlock()
+
+	if check {
+		if latest, err := r.latestModTime(); err == nil && latest.After(modTime) {
+			if err = r.Reload(); err == nil {
+				r.mu.Lock()
+				cert = r.cert
+				r.mu.Unlock()
+			}
+		}
+	}
+
+	return cert, nil
+}
+
+func (r *CertReloader) latestModTime() (time.Time, error) {
+	var latest time.Time
+	for _, file := range []string{r.certFile, r.keyFile} {
+		info, err := os.Stat(file)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("cannot read certificate: %w", err)
+		}
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+	return latest, nil
+}
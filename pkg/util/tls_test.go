@@ -0,0 +1,146 @@
+package util
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/lushenle/plam/pkg/util/tlstest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTLSConfig(t *testing.T) {
+	dir := t.TempDir()
+	ca := tlstest.NewCA(t)
+	certFile, keyFile := ca.IssueFiles(t, dir, "localhost")
+	caFile := ca.WriteCAFile(t, dir)
+
+	testCases := []struct {
+		name          string
+		config        TLS
+		checkResponse func(t *testing.T, tlsConfig *tls.Config, err error)
+	}{
+		{
+			name:   "Defaults",
+			config: TLS{CertFile: certFile, KeyFile: keyFile},
+			checkResponse: func(t *testing.T, tlsConfig *tls.Config, err error) {
+				require.NoError(t, err)
+				require.Equal(t, uint16(tls.VersionTLS12), tlsConfig.MinVersion)
+				require.Nil(t, tlsConfig.CipherSuites)
+				require.Equal(t, tls.NoClientCert, tlsConfig.ClientAuth)
+
+				cert, err := tlsConfig.GetCertificate(nil)
+				require.NoError(t, err)
+				require.NotNil(t, cert)
+			},
+		},
+		{
+			name: "MinVersionAndCipherSuites",
+			config: TLS{
+				CertFile:     certFile,
+				KeyFile:      keyFile,
+				MinVersion:   "1.3",
+				CipherSuites: []string{"TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256"},
+			},
+			checkResponse: func(t *testing.T, tlsConfig *tls.Config, err error) {
+				require.NoError(t, err)
+				require.Equal(t, uint16(tls.VersionTLS13), tlsConfig.MinVersion)
+				require.Equal(t, []uint16{tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256}, tlsConfig.CipherSuites)
+			},
+		},
+		{
+			name:   "ClientCA",
+			config: TLS{CertFile: certFile, KeyFile: keyFile, ClientCAFile: caFile},
+			checkResponse: func(t *testing.T, tlsConfig *tls.Config, err error) {
+				require.NoError(t, err)
+				require.Equal(t, tls.RequireAndVerifyClientCert, tlsConfig.ClientAuth)
+				require.NotNil(t, tlsConfig.ClientCAs)
+			},
+		},
+		{
+			name:   "ClientCAOptional",
+			config: TLS{CertFile: certFile, KeyFile: keyFile, ClientCAFile: caFile, ClientAuth: TLSClientAuthOptional},
+			checkResponse: func(t *testing.T, tlsConfig *tls.Config, err error) {
+				require.NoError(t, err)
+				require.Equal(t, tls.VerifyClientCertIfGiven, tlsConfig.ClientAuth)
+			},
+		},
+		{
+			name:   "UnsupportedVersion",
+			config: TLS{CertFile: certFile, KeyFile: keyFile, MinVersion: "1.0"},
+			checkResponse: func(t *testing.T, tlsConfig *tls.Config, err error) {
+				require.Error(t, err)
+			},
+		},
+		{
+			name:   "InsecureCipherSuite",
+			config: TLS{CertFile: certFile, KeyFile: keyFile, CipherSuites: []string{"TLS_RSA_WITH_RC4_128_SHA"}},
+			checkResponse: func(t *testing.T, tlsConfig *tls.Config, err error) {
+				require.ErrorContains(t, err, "TLS_RSA_WITH_RC4_128_SHA")
+			},
+		},
+		{
+			name:   "MissingCertificate",
+			config: TLS{CertFile: filepath.Join(dir, "missing.pem"), KeyFile: keyFile},
+			checkResponse: func(t *testing.T, tlsConfig *tls.Config, err error) {
+				require.Error(t, err)
+			},
+		},
+		{
+			name:   "InvalidClientCA",
+			config: TLS{CertFile: certFile, KeyFile: keyFile, ClientCAFile: keyFile},
+			checkResponse: func(t *testing.T, tlsConfig *tls.Config, err error) {
+				require.Error(t, err)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tlsConfig, err := NewTLSConfig(tc.config)
+			tc.checkResponse(t, tlsConfig, err)
+		})
+	}
+}
+
+func TestCertReloader(t *testing.T) {
+	dir := t.TempDir()
+	ca := tlstest.NewCA(t)
+	certFile, keyFile := ca.IssueFiles(t, dir, "first")
+
+	reloader, err := NewCertReloader(certFile, keyFile)
+	require.NoError(t, err)
+	requireCertCommonName(t, reloader, "first")
+
+	// Renew the certificate, the files are checked again once certCheckInterval has passed
+	ca.IssueFiles(t, dir, "second")
+	future := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(certFile, future, future))
+	requireCertCommonName(t, reloader, "first")
+
+	reloader.mu.Lock()
+	reloader.lastCheck = time.Now().Add(-certCheckInterval)
+	reloader.mu.Unlock()
+	requireCertCommonName(t, reloader, "second")
+
+	// A broken certificate keeps the previous one
+	require.NoError(t, os.WriteFile(certFile, []byte("broken"), 0o600))
+	future = future.Add(time.Minute)
+	require.NoError(t, os.Chtimes(certFile, future, future))
+	reloader.mu.Lock()
+	reloader.lastCheck = time.Now().Add(-certCheckInterval)
+	reloader.mu.Unlock()
+	requireCertCommonName(t, reloader, "second")
+}
+
+func requireCertCommonName(t *testing.T, reloader *CertReloader, commonName string) {
+	cert, err := reloader.GetCertificate(nil)
+	require.NoError(t, err)
+
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	require.NoError(t, err)
+	require.Equal(t, commonName, leaf.Subject.CommonName)
+}
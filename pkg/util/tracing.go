@@ -0,0 +1,11 @@
+package util
+
+const (
+	TracingExporterNone   = "none"
+	TracingExporterOTLP   = "otlp"
+	TracingExporterStdout = "stdout"
+	TracingExporterFile   = "file"
+)
+
+// TracingExporters lists the accepted values of Tracing.Exporter
+var TracingExporters = []string{TracingExporterNone, TracingExporterOTLP, TracingExporterStdout, TracingExporterFile}